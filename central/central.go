@@ -1,6 +1,7 @@
 package central
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"encoding/json"
@@ -8,12 +9,15 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/pkg/badge"
 	"github.com/k1LoW/octocov/report"
@@ -22,20 +26,90 @@ import (
 //go:embed index.md.tmpl
 var indexTmpl []byte
 
+// DefaultBadgesLayout is the default template of the badge path relative to the badges directory
+const DefaultBadgesLayout = "{{ .Repository }}/{{ .Metric }}.svg"
+
+const (
+	badgeMetricCoverage = "coverage"
+	badgeMetricRatio    = "ratio"
+	badgeMetricTime     = "time"
+	// how long since the last report
+	badgeMetricFreshness = "freshness"
+)
+
+const defaultFreshnessLabel = "last report"
+
+// OverallBadge is the path of the overall coverage badge relative to the badges directory
+const OverallBadge = "overall.svg"
+
+const (
+	// the mean of the coverages of the repositories
+	OverallWeightMean = "mean"
+	// the total coverage of the repositories weighted by the number of the code ( statements or lines )
+	OverallWeightLOC = "loc"
+)
+
+const defaultOverallLabel = "coverage"
+
+// Orders of the repositories in the index
+const (
+	IndexSortName     = "name"
+	IndexSortCoverage = "coverage"
+	IndexSortUpdated  = "updated"
+)
+
+const (
+	IndexGroupByOwner = "owner"
+	indexGroupOthers  = "Others"
+)
+
 type Central struct {
 	config  *CentralConfig
 	reports []*report.Report
+	// where the collected report of the repository is read from
+	sources map[string]reportSource
+}
+
+type reportSource struct {
+	// index of CentralConfig.Reports
+	fsys int
+	path string
 }
 
 type CentralConfig struct {
-	Repository             string
-	Wd                     string
-	Index                  string
-	Badges                 string
-	Reports                []fs.FS
+	Repository       string
+	Wd               string
+	Index            string
+	Badges           string
+	BadgesLayout     string
+	RepoLinkTemplate string
+	Reports          []fs.FS
+	// collect only the most recent reports when ReportsLimit > 0
+	ReportsLimit int
+	// glob patterns of the repositories to collect ( all when IncludeRepositories is empty )
+	IncludeRepositories []string
+	ExcludeRepositories []string
+	// IndexSortName ( default ), IndexSortCoverage or IndexSortUpdated
+	IndexSort string
+	// list only the IndexTop repositories with the lowest coverage in the index when IndexTop > 0
+	IndexTop int
+	// IndexGroupByOwner, or the regexp of the repository whose first submatch is the group name
+	IndexGroupBy string
+	// link the reports by the signed URLs when ReportURLSigners[i] of Reports[i] is set
+	ReportURLSigners       []func(ctx context.Context, path string) (string, error)
 	CoverageColor          func(cover float64) string
 	CodeToTestRatioColor   func(ratio float64) string
 	TestExecutionTimeColor func(d time.Duration) string
+	// generate freshness badges when FreshnessColor is set
+	FreshnessLabel string
+	FreshnessColor func(age time.Duration) string
+	// generate the overall coverage badge when OverallWeight is set ( OverallWeightMean or OverallWeightLOC )
+	OverallLabel  string
+	OverallWeight string
+	// write the dashboard JSON of the collected reports to JSON when it is set
+	JSON string
+	// mark the repositories whose report is older than StaleAfter in the index when StaleAfter > 0
+	StaleAfter time.Duration
 }
 
 func New(c *CentralConfig) *Central {
@@ -57,28 +131,223 @@ func (c *Central) Generate(ctx context.Context) ([]string, error) {
 	}
 
 	// render index
+	p := c.indexPath()
+	i, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	if err := c.renderIndex(i); err != nil {
+		return nil, err
+	}
+	paths = append(paths, p)
+
+	// write dashboard JSON
+	if c.config.JSON != "" {
+		jp, err := c.generateDashboard()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, jp)
+	}
+
+	return paths, nil
+}
+
+// GenerateOnly regenerates the badges of the repository and patches its row in the index.
+// If the index can not be patched incrementally, it falls back to Generate.
+func (c *Central) GenerateOnly(ctx context.Context, repository string) ([]string, error) {
+	r, err := c.findReport(repository)
+	if err != nil {
+		return nil, err
+	}
+	if reason, ok := c.needsFullGeneration(); ok {
+		return c.fallbackToGenerate(ctx, reason)
+	}
+	c.reports = []*report.Report{r}
+
+	// generate badges
+	paths, err := c.generateBadges()
+	if err != nil {
+		return nil, err
+	}
+
+	// patch index
+	p := c.indexPath()
+	current, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		return c.fallbackToGenerate(ctx, err.Error())
+	}
+	buf := new(bytes.Buffer)
+	if err := c.renderIndex(buf); err != nil {
+		return nil, err
+	}
+	patched, ok := patchIndexRow(current, buf.Bytes(), repository)
+	if !ok {
+		return c.fallbackToGenerate(ctx, "the index can not be patched incrementally")
+	}
+	if err := os.WriteFile(p, patched, 0644); err != nil { // #nosec
+		return nil, err
+	}
+	paths = append(paths, p)
+
+	// patch dashboard JSON
+	if c.config.JSON != "" {
+		jp, ok, err := c.patchDashboard(r)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return c.fallbackToGenerate(ctx, "the dashboard JSON can not be patched")
+		}
+		paths = append(paths, jp)
+	}
+	return paths, nil
+}
+
+// needsFullGeneration returns the reason why the index can not be patched by the report of the one repository.
+func (c *Central) needsFullGeneration() (string, bool) {
+	switch {
+	case !c.sortedByName():
+		// the rows of the other repositories may be reordered or dropped
+		return "the index is not sorted by name", true
+	case c.config.IndexGroupBy != "":
+		// the summary rows of the groups are changed
+		return "the index is grouped", true
+	case c.config.StaleAfter > 0:
+		// the other repositories may become stale
+		return "the stale repositories are marked", true
+	case c.config.OverallWeight != "":
+		// the overall badge depends on the reports of all the repositories
+		return "the overall badge is generated", true
+	case c.config.ReportsLimit > 0:
+		// the repository may fall in or out of the most recent reports
+		return "the reports are limited", true
+	}
+	return "", false
+}
+
+func (c *Central) fallbackToGenerate(ctx context.Context, reason string) ([]string, error) {
+	_, _ = fmt.Fprintf(os.Stderr, "Fall back to full generation: %s\n", reason)
+	c.reports = nil
+	return c.Generate(ctx)
+}
+
+func (c *Central) indexPath() string {
 	p := c.config.Index
 	fi, err := os.Stat(c.config.Index)
 	if err == nil && fi.IsDir() {
 		p = filepath.Join(c.config.Index, "README.md")
 	}
-	i, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	return p
+}
+
+// findReport finds the latest report of the repository.
+// It reads owner/repo/report.json of each datastore first, and scans all reports only if it is not found.
+func (c *Central) findReport(repository string) (*report.Report, error) {
+	ok, err := c.collectable(repository)
 	if err != nil {
 		return nil, err
 	}
-	if err := c.renderIndex(i); err != nil {
+	if !ok {
+		return nil, fmt.Errorf("report of %s is not collected by the include/exclude patterns", repository)
+	}
+	var found *report.Report
+	for _, fsys := range c.config.Reports {
+		b, err := fs.ReadFile(fsys, fmt.Sprintf("%s/report.json", repository))
+		if err != nil {
+			continue
+		}
+		r := &report.Report{}
+		if err := json.Unmarshal(b, r); err != nil || r.Repository != repository {
+			continue
+		}
+		if found == nil || found.Timestamp.UnixNano() < r.Timestamp.UnixNano() {
+			found = r
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+	if err := c.collectReports(); err != nil {
 		return nil, err
 	}
-	paths = append(paths, p)
+	for _, r := range c.reports {
+		if r.Repository == repository {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("report of %s is not found", repository)
+}
 
-	return paths, nil
+const indexTableHeaderPrefix = "| Repository |"
+
+// patchIndexRow replaces ( or inserts in sorted order ) the row of the repository in the current index with the row in the rendered index.
+// It returns false if the table header of the current index does not match the rendered one.
+func patchIndexRow(current, rendered []byte, repository string) ([]byte, bool) {
+	rowPrefix := fmt.Sprintf("| [%s](", repository)
+	rlines := strings.Split(string(rendered), "\n")
+	var header, row string
+	for i, l := range rlines {
+		if strings.HasPrefix(l, indexTableHeaderPrefix) && i+1 < len(rlines) {
+			header = l + "\n" + rlines[i+1]
+		}
+		if strings.HasPrefix(l, rowPrefix) {
+			row = l
+		}
+	}
+	if header == "" || row == "" {
+		return nil, false
+	}
+	lines := strings.Split(string(current), "\n")
+	start := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, indexTableHeaderPrefix) && i+1 < len(lines) {
+			if l+"\n"+lines[i+1] != header {
+				return nil, false
+			}
+			start = i + 2
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	end := start
+	for end < len(lines) && strings.HasPrefix(lines[end], "| [") {
+		end++
+	}
+	rows := append([]string{}, lines[start:end]...)
+	replaced := false
+	for i, l := range rows {
+		if strings.HasPrefix(l, rowPrefix) {
+			rows[i] = row
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		rows = append(rows, row)
+		sort.SliceStable(rows, func(i, j int) bool { return rowRepository(rows[i]) < rowRepository(rows[j]) })
+	}
+	patched := append(append(append([]string{}, lines[:start]...), rows...), lines[end:]...)
+	return []byte(strings.Join(patched, "\n")), true
+}
+
+func rowRepository(row string) string {
+	s := strings.TrimPrefix(row, "| [")
+	if i := strings.Index(s, "]("); i >= 0 {
+		return s[:i]
+	}
+	return s
 }
 
 func (c *Central) collectReports() error {
 	rsMap := map[string]*report.Report{}
+	c.sources = map[string]reportSource{}
 
 	// collect reports
-	for _, fsys := range c.config.Reports {
+	for i, fsys := range c.config.Reports {
+		i := i
 		if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
@@ -92,21 +361,32 @@ func (c *Central) collectReports() error {
 				return nil
 			}
 			defer f.Close()
-			b, err := io.ReadAll(f)
-			if err != nil {
+			if err := json.NewDecoder(f).Decode(r); err != nil {
 				return nil
 			}
-			if err := json.Unmarshal(b, r); err != nil {
+			// reports are attributed by their content, not by the directory structure
+			if r.Repository == "" {
+				_, _ = fmt.Fprintf(os.Stderr, "Skip report without repository: %s\n", path)
+				return nil
+			}
+			ok, err := c.collectable(r.Repository)
+			if err != nil {
+				return err
+			}
+			if !ok {
 				return nil
 			}
 			current, ok := rsMap[r.Repository]
 			if !ok {
 				_, _ = fmt.Fprintf(os.Stderr, "Collect report of %s\n", r.Repository)
 				rsMap[r.Repository] = r
+				c.sources[r.Repository] = reportSource{fsys: i, path: path}
+				c.evictOldestReport(rsMap)
 				return nil
 			}
 			if current.Timestamp.UnixNano() < r.Timestamp.UnixNano() {
 				rsMap[r.Repository] = r
+				c.sources[r.Repository] = reportSource{fsys: i, path: path}
 			}
 			return nil
 		}); err != nil {
@@ -121,23 +401,175 @@ func (c *Central) collectReports() error {
 	return nil
 }
 
-func (c *Central) generateBadges() ([]string, error) {
-	generatedPaths := []string{}
+// collectable reports whether the repository is matched by IncludeRepositories ( if any ) and not matched by ExcludeRepositories
+func (c *Central) collectable(repository string) (bool, error) {
+	if len(c.config.IncludeRepositories) > 0 {
+		included := false
+		for _, p := range c.config.IncludeRepositories {
+			match, err := doublestar.Match(p, repository)
+			if err != nil {
+				return false, err
+			}
+			if match {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false, nil
+		}
+	}
+	for _, p := range c.config.ExcludeRepositories {
+		match, err := doublestar.Match(p, repository)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
 
-	for _, r := range c.reports {
-		cp := r.CoveragePercent()
-		err := os.MkdirAll(filepath.Join(c.config.Badges, r.Repository), 0755) // #nosec
+func (c *Central) sortedByName() bool {
+	return (c.config.IndexSort == "" || c.config.IndexSort == IndexSortName) && c.config.IndexTop <= 0
+}
+
+// indexReports returns the reports listed in the index in the order of IndexSort
+func (c *Central) indexReports() []*report.Report {
+	rs := append([]*report.Report{}, c.reports...)
+	if c.config.IndexTop > 0 && len(rs) > c.config.IndexTop {
+		sort.SliceStable(rs, func(i, j int) bool {
+			if rs[i].CoveragePercent() != rs[j].CoveragePercent() {
+				return rs[i].CoveragePercent() < rs[j].CoveragePercent()
+			}
+			return rs[i].Repository < rs[j].Repository
+		})
+		rs = rs[:c.config.IndexTop]
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		switch c.config.IndexSort {
+		case IndexSortCoverage:
+			// the lowest coverage first
+			if rs[i].CoveragePercent() != rs[j].CoveragePercent() {
+				return rs[i].CoveragePercent() < rs[j].CoveragePercent()
+			}
+		case IndexSortUpdated:
+			// the most recently updated first
+			if !rs[i].Timestamp.Equal(rs[j].Timestamp) {
+				return rs[i].Timestamp.After(rs[j].Timestamp)
+			}
+		}
+		return rs[i].Repository < rs[j].Repository
+	})
+	return rs
+}
+
+type indexGroup struct {
+	// empty when the index is not grouped
+	Name    string
+	Reports []*report.Report
+}
+
+// Coverage returns the average coverage of the reports whose coverage is measured in the group
+func (g *indexGroup) Coverage() string {
+	total := 0.0
+	n := 0
+	for _, r := range g.Reports {
+		if !r.IsMeasuredCoverage() {
+			continue
+		}
+		total += r.CoveragePercent()
+		n++
+	}
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", total/float64(n))
+}
+
+// indexGroups groups the index reports by IndexGroupBy in the order of the group names.
+// The repositories that do not match the regexp are grouped into indexGroupOthers at the end.
+func (c *Central) indexGroups() ([]*indexGroup, error) {
+	rs := c.indexReports()
+	if c.config.IndexGroupBy == "" {
+		return []*indexGroup{{Reports: rs}}, nil
+	}
+	var re *regexp.Regexp
+	if c.config.IndexGroupBy != IndexGroupByOwner {
+		var err error
+		re, err = regexp.Compile(c.config.IndexGroupBy)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid central.index.groupBy: %w", err)
 		}
-		bp := filepath.Join(c.config.Badges, r.Repository, "coverage.svg")
-		out, err := os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	}
+	groups := []*indexGroup{}
+	gMap := map[string]*indexGroup{}
+	for _, r := range rs {
+		name := groupName(r.Repository, re)
+		g, ok := gMap[name]
+		if !ok {
+			g = &indexGroup{Name: name}
+			gMap[name] = g
+			groups = append(groups, g)
+		}
+		g.Reports = append(g.Reports, r)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Name == indexGroupOthers || groups[j].Name == indexGroupOthers {
+			return groups[j].Name == indexGroupOthers && groups[i].Name != indexGroupOthers
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups, nil
+}
+
+// groupName returns the owner of the repository when re is nil, otherwise the first submatch ( or the whole match ) of re
+func groupName(repository string, re *regexp.Regexp) string {
+	if re == nil {
+		owner, _, err := gh.SplitRepository(repository)
 		if err != nil {
-			return nil, err
+			return indexGroupOthers
 		}
+		return owner
+	}
+	m := re.FindStringSubmatch(repository)
+	switch {
+	case len(m) == 0:
+		return indexGroupOthers
+	case len(m) > 1 && m[1] != "":
+		return m[1]
+	case m[0] != "":
+		return m[0]
+	}
+	return indexGroupOthers
+}
+
+// evictOldestReport drops the oldest report when the collected reports exceed ReportsLimit,
+// so that at most ReportsLimit reports are held while walking the datastores
+func (c *Central) evictOldestReport(rsMap map[string]*report.Report) {
+	if c.config.ReportsLimit <= 0 || len(rsMap) <= c.config.ReportsLimit {
+		return
+	}
+	var oldest *report.Report
+	for _, r := range rsMap {
+		if oldest == nil || r.Timestamp.Before(oldest.Timestamp) || (r.Timestamp.Equal(oldest.Timestamp) && r.Repository > oldest.Repository) {
+			oldest = r
+		}
+	}
+	delete(rsMap, oldest.Repository)
+	delete(c.sources, oldest.Repository)
+}
+
+func (c *Central) generateBadges() ([]string, error) {
+	generatedPaths := []string{}
+
+	for _, r := range c.reports {
+		cp := r.CoveragePercent()
 		b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
 		b.MessageColor = c.config.CoverageColor(cp)
-		if err := b.Render(out); err != nil {
+		bp, err := c.renderBadge(r, badgeMetricCoverage, b)
+		if err != nil {
 			return nil, err
 		}
 		generatedPaths = append(generatedPaths, bp)
@@ -145,18 +577,10 @@ func (c *Central) generateBadges() ([]string, error) {
 		// Code to Test Ratio
 		if r.CodeToTestRatio != nil {
 			tr := r.CodeToTestRatioRatio()
-			err := os.MkdirAll(filepath.Join(c.config.Badges, r.Repository), 0755) // #nosec
-			if err != nil {
-				return nil, err
-			}
-			bp := filepath.Join(c.config.Badges, r.Repository, "ratio.svg")
-			out, err = os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
-			if err != nil {
-				return nil, err
-			}
 			b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
 			b.MessageColor = c.config.CodeToTestRatioColor(tr)
-			if err := b.Render(out); err != nil {
+			bp, err := c.renderBadge(r, badgeMetricRatio, b)
+			if err != nil {
 				return nil, err
 			}
 			generatedPaths = append(generatedPaths, bp)
@@ -165,28 +589,177 @@ func (c *Central) generateBadges() ([]string, error) {
 		// Test Execution Time
 		if r.TestExecutionTime != nil {
 			d := time.Duration(*r.TestExecutionTime)
-			err := os.MkdirAll(filepath.Join(c.config.Badges, r.Repository), 0755) // #nosec
+			b := badge.New("test execution time", d.String())
+			b.MessageColor = c.config.TestExecutionTimeColor(d)
+			bp, err := c.renderBadge(r, badgeMetricTime, b)
 			if err != nil {
 				return nil, err
 			}
-			bp := filepath.Join(c.config.Badges, r.Repository, "time.svg")
-			out, err = os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
-			if err != nil {
-				return nil, err
+			generatedPaths = append(generatedPaths, bp)
+		}
+
+		// Freshness
+		if c.config.FreshnessColor != nil && !r.Timestamp.IsZero() {
+			label := c.config.FreshnessLabel
+			if label == "" {
+				label = defaultFreshnessLabel
 			}
-			b := badge.New("test execution time", d.String())
-			b.MessageColor = c.config.TestExecutionTimeColor(d)
-			if err := b.Render(out); err != nil {
+			age := time.Since(r.Timestamp)
+			b := badge.New(label, formatAge(age))
+			b.MessageColor = c.config.FreshnessColor(age)
+			bp, err := c.renderBadge(r, badgeMetricFreshness, b)
+			if err != nil {
 				return nil, err
 			}
 			generatedPaths = append(generatedPaths, bp)
 		}
 	}
+
+	// Overall
+	if c.config.OverallWeight != "" && len(c.reports) > 0 {
+		label := c.config.OverallLabel
+		if label == "" {
+			label = defaultOverallLabel
+		}
+		cp, err := c.overallCoverage()
+		if err != nil {
+			return nil, err
+		}
+		b := badge.New(label, fmt.Sprintf("%.1f%%", cp))
+		b.MessageColor = c.config.CoverageColor(cp)
+		bp := filepath.Join(c.config.Badges, OverallBadge)
+		if err := os.MkdirAll(filepath.Dir(bp), 0755); err != nil { // #nosec
+			return nil, err
+		}
+		out, err := os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+		if err != nil {
+			return nil, err
+		}
+		defer out.Close()
+		if err := b.Render(out); err != nil {
+			return nil, err
+		}
+		generatedPaths = append(generatedPaths, bp)
+	}
 	return generatedPaths, nil
 }
 
+// overallCoverage returns the coverage [%] across the collected reports in the way of OverallWeight.
+// The summary only reports are not weighted by OverallWeightLOC because the number of their code is unknown.
+func (c *Central) overallCoverage() (float64, error) {
+	switch c.config.OverallWeight {
+	case OverallWeightMean:
+		total := 0.0
+		n := 0
+		for _, r := range c.reports {
+			if !r.IsMeasuredCoverage() {
+				continue
+			}
+			total += r.CoveragePercent()
+			n++
+		}
+		if n == 0 {
+			return 0.0, nil
+		}
+		return total / float64(n), nil
+	case OverallWeightLOC:
+		var covered, total int
+		for _, r := range c.reports {
+			if r.SummaryOnly || r.Coverage == nil {
+				continue
+			}
+			covered += r.Coverage.Covered
+			total += r.Coverage.Total
+		}
+		if total == 0 {
+			return 0.0, nil
+		}
+		return float64(covered) / float64(total) * 100, nil
+	default:
+		return 0.0, fmt.Errorf("invalid central.badges.overall.weight: %s", c.config.OverallWeight)
+	}
+}
+
+// stale reports whether the report is older than StaleAfter at the time of generation
+func (c *Central) stale(r *report.Report) bool {
+	if c.config.StaleAfter <= 0 || r.Timestamp.IsZero() {
+		return false
+	}
+	return time.Since(r.Timestamp) > c.config.StaleAfter
+}
+
+// formatAge formats the age of the report in a short form ( e.g. 2d ago )
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
+func (c *Central) renderBadge(r *report.Report, metric string, b *badge.Badge) (string, error) {
+	rel, err := c.badgePath(r, metric)
+	if err != nil {
+		return "", err
+	}
+	bp := filepath.Join(c.config.Badges, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(bp), 0755); err != nil { // #nosec
+		return "", err
+	}
+	out, err := os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+	if err := b.Render(out); err != nil {
+		return "", err
+	}
+	return bp, nil
+}
+
+// badgePath returns the path of the badge relative to the badges directory using central.badges.layout
+func (c *Central) badgePath(r *report.Report, metric string) (string, error) {
+	layout := c.config.BadgesLayout
+	if layout == "" {
+		layout = DefaultBadgesLayout
+	}
+	tmpl, err := template.New("badgesLayout").Parse(layout)
+	if err != nil {
+		return "", fmt.Errorf("invalid central.badges.layout: %w", err)
+	}
+	owner, repo, err := gh.SplitRepository(r.Repository)
+	if err != nil {
+		return "", err
+	}
+	buf := new(strings.Builder)
+	if err := tmpl.Execute(buf, map[string]string{
+		"Repository": r.Repository,
+		"Owner":      owner,
+		"Repo":       repo,
+		"Metric":     metric,
+	}); err != nil {
+		return "", fmt.Errorf("invalid central.badges.layout: %w", err)
+	}
+	p := path.Clean(strings.TrimPrefix(buf.String(), "/"))
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("invalid central.badges.layout: %s is out of the badges directory", buf.String())
+	}
+	return p, nil
+}
+
 func (c *Central) renderIndex(wr io.Writer) error {
-	tmpl := template.Must(template.New("index").Funcs(funcs()).Parse(string(indexTmpl)))
+	tmpl := template.Must(template.New("index").Funcs(funcs()).Funcs(template.FuncMap{
+		"badgePath": c.badgePath,
+		"stale":     c.stale,
+		"age": func(r *report.Report) string {
+			return formatAge(time.Since(r.Timestamp))
+		},
+	}).Parse(string(indexTmpl)))
 	host := os.Getenv("GITHUB_SERVER_URL")
 	if host == "" {
 		host = gh.DefaultGithubServerURL
@@ -224,12 +797,30 @@ func (c *Central) renderIndex(wr io.Writer) error {
 		return err
 	}
 
+	links, err := c.repoLinks()
+	if err != nil {
+		return err
+	}
+
+	reportURLs, err := c.reportURLs(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups, err := c.indexGroups()
+	if err != nil {
+		return err
+	}
+
 	d := map[string]interface{}{
 		"Host":          host,
-		"Reports":       c.reports,
+		"Groups":        groups,
 		"BadgesLinkRel": badgesLinkRel,
 		"BadgesURLRel":  badgesURLRel,
 		"RawRootURL":    rawRootURL,
+		"Links":         links,
+		"ReportURLs":    reportURLs,
+		"Freshness":     c.config.FreshnessColor != nil,
 	}
 	if err := tmpl.Execute(wr, d); err != nil {
 		return err
@@ -238,6 +829,56 @@ func (c *Central) renderIndex(wr io.Writer) error {
 	return nil
 }
 
+// reportURLs signs the URLs of the reports read from the datastores with ReportURLSigners
+func (c *Central) reportURLs(ctx context.Context) (map[string]string, error) {
+	urls := map[string]string{}
+	for _, r := range c.reports {
+		src, ok := c.sources[r.Repository]
+		if !ok || src.fsys >= len(c.config.ReportURLSigners) || c.config.ReportURLSigners[src.fsys] == nil {
+			continue
+		}
+		u, err := c.config.ReportURLSigners[src.fsys](ctx, src.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign the URL of the report of %s: %w", r.Repository, err)
+		}
+		urls[r.Repository] = u
+	}
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	return urls, nil
+}
+
+// repoLinks renders central.repoLinkTemplate for each repository ( the repository that fails to render is left without a link )
+func (c *Central) repoLinks() (map[string]string, error) {
+	links := map[string]string{}
+	if c.config.RepoLinkTemplate == "" {
+		return links, nil
+	}
+	tmpl, err := template.New("repoLink").Parse(c.config.RepoLinkTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid central.repoLinkTemplate: %w", err)
+	}
+	for _, r := range c.reports {
+		owner, repo, err := gh.SplitRepository(r.Repository)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Skip the link of %s: %s\n", r.Repository, err)
+			continue
+		}
+		buf := new(strings.Builder)
+		if err := tmpl.Execute(buf, map[string]string{
+			"Repository": r.Repository,
+			"Owner":      owner,
+			"Repo":       repo,
+		}); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Skip the link of %s: invalid central.repoLinkTemplate: %s\n", r.Repository, err)
+			continue
+		}
+		links[r.Repository] = buf.String()
+	}
+	return links, nil
+}
+
 func funcs() map[string]interface{} {
 	return template.FuncMap{
 		"coverage": func(r *report.Report) string {
@@ -2,14 +2,25 @@ package central
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io/fs"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/datastore/local"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
 )
 
 func TestCollectReports(t *testing.T) {
@@ -43,6 +54,399 @@ func TestCollectReports(t *testing.T) {
 	}
 }
 
+func TestCollectReportsFromMultipleLocalDatastores(t *testing.T) {
+	c := config.New()
+	reports := []fs.FS{}
+	for _, d := range []string{"a", "b"} {
+		l, err := local.New(filepath.Join(testdataDir(t), "central_multi", d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys, err := l.FS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, fsys)
+	}
+	ctr := New(&CentralConfig{
+		Repository:             "owner/repo",
+		Index:                  ".",
+		Wd:                     c.Getwd(),
+		Badges:                 "badges",
+		Reports:                reports,
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+	})
+
+	if err := ctr.collectReports(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, r := range ctr.reports {
+		got[r.Repository] = r.Commit
+	}
+	want := map[string]string{
+		"owner/alpha": "3333333333333333333333333333333333333333",
+		"owner/beta":  "2222222222222222222222222222222222222222",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %v\nwant %v", k, got[k], v)
+		}
+	}
+}
+
+func TestCollectReportsWithLimit(t *testing.T) {
+	c := config.New()
+	fsys := fstest.MapFS{}
+	base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	// with limit 2, owner/a is evicted once and collected again by its newer report
+	for i, r := range []*report.Report{
+		{Repository: "owner/a", Timestamp: base.Add(1 * time.Hour)},
+		{Repository: "owner/b", Timestamp: base.Add(4 * time.Hour)},
+		{Repository: "owner/c", Timestamp: base},
+		{Repository: "owner/d", Timestamp: base.Add(3 * time.Hour)},
+		{Repository: "owner/a", Timestamp: base.Add(5 * time.Hour)},
+	} {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys[fmt.Sprintf("%d.json", i)] = &fstest.MapFile{Data: b}
+	}
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{0, []string{"owner/a", "owner/b", "owner/c", "owner/d"}},
+		{2, []string{"owner/a", "owner/b"}},
+		{3, []string{"owner/a", "owner/b", "owner/d"}},
+		{10, []string{"owner/a", "owner/b", "owner/c", "owner/d"}},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			Repository:             "owner/repo",
+			Index:                  ".",
+			Wd:                     c.Getwd(),
+			Badges:                 "badges",
+			Reports:                []fs.FS{fsys},
+			ReportsLimit:           tt.limit,
+			CoverageColor:          c.CoverageColor,
+			CodeToTestRatioColor:   c.CodeToTestRatioColor,
+			TestExecutionTimeColor: c.TestExecutionTimeColor,
+		})
+		if err := ctr.collectReports(); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, r := range ctr.reports {
+			got = append(got, r.Repository)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("limit %d: %s", tt.limit, diff)
+		}
+	}
+}
+
+func TestCollectReportsWithPatterns(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for i, repo := range []string{"owner/a", "owner/archived-b", "other/c", "owner/d"} {
+		b, err := json.Marshal(&report.Report{Repository: repo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys[fmt.Sprintf("%d.json", i)] = &fstest.MapFile{Data: b}
+	}
+	tests := []struct {
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{nil, nil, []string{"other/c", "owner/a", "owner/archived-b", "owner/d"}},
+		{[]string{"owner/*"}, nil, []string{"owner/a", "owner/archived-b", "owner/d"}},
+		{[]string{"owner/*"}, []string{"*/archived-*"}, []string{"owner/a", "owner/d"}},
+		{nil, []string{"owner/*"}, []string{"other/c"}},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			Reports:             []fs.FS{fsys},
+			IncludeRepositories: tt.include,
+			ExcludeRepositories: tt.exclude,
+		})
+		if err := ctr.collectReports(); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, r := range ctr.reports {
+			got = append(got, r.Repository)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%v %v: %s", tt.include, tt.exclude, diff)
+		}
+	}
+}
+
+func TestIndexReports(t *testing.T) {
+	base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	reports := []*report.Report{
+		{Repository: "owner/a", Timestamp: base.Add(2 * time.Hour), Coverage: &coverage.Coverage{Covered: 90, Total: 100}},
+		{Repository: "owner/b", Timestamp: base, Coverage: &coverage.Coverage{Covered: 30, Total: 100}},
+		{Repository: "owner/c", Timestamp: base.Add(1 * time.Hour), Coverage: &coverage.Coverage{Covered: 60, Total: 100}},
+		{Repository: "owner/d", Timestamp: base.Add(3 * time.Hour), Coverage: &coverage.Coverage{Covered: 30, Total: 100}},
+	}
+	tests := []struct {
+		sort string
+		top  int
+		want []string
+	}{
+		{"", 0, []string{"owner/a", "owner/b", "owner/c", "owner/d"}},
+		{IndexSortName, 0, []string{"owner/a", "owner/b", "owner/c", "owner/d"}},
+		{IndexSortCoverage, 0, []string{"owner/b", "owner/d", "owner/c", "owner/a"}},
+		{IndexSortUpdated, 0, []string{"owner/d", "owner/a", "owner/c", "owner/b"}},
+		{"", 2, []string{"owner/b", "owner/d"}},
+		{IndexSortUpdated, 3, []string{"owner/d", "owner/c", "owner/b"}},
+		{IndexSortCoverage, 10, []string{"owner/b", "owner/d", "owner/c", "owner/a"}},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			IndexSort: tt.sort,
+			IndexTop:  tt.top,
+		})
+		ctr.reports = reports
+		got := []string{}
+		for _, r := range ctr.indexReports() {
+			got = append(got, r.Repository)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s %d: %s", tt.sort, tt.top, diff)
+		}
+		if ctr.reports[0].Repository != "owner/a" {
+			t.Error("the collected reports should not be reordered")
+		}
+	}
+}
+
+func TestIndexGroups(t *testing.T) {
+	reports := []*report.Report{
+		{Repository: "org-a/payments-api", Coverage: &coverage.Coverage{Covered: 80, Total: 100}},
+		{Repository: "org-a/search-web", Coverage: &coverage.Coverage{Covered: 50, Total: 100}},
+		{Repository: "org-b/payments-web", Coverage: &coverage.Coverage{Covered: 60, Total: 100}},
+		{Repository: "org-b/tools", Coverage: &coverage.Coverage{Covered: 10, Total: 100}},
+		{Repository: "org-c/docs"},
+	}
+	tests := []struct {
+		groupBy string
+		want    []string
+	}{
+		{"", []string{": org-a/payments-api org-a/search-web org-b/payments-web org-b/tools org-c/docs (50.0%)"}},
+		{IndexGroupByOwner, []string{
+			"org-a: org-a/payments-api org-a/search-web (65.0%)",
+			"org-b: org-b/payments-web org-b/tools (35.0%)",
+			"org-c: org-c/docs (-)",
+		}},
+		{`^[^/]+/([^-]+)-`, []string{
+			"payments: org-a/payments-api org-b/payments-web (70.0%)",
+			"search: org-a/search-web (50.0%)",
+			"Others: org-b/tools org-c/docs (10.0%)",
+		}},
+		{`^org-b/`, []string{
+			"org-b/: org-b/payments-web org-b/tools (35.0%)",
+			"Others: org-a/payments-api org-a/search-web org-c/docs (65.0%)",
+		}},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			IndexGroupBy: tt.groupBy,
+		})
+		ctr.reports = reports
+		groups, err := ctr.indexGroups()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, g := range groups {
+			rs := []string{}
+			for _, r := range g.Reports {
+				rs = append(rs, r.Repository)
+			}
+			got = append(got, fmt.Sprintf("%s: %s (%s)", g.Name, strings.Join(rs, " "), g.Coverage()))
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s: %s", tt.groupBy, diff)
+		}
+	}
+}
+
+func TestReportURLs(t *testing.T) {
+	c := config.New()
+	reports := []fs.FS{}
+	for _, d := range []string{"a", "b"} {
+		l, err := local.New(filepath.Join(testdataDir(t), "central_multi", d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys, err := l.FS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, fsys)
+	}
+	sign := func(prefix string) func(ctx context.Context, path string) (string, error) {
+		return func(ctx context.Context, path string) (string, error) {
+			return fmt.Sprintf("https://%s/%s?sig=xxx", prefix, path), nil
+		}
+	}
+	tests := []struct {
+		signers []func(ctx context.Context, path string) (string, error)
+		want    map[string]string
+	}{
+		{nil, nil},
+		{[]func(ctx context.Context, path string) (string, error){nil, nil}, nil},
+		{[]func(ctx context.Context, path string) (string, error){sign("a"), sign("b")}, map[string]string{
+			"owner/alpha": "https://b/owner/alpha/report.json?sig=xxx",
+			"owner/beta":  "https://a/misc/other.json?sig=xxx",
+		}},
+		{[]func(ctx context.Context, path string) (string, error){nil, sign("b")}, map[string]string{
+			"owner/alpha": "https://b/owner/alpha/report.json?sig=xxx",
+		}},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			Repository:             "owner/repo",
+			Index:                  ".",
+			Wd:                     c.Getwd(),
+			Badges:                 "badges",
+			Reports:                reports,
+			ReportURLSigners:       tt.signers,
+			CoverageColor:          c.CoverageColor,
+			CodeToTestRatioColor:   c.CodeToTestRatioColor,
+			TestExecutionTimeColor: c.TestExecutionTimeColor,
+		})
+		if err := ctr.collectReports(); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ctr.reportURLs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestFindReport(t *testing.T) {
+	reports := []fs.FS{}
+	for _, d := range []string{"a", "b"} {
+		l, err := local.New(filepath.Join(testdataDir(t), "central_multi", d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys, err := l.FS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, fsys)
+	}
+	tests := []struct {
+		repository string
+		wantCommit string
+		wantErr    bool
+	}{
+		{"owner/alpha", "3333333333333333333333333333333333333333", false},
+		{"owner/beta", "2222222222222222222222222222222222222222", false},
+		{"owner/unknown", "", true},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{Reports: reports})
+		got, err := ctr.findReport(tt.repository)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got.Commit != tt.wantCommit {
+			t.Errorf("got %v\nwant %v", got.Commit, tt.wantCommit)
+		}
+	}
+}
+
+func TestNeedsFullGeneration(t *testing.T) {
+	tests := []struct {
+		config *CentralConfig
+		want   bool
+	}{
+		{&CentralConfig{}, false},
+		{&CentralConfig{IndexSort: IndexSortName}, false},
+		{&CentralConfig{IndexTop: 10}, true},
+		{&CentralConfig{IndexGroupBy: "owner"}, true},
+		{&CentralConfig{StaleAfter: time.Hour}, true},
+		{&CentralConfig{OverallWeight: "mean"}, true},
+		{&CentralConfig{ReportsLimit: 10}, true},
+	}
+	for _, tt := range tests {
+		ctr := New(tt.config)
+		if _, got := ctr.needsFullGeneration(); got != tt.want {
+			t.Errorf("%+v: got %v\nwant %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestRepoLinks(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"", map[string]string{}, false},
+		{
+			"https://grafana.example.com/d/coverage?var-repo={{ .Repository }}",
+			map[string]string{"k1LoW/tbls": "https://grafana.example.com/d/coverage?var-repo=k1LoW/tbls"},
+			false,
+		},
+		{
+			"https://portal.example.com/{{ .Owner }}/projects/{{ .Repo }}",
+			map[string]string{"k1LoW/tbls": "https://portal.example.com/k1LoW/projects/tbls"},
+			false,
+		},
+		{"https://portal.example.com/{{ .Repository", nil, true},
+		{"https://portal.example.com/{{ .Repository.Name }}", map[string]string{}, false},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{
+			RepoLinkTemplate: tt.tmpl,
+		})
+		ctr.reports = []*report.Report{{Repository: "k1LoW/tbls"}}
+		got, err := ctr.repoLinks()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("got %v\nwant %v", got[k], v)
+			}
+		}
+	}
+}
+
 func TestGenerateBadges(t *testing.T) {
 	bd := t.TempDir()
 	c := config.New()
@@ -95,6 +499,247 @@ func TestGenerateBadges(t *testing.T) {
 	}
 }
 
+func TestGenerateBadgesWithLayout(t *testing.T) {
+	bd := t.TempDir()
+	c := config.New()
+	ctr := New(&CentralConfig{
+		Badges:                 bd,
+		BadgesLayout:           "{{ .Owner }}/{{ .Repo }}/badges/{{ .Metric }}.svg",
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+	})
+	tet := float64(1000)
+	ctr.reports = []*report.Report{
+		{Repository: "owner/repo", TestExecutionTime: &tet},
+	}
+	got, err := ctr.generateBadges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(bd, "owner", "repo", "badges", "coverage.svg"),
+		filepath.Join(bd, "owner", "repo", "badges", "time.svg"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v\nwant %v", got[i], want[i])
+		}
+		if _, err := os.Stat(want[i]); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestGenerateFreshnessBadges(t *testing.T) {
+	bd := t.TempDir()
+	c := config.New()
+	ctr := New(&CentralConfig{
+		Badges:                 bd,
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+		FreshnessLabel:         "reported",
+		FreshnessColor:         c.FreshnessColor,
+	})
+	ctr.reports = []*report.Report{
+		{Repository: "owner/repo", Timestamp: time.Now().Add(-50 * time.Hour)},
+		{Repository: "owner/notimestamp"},
+	}
+	got, err := ctr.generateBadges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(bd, "owner", "repo", "coverage.svg"),
+		filepath.Join(bd, "owner", "repo", "freshness.svg"),
+		filepath.Join(bd, "owner", "notimestamp", "coverage.svg"),
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	b, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"reported", "2d ago"} {
+		if !strings.Contains(string(b), w) {
+			t.Errorf("got %s\nwant %s", string(b), w)
+		}
+	}
+}
+
+func TestGenerateOverallBadge(t *testing.T) {
+	reports := []*report.Report{
+		{Repository: "owner/a", Coverage: &coverage.Coverage{Covered: 90, Total: 100}},
+		{Repository: "owner/b", Coverage: &coverage.Coverage{Covered: 10, Total: 300}},
+		{Repository: "owner/c", Coverage: &coverage.Coverage{Covered: 500, Total: 1000}, SummaryOnly: true},
+		{Repository: "owner/d"},
+	}
+	tests := []struct {
+		weight string
+		want   string
+	}{
+		{OverallWeightMean, "47.8%"},
+		{OverallWeightLOC, "25.0%"},
+	}
+	for _, tt := range tests {
+		bd := t.TempDir()
+		c := config.New()
+		ctr := New(&CentralConfig{
+			Badges:                 bd,
+			CoverageColor:          c.CoverageColor,
+			CodeToTestRatioColor:   c.CodeToTestRatioColor,
+			TestExecutionTimeColor: c.TestExecutionTimeColor,
+			OverallLabel:           "org coverage",
+			OverallWeight:          tt.weight,
+		})
+		ctr.reports = reports
+		got, err := ctr.generateBadges()
+		if err != nil {
+			t.Fatal(err)
+		}
+		op := filepath.Join(bd, OverallBadge)
+		if got[len(got)-1] != op {
+			t.Errorf("got %v\nwant %v", got[len(got)-1], op)
+		}
+		b, err := os.ReadFile(op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range []string{"org coverage", tt.want} {
+			if !strings.Contains(string(b), w) {
+				t.Errorf("%s: got %s\nwant %s", tt.weight, string(b), w)
+			}
+		}
+	}
+}
+
+func TestStale(t *testing.T) {
+	tests := []struct {
+		staleAfter time.Duration
+		r          *report.Report
+		want       bool
+	}{
+		{0, &report.Report{Timestamp: time.Now().Add(-1000 * time.Hour)}, false},
+		{24 * time.Hour, &report.Report{Timestamp: time.Now().Add(-25 * time.Hour)}, true},
+		{24 * time.Hour, &report.Report{Timestamp: time.Now().Add(-23 * time.Hour)}, false},
+		{24 * time.Hour, &report.Report{}, false},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{StaleAfter: tt.staleAfter})
+		if got := ctr.stale(tt.r); got != tt.want {
+			t.Errorf("%v %v: got %v\nwant %v", tt.staleAfter, tt.r.Timestamp, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{59 * time.Minute, "59m ago"},
+		{23 * time.Hour, "23h ago"},
+		{49 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.in); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestBadgePath(t *testing.T) {
+	tests := []struct {
+		layout  string
+		want    string
+		wantErr bool
+	}{
+		{"", "owner/repo/coverage.svg", false},
+		{"{{ .Metric }}/{{ .Owner }}-{{ .Repo }}.svg", "coverage/owner-repo.svg", false},
+		{"/{{ .Repository }}/{{ .Metric }}.svg", "owner/repo/coverage.svg", false},
+		{"../{{ .Metric }}.svg", "", true},
+		{"{{ .Metric", "", true},
+	}
+	for _, tt := range tests {
+		ctr := New(&CentralConfig{BadgesLayout: tt.layout})
+		got, err := ctr.badgePath(&report.Report{Repository: "owner/repo"}, "coverage")
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestPatchIndexRow(t *testing.T) {
+	current := `# Index
+
+| Repository | Coverage | Badges |
+| --- | --- | --- |
+| [a/a](https://github.com/a/a) | 10.0% | - |
+| [c/c](https://github.com/c/c) | 30.0% | - |
+
+---
+`
+	tests := []struct {
+		rendered   string
+		repository string
+		want       string
+		wantOK     bool
+	}{
+		{
+			"| Repository | Coverage | Badges |\n| --- | --- | --- |\n| [c/c](https://github.com/c/c) | 35.0% | - |\n",
+			"c/c",
+			"| [a/a](https://github.com/a/a) | 10.0% | - |\n| [c/c](https://github.com/c/c) | 35.0% | - |\n\n---",
+			true,
+		},
+		{
+			"| Repository | Coverage | Badges |\n| --- | --- | --- |\n| [b/b](https://github.com/b/b) | 20.0% | - |\n",
+			"b/b",
+			"| [a/a](https://github.com/a/a) | 10.0% | - |\n| [b/b](https://github.com/b/b) | 20.0% | - |\n| [c/c](https://github.com/c/c) | 30.0% | - |\n\n---",
+			true,
+		},
+		{
+			"| Repository | Coverage | Badges | Links |\n| --- | --- | --- | --- |\n| [c/c](https://github.com/c/c) | 35.0% | - | [Link](https://example.com) |\n",
+			"c/c",
+			"",
+			false,
+		},
+		{
+			"| Repository | Coverage | Badges |\n| --- | --- | --- |\n",
+			"c/c",
+			"",
+			false,
+		},
+	}
+	for _, tt := range tests {
+		got, ok := patchIndexRow([]byte(current), []byte(tt.rendered), tt.repository)
+		if ok != tt.wantOK {
+			t.Errorf("got %v\nwant %v", ok, tt.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !strings.Contains(string(got), tt.want) {
+			t.Errorf("got %v\nwant %v", string(got), tt.want)
+		}
+	}
+}
+
 func TestRenderIndex(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -108,7 +753,7 @@ func TestRenderIndex(t *testing.T) {
 		Reports: config.ConfigCentralReports{
 			Datastores: []string{"reports"},
 		},
-		Badges: "badges",
+		Badges: config.ConfigCentralBadges{Path: "badges"},
 	}
 	c.Build()
 	l, err := local.New(filepath.Join(testdataDir(t), "reports"))
@@ -124,7 +769,7 @@ func TestRenderIndex(t *testing.T) {
 		Repository:             c.Repository,
 		Index:                  c.Central.Root,
 		Wd:                     c.Getwd(),
-		Badges:                 c.Central.Badges,
+		Badges:                 c.Central.Badges.Path,
 		Reports:                []fs.FS{fsys},
 		CoverageColor:          c.CoverageColor,
 		CodeToTestRatioColor:   c.CodeToTestRatioColor,
@@ -163,3 +808,45 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestWebhookHandler(t *testing.T) {
+	secret := "secret"
+	tests := []struct {
+		method    string
+		payload   string
+		signature string
+		wantCode  int
+		wantRepo  string
+	}{
+		{http.MethodPost, `{"repository":{"full_name":"owner/repo"}}`, "", http.StatusAccepted, "owner/repo"},
+		{http.MethodPost, `{"repository":{"full_name":"owner/central"},"client_payload":{"repository":"owner/repo"}}`, "", http.StatusAccepted, "owner/repo"},
+		{http.MethodPost, `{"repository":{"full_name":"owner/repo"}}`, "sha256=invalid", http.StatusUnauthorized, ""},
+		{http.MethodPost, `{"repository":{"full_name":"owner/repo"}}`, "-", http.StatusUnauthorized, ""},
+		{http.MethodPost, `{"repository":{"full_name":"../../etc"}}`, "", http.StatusBadRequest, ""},
+		{http.MethodPost, `{}`, "", http.StatusBadRequest, ""},
+		{http.MethodGet, ``, "", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		h := NewWebhookHandler(secret, func(repository string) error {
+			got = repository
+			return nil
+		})
+		req := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.payload))
+		switch tt.signature {
+		case "":
+			req.Header.Set(SignatureHeader, Sign(secret, []byte(tt.payload)))
+		case "-":
+		default:
+			req.Header.Set(SignatureHeader, tt.signature)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.wantCode {
+			t.Errorf("got %v\nwant %v", rec.Code, tt.wantCode)
+		}
+		if got != tt.wantRepo {
+			t.Errorf("got %v\nwant %v", got, tt.wantRepo)
+		}
+	}
+}
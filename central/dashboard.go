@@ -0,0 +1,127 @@
+package central
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+// dashboard is the JSON of the collected reports for custom frontends
+type dashboard struct {
+	Repositories []*dashboardRepository `json:"repositories"`
+}
+
+type dashboardRepository struct {
+	Repository string `json:"repository"`
+	// [%]
+	Coverage        float64  `json:"coverage"`
+	CodeToTestRatio *float64 `json:"code_to_test_ratio,omitempty"`
+	// [ns] same as report.json
+	TestExecutionTime *float64  `json:"test_execution_time,omitempty"`
+	Timestamp         time.Time `json:"timestamp"`
+	SummaryOnly       bool      `json:"summary_only,omitempty"`
+	// paths of the badges relative to the project root keyed by the metric
+	Badges map[string]string `json:"badges"`
+}
+
+// generateDashboard writes the dashboard JSON of the collected reports to CentralConfig.JSON
+func (c *Central) generateDashboard() (string, error) {
+	d := &dashboard{Repositories: []*dashboardRepository{}}
+	for _, r := range c.reports {
+		dr, err := c.dashboardRepository(r)
+		if err != nil {
+			return "", err
+		}
+		d.Repositories = append(d.Repositories, dr)
+	}
+	return c.writeDashboard(d)
+}
+
+// patchDashboard replaces the entry of the collected report in the current dashboard JSON.
+// It returns false if the current dashboard JSON can not be read.
+func (c *Central) patchDashboard(r *report.Report) (string, bool, error) {
+	b, err := os.ReadFile(filepath.Clean(c.config.JSON))
+	if err != nil {
+		return "", false, nil
+	}
+	d := &dashboard{}
+	if err := json.Unmarshal(b, d); err != nil || d.Repositories == nil {
+		return "", false, nil
+	}
+	dr, err := c.dashboardRepository(r)
+	if err != nil {
+		return "", false, err
+	}
+	rs := []*dashboardRepository{dr}
+	for _, e := range d.Repositories {
+		if e.Repository != r.Repository {
+			rs = append(rs, e)
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Repository < rs[j].Repository })
+	d.Repositories = rs
+	p, err := c.writeDashboard(d)
+	if err != nil {
+		return "", false, err
+	}
+	return p, true, nil
+}
+
+func (c *Central) writeDashboard(d *dashboard) (string, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	p := c.config.JSON
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil { // #nosec
+		return "", err
+	}
+	if err := os.WriteFile(p, append(b, '\n'), 0644); err != nil { // #nosec
+		return "", err
+	}
+	return p, nil
+}
+
+func (c *Central) dashboardRepository(r *report.Report) (*dashboardRepository, error) {
+	badgesRel, err := filepath.Rel(c.config.Wd, c.config.Badges)
+	if err != nil {
+		return nil, err
+	}
+	// same badges as generateBadges
+	metrics := []string{badgeMetricCoverage}
+	if r.CodeToTestRatio != nil {
+		metrics = append(metrics, badgeMetricRatio)
+	}
+	if r.TestExecutionTime != nil {
+		metrics = append(metrics, badgeMetricTime)
+	}
+	if c.config.FreshnessColor != nil && !r.Timestamp.IsZero() {
+		metrics = append(metrics, badgeMetricFreshness)
+	}
+	badges := map[string]string{}
+	for _, m := range metrics {
+		bp, err := c.badgePath(r, m)
+		if err != nil {
+			return nil, err
+		}
+		badges[m] = path.Join(filepath.ToSlash(badgesRel), bp)
+	}
+	dr := &dashboardRepository{
+		Repository:        r.Repository,
+		Coverage:          r.CoveragePercent(),
+		TestExecutionTime: r.TestExecutionTime,
+		Timestamp:         r.Timestamp,
+		SummaryOnly:       r.SummaryOnly,
+		Badges:            badges,
+	}
+	if r.CodeToTestRatio != nil {
+		tr := r.CodeToTestRatioRatio()
+		dr.CodeToTestRatio = &tr
+	}
+	return dr, nil
+}
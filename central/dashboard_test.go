@@ -0,0 +1,76 @@
+package central
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestDashboard(t *testing.T) {
+	wd := t.TempDir()
+	ts := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tet := float64(1000)
+	ctr := New(&CentralConfig{
+		Wd:     wd,
+		Badges: filepath.Join(wd, "badges"),
+		JSON:   filepath.Join(wd, "central.json"),
+	})
+	ctr.reports = []*report.Report{
+		{Repository: "owner/a", Timestamp: ts, Coverage: &coverage.Coverage{Covered: 50, Total: 100}, TestExecutionTime: &tet},
+		{Repository: "owner/c", Timestamp: ts, Coverage: &coverage.Coverage{Covered: 10, Total: 100}},
+	}
+	p, err := ctr.generateDashboard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*dashboardRepository{
+		{Repository: "owner/a", Coverage: 50, TestExecutionTime: &tet, Timestamp: ts, Badges: map[string]string{"coverage": "badges/owner/a/coverage.svg", "time": "badges/owner/a/time.svg"}},
+		{Repository: "owner/c", Coverage: 10, Timestamp: ts, Badges: map[string]string{"coverage": "badges/owner/c/coverage.svg"}},
+	}
+	if got := readDashboard(t, p); !cmp.Equal(got.Repositories, want) {
+		t.Errorf("%s", cmp.Diff(got.Repositories, want))
+	}
+
+	// patch
+	if _, ok, err := ctr.patchDashboard(&report.Report{Repository: "owner/b", Timestamp: ts, Coverage: &coverage.Coverage{Covered: 30, Total: 100}}); err != nil || !ok {
+		t.Fatalf("got %v %v", ok, err)
+	}
+	if _, ok, err := ctr.patchDashboard(&report.Report{Repository: "owner/a", Timestamp: ts, Coverage: &coverage.Coverage{Covered: 60, Total: 100}}); err != nil || !ok {
+		t.Fatalf("got %v %v", ok, err)
+	}
+	want = []*dashboardRepository{
+		{Repository: "owner/a", Coverage: 60, Timestamp: ts, Badges: map[string]string{"coverage": "badges/owner/a/coverage.svg"}},
+		{Repository: "owner/b", Coverage: 30, Timestamp: ts, Badges: map[string]string{"coverage": "badges/owner/b/coverage.svg"}},
+		{Repository: "owner/c", Coverage: 10, Timestamp: ts, Badges: map[string]string{"coverage": "badges/owner/c/coverage.svg"}},
+	}
+	if got := readDashboard(t, p); !cmp.Equal(got.Repositories, want) {
+		t.Errorf("%s", cmp.Diff(got.Repositories, want))
+	}
+
+	// can not be patched
+	if err := os.WriteFile(p, []byte("# not JSON"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := ctr.patchDashboard(ctr.reports[0]); err != nil || ok {
+		t.Errorf("got %v %v\nwant false <nil>", ok, err)
+	}
+}
+
+func readDashboard(t *testing.T, p string) *dashboard {
+	t.Helper()
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &dashboard{}
+	if err := json.Unmarshal(b, d); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
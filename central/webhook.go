@@ -0,0 +1,111 @@
+package central
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// SignatureHeader is the header of the HMAC-SHA256 signature of the webhook payload ( same as GitHub webhooks )
+const SignatureHeader = "X-Hub-Signature-256"
+
+const signaturePrefix = "sha256="
+
+// maxWebhookPayloadSize is the max size of the webhook payload
+const maxWebhookPayloadSize = 1 << 20
+
+var repositoryRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// webhookPayload is the payload of the webhook.
+// `client_payload.repository` of repository_dispatch takes precedence over `repository.full_name`.
+type webhookPayload struct {
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	ClientPayload struct {
+		Repository string `json:"repository"`
+	} `json:"client_payload"`
+}
+
+// WebhookHandler receives the notification that a repository updated its report and calls update with the repository
+type WebhookHandler struct {
+	secret []byte
+	update func(repository string) error
+}
+
+func NewWebhookHandler(secret string, update func(repository string) error) *WebhookHandler {
+	return &WebhookHandler{
+		secret: []byte(secret),
+		update: update,
+	}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayloadSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(b) > maxWebhookPayloadSize {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !h.verify(b, r.Header.Get(SignatureHeader)) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	repository, err := webhookRepository(b)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.update(repository); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(w, "accepted: %s\n", repository)
+}
+
+// Sign returns the signature of the payload for SignatureHeader
+func Sign(secret string, payload []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	_, _ = m.Write(payload)
+	return signaturePrefix + hex.EncodeToString(m.Sum(nil))
+}
+
+func (h *WebhookHandler) verify(payload []byte, signature string) bool {
+	if len(h.secret) == 0 || !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(string(h.secret), payload)), []byte(signature))
+}
+
+func webhookRepository(payload []byte) (string, error) {
+	p := &webhookPayload{}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return "", fmt.Errorf("invalid payload: %w", err)
+	}
+	repository := p.ClientPayload.Repository
+	if repository == "" {
+		repository = p.Repository.FullName
+	}
+	if repository == "" {
+		return "", errors.New("invalid payload: repository is not set")
+	}
+	if !repositoryRe.MatchString(repository) {
+		return "", fmt.Errorf("invalid repository: %s", repository)
+	}
+	return repository, nil
+}
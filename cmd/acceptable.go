@@ -0,0 +1,101 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/report"
+	"github.com/spf13/cobra"
+)
+
+// acceptableCmd represents the acceptable command
+var acceptableCmd = &cobra.Command{
+	Use:     "acceptable [REPORT ...]",
+	Short:   "check stored reports against the acceptable conditions of config",
+	Long:    `check stored reports ( octocov report.json ) against the acceptable conditions of the current config without re-measuring.`,
+	Aliases: []string{"check"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		c.Build()
+		paths := args
+		if len(paths) == 0 {
+			switch {
+			case reportPath != "":
+				paths = []string{reportPath}
+			case c.Report != nil && c.Report.Path != "":
+				paths = []string{c.Report.Path}
+			default:
+				return errors.New("REPORT, --report and report.path: are not set")
+			}
+		}
+		failed := 0
+		for _, p := range paths {
+			if err := acceptable(c, p); err != nil {
+				cmd.PrintErrf("NG: %s: %v\n", p, err)
+				failed++
+				continue
+			}
+			cmd.PrintErrf("OK: %s\n", p)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d reports are not acceptable", failed, len(paths))
+		}
+		return nil
+	},
+}
+
+// acceptable checks the stored report against the current config.
+// The coverage filters and the critical paths of the current config are applied to the files of the report.
+func acceptable(c *config.Config, path string) error {
+	r := &report.Report{}
+	if err := r.MeasureCoverage(path, c.CoverageProcessors()...); err != nil {
+		return err
+	}
+	// summary-only reports do not have the files
+	if r.Coverage != nil && len(r.Coverage.Files) > 0 {
+		if err := r.ExcludeCoverageFiles(c.Root(), c.Coverage.Exclude); err != nil {
+			return err
+		}
+		if err := r.Coverage.FilterByExtensions(c.Coverage.IncludeExtensions, c.Coverage.ExcludeExtensions); err != nil {
+			return err
+		}
+		if err := c.CriticalCoverageConfigReady(); err == nil {
+			if err := r.MeasureCriticalCoverage(c.Coverage.Critical.Paths); err != nil {
+				return err
+			}
+		}
+	}
+	return c.Acceptable(r, nil)
+}
+
+func init() {
+	rootCmd.AddCommand(acceptableCmd)
+	acceptableCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	acceptableCmd.Flags().StringVarP(&reportPath, "report", "r", "", "octocov report.json path. default: report.path")
+	acceptableCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-json"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/spf13/cobra"
+)
+
+var (
+	badgeField      string
+	badgeLabel      string
+	badgeStyle      string
+	badgeFormat     string
+	badgeThresholds []string
+	badgeOut        string
+)
+
+// badgeCmd represents the badge command
+var badgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "generate badge of the field of report",
+	Long:  `generate badge of any numeric field of octocov report.json.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := reportPath
+		if path == "" {
+			c, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			c.Build()
+			if c.Report != nil {
+				path = c.Report.Path
+			}
+		}
+		if path == "" {
+			return errors.New("--report and report.path: are not set")
+		}
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return fmt.Errorf("can not parse %s: %w", path, err)
+		}
+		n, err := internal.LookupNumber(v, badgeField)
+		if err != nil {
+			return err
+		}
+		ts, err := badge.ParseThresholds(badgeThresholds)
+		if err != nil {
+			return err
+		}
+		label := badgeLabel
+		if label == "" {
+			label = badgeField
+		}
+		bdg := badge.New(label, fmt.Sprintf(badgeFormat, n))
+		bdg.MessageColor = ts.Color(n)
+		bdg.Style = badgeStyle
+
+		var out io.Writer = os.Stdout
+		if badgeOut != "" {
+			if err := os.MkdirAll(filepath.Dir(badgeOut), 0755); err != nil { // #nosec
+				return err
+			}
+			f, err := os.OpenFile(filepath.Clean(badgeOut), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		return renderBadge(bdg, out, badgeOut)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+	badgeCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	badgeCmd.Flags().StringVarP(&reportPath, "report", "r", "", "octocov report.json path. default: report.path")
+	badgeCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	badgeCmd.Flags().StringVarP(&badgeField, "field", "", "", "dot-separated path of the numeric field (e.g. coverage.covered)")
+	badgeCmd.Flags().StringVarP(&badgeLabel, "label", "", "", "label of the badge. default: --field")
+	badgeCmd.Flags().StringVarP(&badgeStyle, "style", "", "", "style of the badge (flat, flat-square or for-the-badge). default: flat")
+	badgeCmd.Flags().StringVarP(&badgeFormat, "format", "", "%g", "format of the message (fmt verb for float64)")
+	badgeCmd.Flags().StringSliceVarP(&badgeThresholds, "threshold", "", []string{}, "color of the values greater than or equal to MIN (MIN:COLOR, e.g. 80:green)")
+	badgeCmd.Flags().StringVarP(&badgeOut, "out", "o", "", "output badge path (PNG when the extension is .png). default: stdout")
+	if err := badgeCmd.MarkFlagRequired("field"); err != nil {
+		panic(err)
+	}
+}
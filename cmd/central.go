@@ -0,0 +1,260 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/k1LoW/octocov/central"
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/spf13/cobra"
+)
+
+// webhookSecretEnv is the env of the secret to verify the signature of the webhook
+const webhookSecretEnv = "OCTOCOV_WEBHOOK_SECRET"
+
+// centralServeQueueSize is the max number of the pending updates
+const centralServeQueueSize = 100
+
+// centralServeShutdownTimeout is the timeout of waiting for the running webhook handlers on shutdown
+const centralServeShutdownTimeout = 30 * time.Second
+
+var centralServeListen string
+
+// centralCmd represents the central command
+var centralCmd = &cobra.Command{
+	Use:   "central",
+	Short: "commands for central mode",
+	Long:  `commands for central mode.`,
+}
+
+// centralServeCmd represents the central serve command
+var centralServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve the webhook to update the central report of a repository",
+	Long: `serve the webhook to update the central report of a repository.
+The webhook post is authenticated by the HMAC-SHA256 signature (X-Hub-Signature-256) with the secret of env OCTOCOV_WEBHOOK_SECRET.
+The badges and the index row of the repository in the payload are regenerated as 'octocov --only owner/repo'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		secret := os.Getenv(webhookSecretEnv)
+		if secret == "" {
+			return fmt.Errorf("env %s is not set", webhookSecretEnv)
+		}
+		// the server runs long, so mask the secret in all the output of the handlers as well
+		internal.RegisterSecret(secret)
+		c, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if c.Central == nil {
+			c.Central = &config.ConfigCentral{}
+		}
+		c.Central.Enable = true
+		c.Build()
+		if err := c.CentralConfigReady(); err != nil {
+			return err
+		}
+
+		queue := make(chan string, centralServeQueueSize)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			// updates are processed one by one because they share the index and the git repository
+			for repository := range queue {
+				if ctx.Err() != nil {
+					cmd.PrintErrf("Skip update central report (shutting down): %s\n", repository)
+					continue
+				}
+				cmd.PrintErrf("Update central report: %s\n", repository)
+				// the running update is not canceled on shutdown so as not to leave the half-written index
+				if err := updateCentral(context.Background(), cmd, c, repository); err != nil {
+					cmd.PrintErrf("Failed to update central report: %s: %v\n", repository, err)
+				}
+			}
+		}()
+		h := central.NewWebhookHandler(secret, func(repository string) error {
+			select {
+			case queue <- repository:
+				return nil
+			default:
+				return errors.New("too many pending updates")
+			}
+		})
+		mux := http.NewServeMux()
+		mux.Handle("/", h)
+		s := &http.Server{
+			Addr:              centralServeListen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		cmd.PrintErrf("Listen on %s\n", centralServeListen)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- s.ListenAndServe()
+		}()
+		select {
+		case err := <-errc:
+			return err
+		case <-ctx.Done():
+		}
+		cmd.PrintErrln("Shutting down")
+		sctx, cancel := context.WithTimeout(context.Background(), centralServeShutdownTimeout)
+		defer cancel()
+		if err := s.Shutdown(sctx); err != nil {
+			return err
+		}
+		// no more updates are queued after the handlers are finished
+		close(queue)
+		<-done
+		return nil
+	},
+}
+
+// updateCentral regenerates the central report of the repository on the latest tree of the remote, and pushes it
+func updateCentral(ctx context.Context, cmd *cobra.Command, c *config.Config, repository string) error {
+	if err := c.CentralPushConfigReady(); err == nil {
+		retry, err := c.DatastoresRetryPolicy()
+		if err != nil {
+			return err
+		}
+		// the local tree is stale after the previous push ( or the push of others )
+		if err := gh.SyncLocalGit(ctx, c.GitRoot, retry); err != nil {
+			return fmt.Errorf("failed to sync the local git repository: %w", err)
+		}
+	}
+	ctr, err := newCentral(ctx, c)
+	if err != nil {
+		return err
+	}
+	paths, err := ctr.GenerateOnly(ctx, repository)
+	if err != nil {
+		return err
+	}
+	return pushCentral(ctx, cmd, c, paths)
+}
+
+// newCentral reads central.reports.datastores and returns the generator of the central report
+func newCentral(ctx context.Context, c *config.Config) (*central.Central, error) {
+	ttl, signed, err := c.CentralReportsSignedURLTTL()
+	if err != nil {
+		return nil, err
+	}
+	reports := []fs.FS{}
+	signers := []func(ctx context.Context, path string) (string, error){}
+	for _, s := range c.Central.Reports.Datastores {
+		d, err := datastore.New(ctx, s, c.Root())
+		if err != nil {
+			return nil, err
+		}
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, fsys)
+		if !signed {
+			continue
+		}
+		us, ok := d.(datastore.URLSigner)
+		if !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "Skip signing URLs of the reports: %s does not support signed URLs\n", s)
+			signers = append(signers, nil)
+			continue
+		}
+		signers = append(signers, func(ctx context.Context, path string) (string, error) {
+			return us.SignedURL(ctx, path, ttl)
+		})
+	}
+
+	cc := &central.CentralConfig{
+		Repository:             c.Repository,
+		Index:                  c.Central.Root,
+		Wd:                     c.Getwd(),
+		Badges:                 c.Central.Badges.Path,
+		BadgesLayout:           c.Central.Badges.Layout,
+		RepoLinkTemplate:       c.Central.RepoLinkTemplate,
+		Reports:                reports,
+		ReportsLimit:           c.Central.Reports.Limit,
+		IncludeRepositories:    c.Central.Reports.Include,
+		ExcludeRepositories:    c.Central.Reports.Exclude,
+		IndexSort:              c.Central.Index.Sort,
+		IndexTop:               c.Central.Index.Top,
+		IndexGroupBy:           c.Central.Index.GroupBy,
+		JSON:                   c.Central.JSON,
+		ReportURLSigners:       signers,
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+	}
+	staleAfter, err := c.CentralStaleAfter()
+	if err != nil {
+		return nil, err
+	}
+	cc.StaleAfter = staleAfter
+	if f := c.Central.Badges.Freshness; f != nil {
+		cc.FreshnessLabel = f.Label
+		cc.FreshnessColor = c.FreshnessColor
+	}
+	if o := c.Central.Badges.Overall; o != nil {
+		cc.OverallLabel = o.Label
+		cc.OverallWeight = o.Weight
+		if cc.OverallWeight == "" {
+			cc.OverallWeight = central.OverallWeightMean
+		}
+	}
+	return central.New(cc), nil
+}
+
+// pushCentral commits and pushes the generated central report
+func pushCentral(ctx context.Context, cmd *cobra.Command, c *config.Config, paths []string) error {
+	if err := c.CentralPushConfigReady(); err != nil {
+		cmd.PrintErrf("Skip commit and push central report: %v\n", err)
+		return nil
+	}
+	cmd.PrintErrln("Commit and push central report")
+	retry, err := c.DatastoresRetryPolicy()
+	if err != nil {
+		return err
+	}
+	return gh.PushUsingLocalGit(ctx, c.GitRoot, paths, "Update by octocov", retry)
+}
+
+func init() {
+	rootCmd.AddCommand(centralCmd)
+	centralCmd.AddCommand(centralServeCmd)
+	centralServeCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	centralServeCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	centralServeCmd.Flags().StringVarP(&centralServeListen, "listen", "", ":8080", "address to listen on")
+}
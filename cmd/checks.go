@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+)
+
+const defaultChecksName = "octocov"
+
+// checkReport creates the check run with the annotations on the uncovered lines added in the pull request
+func checkReport(ctx context.Context, c *config.Config, r *report.Report) error {
+	owner, repo, err := gh.SplitRepository(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	n, err := g.DetectCurrentPullRequestNumber(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	files, err := g.GetPullRequestFiles(ctx, owner, repo, n)
+	if err != nil {
+		return err
+	}
+	pc, err := r.PatchCoverage(files)
+	if err != nil {
+		return err
+	}
+	annotations := []*gh.CheckAnnotation{}
+	uncovered := 0
+	for _, f := range pc.Files {
+		uncovered += len(f.UncoveredLines)
+		for _, lr := range f.UncoveredLineRanges() {
+			m := fmt.Sprintf("Line %d is not covered by tests", lr.Start)
+			if lr.End > lr.Start {
+				m = fmt.Sprintf("Lines %d-%d are not covered by tests", lr.Start, lr.End)
+			}
+			annotations = append(annotations, &gh.CheckAnnotation{
+				Path:      f.File,
+				StartLine: lr.Start,
+				EndLine:   lr.End,
+				Title:     "Uncovered lines",
+				Message:   m,
+			})
+		}
+	}
+	name := c.Checks.Name
+	if name == "" {
+		name = defaultChecksName
+	}
+	title := fmt.Sprintf("Code coverage of the changed lines: %.1f%% (%d/%d)", pc.Percent, pc.Covered, pc.Total)
+	summary := fmt.Sprintf("%d of the %d changed lines are not covered by tests.", uncovered, pc.Total)
+	conclusion := "success"
+	if uncovered > 0 {
+		conclusion = "neutral"
+	}
+	return g.CreateCheckRun(ctx, owner, repo, n, name, title, summary, conclusion, annotations)
+}
@@ -1,14 +1,39 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
 	"strings"
+	"text/template"
 
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/report"
 )
 
+// commentData is the data of the template of the comment ( comment.template )
+type commentData struct {
+	Repository        string
+	Owner             string
+	Repo              string
+	PullRequestNumber int
+	Report            *report.Report
+	// nil when the previous report is not found
+	Previous *report.Report
+	Diff     *report.DiffReport
+	// error of the acceptable check ( comment.asReview ), or ""
+	Failed        string
+	Table         string
+	RemovedNote   string
+	FileTable     string
+	FunctionTable string
+	NewFileTable  string
+	SlowestTable  string
+	Footer        string
+}
+
 func commentReport(ctx context.Context, c *config.Config, r, rOrig *report.Report) error {
 	owner, repo, err := gh.SplitRepository(c.Repository)
 	if err != nil {
@@ -30,26 +55,166 @@ func commentReport(ctx context.Context, c *config.Config, r, rOrig *report.Repor
 	if c.Comment.HideFooterLink {
 		footer = "Reported by octocov"
 	}
-	var table, fileTable string
+	threshold, err := c.NewFileThreshold()
+	if err != nil {
+		return err
+	}
+	tmpl, err := c.CommentTemplate()
+	if err != nil {
+		return err
+	}
+	var accErr error
+	if c.Comment.AsReview {
+		// the acceptable check uses all measured metrics regardless of comment.metrics
+		accErr = c.Acceptable(r, rOrig)
+		if accErr == nil {
+			accErr = c.AcceptableNewFiles(r, files)
+		}
+	}
+
+	r, err = r.SelectMetrics(c.Comment.Metrics)
+	if err != nil {
+		return fmt.Errorf("comment.metrics: %w", err)
+	}
 	if rOrig != nil {
-		d := rOrig.Compare(r)
+		rOrig, err = rOrig.SelectMetrics(c.Comment.Metrics)
+		if err != nil {
+			return fmt.Errorf("comment.metrics: %w", err)
+		}
+	}
+	depth, err := c.TablesGroupDepth()
+	if err != nil {
+		return err
+	}
+	hideMin, err := c.CommentHideFilesThreshold()
+	if err != nil {
+		return err
+	}
+	slowest, err := c.TestExecutionTimeShowSlowest()
+	if err != nil {
+		return err
+	}
+	newFileTable := r.UncoveredNewFilesTable(files, threshold)
+	var table, removedNote, fileTable, funcTable string
+	var d *report.DiffReport
+	if rOrig != nil {
+		d = rOrig.Compare(r)
+		d.HandlePullRequestFiles(files, c.Diff != nil && c.Diff.ExcludeRemovedFiles)
 		table = d.Table()
-		fileTable = d.FileCoveagesTable(files)
+		removedNote = d.RemovedFilesNote()
+		if depth > 0 {
+			fileTable = d.GroupedFileCoveagesTable(files, depth, hideMin)
+		} else {
+			fileTable = d.FileCoveagesTable(files, hideMin)
+		}
+		funcTable = d.FunctionCoveragesTable(files, hideMin)
 	} else {
 		table = r.Table()
-		fileTable = r.FileCoveagesTable(files)
+		if depth > 0 {
+			fileTable = r.GroupedFileCoveagesTable(files, depth, hideMin)
+		} else {
+			fileTable = r.FileCoveagesTable(files, hideMin)
+		}
 	}
 
-	comment := strings.Join([]string{
-		"## Code Metrics Report",
-		table,
-		"",
-		fileTable,
-		"---",
-		footer,
-	}, "\n")
-	if err := gh.PutComment(ctx, owner, repo, n, comment); err != nil {
+	if c.Comment.ChangedFilesOnly {
+		// the tables above are rendered from the whole reports ( e.g. the removed files of the diff )
+		r = r.ChangedFilesOnly(files)
+		if rOrig != nil {
+			rOrig = rOrig.ChangedFilesOnly(files)
+		}
+		if d != nil {
+			d = d.ChangedFilesOnly(files)
+		}
+	}
+
+	data := &commentData{
+		Repository:        c.Repository,
+		Owner:             owner,
+		Repo:              repo,
+		PullRequestNumber: n,
+		Report:            r,
+		Previous:          rOrig,
+		Diff:              d,
+		Table:             table,
+		RemovedNote:       removedNote,
+		FileTable:         fileTable,
+		FunctionTable:     funcTable,
+		NewFileTable:      newFileTable,
+		SlowestTable:      r.SlowestTestsTable(slowest),
+		Footer:            footer,
+	}
+	if accErr != nil {
+		data.Failed = accErr.Error()
+	}
+	comment, err := renderComment(tmpl, data)
+	if err != nil {
+		return err
+	}
+	if c.Comment.AsReview {
+		event, err := c.ReviewEvent(accErr == nil)
+		if err != nil {
+			return err
+		}
+		submitted, err := gh.PutReview(ctx, owner, repo, n, comment, event)
+		if err != nil {
+			return err
+		}
+		if submitted != event {
+			_, _ = fmt.Fprintf(os.Stderr, "Submitted the review as %s because %s was rejected (e.g. on the pull request of the token owner)\n", submitted, event)
+		}
+		return nil
+	}
+	if err := gh.PutComment(ctx, owner, repo, n, comment, c.CommentUpdate()); err != nil {
 		return err
 	}
 	return nil
 }
+
+// renderComment renders the comment by the template of comment.template, or by the built-in format when tmpl is nil
+func renderComment(tmpl *template.Template, data *commentData) (string, error) {
+	if tmpl != nil {
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, data); err != nil {
+			return "", fmt.Errorf("comment.template: %w", err)
+		}
+		return buf.String(), nil
+	}
+	head := []string{"## Code Metrics Report"}
+	if data.Failed != "" {
+		head = append(head, fmt.Sprintf("**Failed:** %s", data.Failed), "")
+	}
+	head = append(head, data.Table, "")
+	if data.RemovedNote != "" {
+		// the coverage change attributable to removed files is noted separately from the delta
+		head = append(head, data.RemovedNote)
+	}
+	body := append(head,
+		data.FileTable,
+		data.FunctionTable,
+		data.NewFileTable,
+	)
+	if data.SlowestTable != "" {
+		body = append(body, data.SlowestTable)
+	}
+	return strings.Join(append(body,
+		"---",
+		data.Footer,
+	), "\n"), nil
+}
+
+func pullRequestFiles(ctx context.Context, c *config.Config) ([]*gh.PullRequestFile, error) {
+	owner, repo, err := gh.SplitRepository(c.Repository)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return nil, err
+	}
+	n, err := g.DetectCurrentPullRequestNumber(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return g.GetPullRequestFiles(ctx, owner, repo, n)
+}
@@ -22,42 +22,44 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/report"
 	"github.com/spf13/cobra"
 )
 
+var diffRepository string
+
 // diffCmd represents the diff command
 var diffCmd = &cobra.Command{
 	Use:     "diff [REPORT_A] [REPORT_B]",
 	Short:   "compare reports (code coverage report or octocov report.json)",
-	Long:    `compare reports (code coverage report or octocov report.json).`,
+	Long:    `compare reports (code coverage report, octocov report.json or datastore URL where the report of the repository is stored).`,
 	Aliases: []string{"compare"},
 	Args:    cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		a := &report.Report{}
-		if err := a.MeasureCoverage(args[0]); err != nil {
+		ctx := context.Background()
+		c, err := loadConfig(cmd)
+		if err != nil {
 			return err
 		}
-		if a.Timestamp.IsZero() {
-			fi, err := os.Stat(args[0])
-			if err != nil {
-				return err
-			}
-			a.Timestamp = fi.ModTime()
+		c.Build()
+		if diffRepository != "" {
+			c.Repository = diffRepository
 		}
-
-		b := &report.Report{}
-		if err := b.MeasureCoverage(args[1]); err != nil {
+		a, err := diffReport(ctx, c, args[0])
+		if err != nil {
 			return err
 		}
-		if b.Timestamp.IsZero() {
-			fi, err := os.Stat(args[1])
-			if err != nil {
-				return err
-			}
-			b.Timestamp = fi.ModTime()
+		b, err := diffReport(ctx, c, args[1])
+		if err != nil {
+			return err
 		}
 
 		a.Compare(b).Out(os.Stdout)
@@ -65,6 +67,48 @@ var diffCmd = &cobra.Command{
 	},
 }
 
+// diffReport reads the report from the file, or the report of the repository from the datastore.
+// The coverage report of the file is measured in the same way as the root command ( e.g. coverage.exclude: ).
+func diffReport(ctx context.Context, c *config.Config, path string) (*report.Report, error) {
+	if !datastore.IsURL(path) {
+		r := &report.Report{}
+		if err := measureCoverage(c, r, path); err != nil {
+			return nil, err
+		}
+		if r.Timestamp.IsZero() {
+			fi, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			r.Timestamp = fi.ModTime()
+		}
+		return r, nil
+	}
+	if c.Repository == "" {
+		return nil, errors.New("repository: not set (use --repository or env GITHUB_REPOSITORY)")
+	}
+	owner, repo, err := gh.SplitRepository(c.Repository)
+	if err != nil {
+		return nil, err
+	}
+	d, err := datastore.New(ctx, path, c.Root())
+	if err != nil {
+		return nil, err
+	}
+	fsys, err := d.FS()
+	if err != nil {
+		return nil, err
+	}
+	r, err := readReport(fsys, fmt.Sprintf("%s/%s/report.json", owner, repo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the report of %s from %s: %w", c.Repository, path, err)
+	}
+	return r, nil
+}
+
 func init() {
 	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	diffCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	diffCmd.Flags().StringVarP(&diffRepository, "repository", "", "", "repository (owner/repo) of the report in the datastore. default: repository: or env GITHUB_REPOSITORY")
 }
@@ -0,0 +1,122 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffRefsDatastores []string
+	diffRefsRepository string
+)
+
+// diffRefsCmd represents the diff-refs command
+var diffRefsCmd = &cobra.Command{
+	Use:   "diff-refs [REF_A] [REF_B]",
+	Short: "compare reports stored for two git refs",
+	Long:  `compare reports stored for two git refs (requires report.storeByRef: true).`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		c, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		c.Build()
+		repository := c.Repository
+		if diffRefsRepository != "" {
+			repository = diffRefsRepository
+		}
+		if repository == "" {
+			return errors.New("repository: not set (use --repository or env GITHUB_REPOSITORY)")
+		}
+		datastores := diffRefsDatastores
+		if len(datastores) == 0 && c.Report != nil {
+			datastores = c.Report.Datastores
+		}
+		if len(datastores) == 0 {
+			return errors.New("--datastore and report.datastores: are not set")
+		}
+		a, err := reportByRef(ctx, datastores, c.Root(), repository, args[0])
+		if err != nil {
+			return err
+		}
+		b, err := reportByRef(ctx, datastores, c.Root(), repository, args[1])
+		if err != nil {
+			return err
+		}
+		tf, err := c.TestExecutionTimeFormat()
+		if err != nil {
+			return err
+		}
+		b.SetTestExecutionTimeFormat(tf)
+		d := a.Compare(b)
+		d.Out(os.Stdout)
+		cmd.Println("")
+		d.OutFiles(os.Stdout)
+		return nil
+	},
+}
+
+// reportByRef returns the latest report of the repository stored for the ref in the datastores
+func reportByRef(ctx context.Context, datastores []string, configRoot, repository, ref string) (*report.Report, error) {
+	var r *report.Report
+	path := internal.RefReportPath(repository, ref)
+	for _, s := range datastores {
+		d, err := datastore.New(ctx, s, configRoot)
+		if err != nil {
+			return nil, err
+		}
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, err
+		}
+		rt, err := readReport(fsys, path)
+		if err != nil {
+			continue
+		}
+		if r == nil || r.Timestamp.UnixNano() < rt.Timestamp.UnixNano() {
+			r = rt
+		}
+	}
+	if r == nil {
+		return nil, fmt.Errorf("no report is stored for ref %s of %s (%s)", ref, repository, path)
+	}
+	return r, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffRefsCmd)
+	diffRefsCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	diffRefsCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	diffRefsCmd.Flags().StringSliceVarP(&diffRefsDatastores, "datastore", "", []string{}, "datastore (URL) where reports are stored by ref. default: report.datastores")
+	diffRefsCmd.Flags().StringVarP(&diffRefsRepository, "repository", "", "", "repository (owner/repo). default: repository: or env GITHUB_REPOSITORY")
+}
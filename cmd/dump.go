@@ -26,7 +26,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/report"
 	"github.com/k1LoW/octocov/version"
 	"github.com/spf13/cobra"
@@ -40,8 +39,8 @@ var dumpCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 		cmd.PrintErrf("%s version %s\n", version.Name, version.Version)
-		c := config.New()
-		if err := c.Load(configPath); err != nil {
+		c, err := loadConfig(cmd)
+		if err != nil {
 			return err
 		}
 		c.Build()
@@ -58,7 +57,7 @@ var dumpCmd = &cobra.Command{
 			if reportPath != "" {
 				path = reportPath
 			}
-			if err := r.MeasureCoverage(path); err != nil {
+			if err := measureCoverage(c, r, path); err != nil {
 				cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
 			}
 		}
@@ -66,7 +65,7 @@ var dumpCmd = &cobra.Command{
 		if err := c.CodeToTestRatioConfigReady(); err != nil {
 			cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
 		} else {
-			if err := r.MeasureCodeToTestRatio(c.CodeToTestRatio.Code, c.CodeToTestRatio.Test); err != nil {
+			if err := r.MeasureCodeToTestRatio(c.Getwd(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test, c.CodeToTestRatioExclude(), c.CodeToTestRatioLanguages(), c.CodeToTestRatioCountLogicalLines()); err != nil {
 				cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
 			}
 		}
@@ -98,4 +97,5 @@ var dumpCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(dumpCmd)
 	dumpCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
+	dumpCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
 }
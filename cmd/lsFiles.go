@@ -30,21 +30,39 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 	"github.com/lucasb-eyer/go-colorful"
 	"github.com/spf13/cobra"
 )
 
+var (
+	lsFilesSort string
+	lsFilesMin  float64
+	lsFilesMax  float64
+)
+
+const (
+	lsFilesSortPath     = "path"
+	lsFilesSortCoverage = "coverage"
+)
+
 // lsFilesCmd represents the lsFiles command
 var lsFilesCmd = &cobra.Command{
 	Use:   "ls-files",
 	Short: "list files logged in code coverage report",
 	Long:  `list files logged in code coverage report.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := config.New()
-		if err := c.Load(configPath); err != nil {
+		switch lsFilesSort {
+		case lsFilesSortPath, lsFilesSortCoverage:
+		default:
+			return fmt.Errorf("invalid --sort: %s", lsFilesSort)
+		}
+		if lsFilesMin > lsFilesMax {
+			return fmt.Errorf("invalid --min: %g is greater than --max %g", lsFilesMin, lsFilesMax)
+		}
+		c, err := loadConfig(cmd)
+		if err != nil {
 			return err
 		}
 		c.Build()
@@ -59,13 +77,22 @@ var lsFilesCmd = &cobra.Command{
 		if reportPath != "" {
 			path = reportPath
 		}
-		if err := r.MeasureCoverage(path); err != nil {
+		if err := measureCoverage(c, r, path); err != nil {
 			return err
 		}
 		t := 0
+		for _, f := range r.Coverage.Files {
+			if f.Total > t {
+				t = f.Total
+			}
+		}
 		sort.Slice(r.Coverage.Files, func(i int, j int) bool {
-			if r.Coverage.Files[i].Total > t {
-				t = r.Coverage.Files[i].Total
+			if lsFilesSort == lsFilesSortCoverage {
+				ci := fileCoveragePercent(r.Coverage.Files[i].Covered, r.Coverage.Files[i].Total)
+				cj := fileCoveragePercent(r.Coverage.Files[j].Covered, r.Coverage.Files[j].Total)
+				if ci != cj {
+					return ci < cj
+				}
 			}
 			return r.Coverage.Files[i].File < r.Coverage.Files[j].File
 		})
@@ -85,24 +112,10 @@ var lsFilesCmd = &cobra.Command{
 		for _, f := range r.Coverage.Files {
 			cfiles = append(cfiles, f.File)
 		}
-		files := []string{}
-		if err := filepath.Walk(gitRoot, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if info.IsDir() && strings.Contains(path, ".git/") {
-				return filepath.SkipDir
-			}
-			if !info.IsDir() && !strings.Contains(path, ".git/") {
-				files = append(files, path)
-			}
-			return nil
-		}); err != nil {
+		files, err := internal.ListFiles(gitRoot)
+		if err != nil {
 			return err
 		}
-		sort.Slice(files, func(i int, j int) bool {
-			return files[i] < files[j]
-		})
 
 		prefix := internal.DetectPrefix(gitRoot, wd, files, cfiles)
 		for _, f := range r.Coverage.Files {
@@ -111,9 +124,9 @@ var lsFilesCmd = &cobra.Command{
 				continue
 			}
 			trimed := strings.TrimPrefix(strings.TrimPrefix(p, prefix), "/")
-			cover := float64(f.Covered) / float64(f.Total) * 100
-			if f.Total == 0 {
-				cover = 0.0
+			cover := fileCoveragePercent(f.Covered, f.Total)
+			if cover < lsFilesMin || cover > lsFilesMax {
+				continue
 			}
 			cl := c.CoverageColor(cover)
 			c, err := detectTermColor(cl)
@@ -128,6 +141,13 @@ var lsFilesCmd = &cobra.Command{
 	},
 }
 
+func fileCoveragePercent(covered, total int) float64 {
+	if total == 0 {
+		return 0.0
+	}
+	return float64(covered) / float64(total) * 100
+}
+
 func detectTermColor(cl string) (*color.Color, error) {
 	termGreen, _ := colorful.Hex("#4e9a06")
 	termYellow, _ := colorful.Hex("#c4a000")
@@ -158,4 +178,8 @@ func detectTermColor(cl string) (*color.Color, error) {
 func init() {
 	rootCmd.AddCommand(lsFilesCmd)
 	lsFilesCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
+	lsFilesCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	lsFilesCmd.Flags().StringVarP(&lsFilesSort, "sort", "", lsFilesSortPath, "sort order of the files (path, coverage)")
+	lsFilesCmd.Flags().Float64VarP(&lsFilesMin, "min", "", 0, "list only the files whose coverage is greater than or equal to MIN (%)")
+	lsFilesCmd.Flags().Float64VarP(&lsFilesMax, "max", "", 100, "list only the files whose coverage is less than or equal to MAX (%)")
 }
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+)
+
+// previousReport returns the latest report of diff.path and diff.datastores to compare with the current report
+func previousReport(ctx context.Context, c *config.Config) (*report.Report, error) {
+	var r2 *report.Report
+	owner, repo, err := gh.SplitRepository(c.Repository)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("%s/%s/report.json", owner, repo)
+	policy, err := c.DiffRetryPolicy()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range c.Diff.Datastores {
+		d, err := datastore.New(ctx, s, c.Root())
+		if err != nil {
+			return nil, err
+		}
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, err
+		}
+		var rt *report.Report
+		if err := policy.Do(ctx, func() error {
+			var err error
+			rt, err = readReport(fsys, path)
+			return err
+		}); err != nil {
+			continue
+		}
+		if r2 == nil || r2.Timestamp.UnixNano() < rt.Timestamp.UnixNano() {
+			r2 = rt
+		}
+	}
+	if c.Diff.Path != "" {
+		rt, err := report.New()
+		if err != nil {
+			return nil, err
+		}
+		if err := measureCoverage(c, rt, c.Diff.Path); err == nil {
+			if r2 == nil || r2.Timestamp.UnixNano() < rt.Timestamp.UnixNano() {
+				r2 = rt
+			}
+		}
+	}
+	if err := c.DiffStale(r2, time.Now()); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return r2, nil
+}
+
+func readReport(fsys fs.FS, path string) (*report.Report, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	r := &report.Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
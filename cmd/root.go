@@ -23,22 +23,22 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/k1LoW/octocov/central"
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/datastore"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/notification"
 	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/k1LoW/octocov/pkg/coverage"
 	"github.com/k1LoW/octocov/report"
 	"github.com/k1LoW/octocov/version"
 	"github.com/spf13/cobra"
@@ -46,10 +46,28 @@ import (
 
 var (
 	configPath    string
+	coveragePath  string
 	coverageBadge bool
 	ratioBadge    bool
 	timeBadge     bool
+	locBadge      bool
 	createTable   bool
+	noPreflight   bool
+	strictPaths   bool
+	centralMode   bool
+	onlyRepo      string
+	gitRoot       string
+	outputFormat  string
+	dump          bool
+	checkOnly     bool
+	strictConfig  bool
+	// percentage of unresolved files allowed with --strict-paths
+	strictPathsThreshold float64
+)
+
+const (
+	outputFormatTable   = "table"
+	outputFormatSummary = "summary"
 )
 
 var rootCmd = &cobra.Command{
@@ -63,61 +81,93 @@ var rootCmd = &cobra.Command{
 		addPaths := []string{}
 		cmd.PrintErrf("%s version %s\n", version.Name, version.Version)
 
-		c := config.New()
-		if err := c.Load(configPath); err != nil {
+		switch outputFormat {
+		case outputFormatTable, outputFormatSummary:
+		default:
+			return fmt.Errorf("invalid --format: %s", outputFormat)
+		}
+
+		c, err := loadConfig(cmd)
+		if err != nil {
 			return err
 		}
 		if !c.Loaded() {
 			cmd.PrintErrf("%s are not found\n", strings.Join(config.DefaultConfigFilePaths, " and "))
 		}
 
+		if centralMode {
+			if c.Central == nil {
+				c.Central = &config.ConfigCentral{}
+			}
+			c.Central.Enable = true
+		}
+
+		if coveragePath != "" {
+			if c.Coverage == nil {
+				c.Coverage = &config.ConfigCoverage{}
+			}
+			c.Coverage.Path = coveragePath
+		}
+
+		if gitRoot != "" {
+			p, err := filepath.Abs(gitRoot)
+			if err != nil {
+				return err
+			}
+			c.GitRoot = p
+		}
+
 		c.Build()
 
+		if onlyRepo != "" && (c.Central == nil || !c.Central.Enable) {
+			return errors.New("--only is available only in central mode")
+		}
+
+		if dump && c.Central != nil && c.Central.Enable {
+			return errors.New("--dump is not available in central mode")
+		}
+
+		if checkOnly {
+			if c.Central != nil && c.Central.Enable {
+				return errors.New("--check-only is not available in central mode")
+			}
+			if coverageBadge || ratioBadge || timeBadge || locBadge {
+				return errors.New("--check-only is not available with the badge flags ( e.g. --coverage-badge )")
+			}
+			c.SetCheckOnly(true)
+		}
+
 		if createTable {
 			return createBQTable(ctx, c)
 		}
 
+		if !noPreflight {
+			if err := c.Preflight(); err != nil {
+				return fmt.Errorf("%w\n(use --no-preflight to skip the preflight check)", err)
+			}
+		}
+
 		if c.Central != nil && c.Central.Enable {
 			cmd.PrintErrln("Central mode enabled")
 			if err := c.CentralConfigReady(); err != nil {
 				return err
 			}
 
-			reports := []fs.FS{}
-			for _, s := range c.Central.Reports.Datastores {
-				d, err := datastore.New(ctx, s, c.Root())
-				if err != nil {
-					return err
-				}
-				fsys, err := d.FS()
-				if err != nil {
-					return err
-				}
-				reports = append(reports, fsys)
-			}
-
-			ctr := central.New(&central.CentralConfig{
-				Repository:             c.Repository,
-				Index:                  c.Central.Root,
-				Wd:                     c.Getwd(),
-				Badges:                 c.Central.Badges,
-				Reports:                reports,
-				CoverageColor:          c.CoverageColor,
-				CodeToTestRatioColor:   c.CodeToTestRatioColor,
-				TestExecutionTimeColor: c.TestExecutionTimeColor,
-			})
-			paths, err := ctr.Generate(ctx)
+			ctr, err := newCentral(ctx, c)
 			if err != nil {
 				return err
 			}
-			// git push
-			if err := c.CentralPushConfigReady(); err != nil {
-				cmd.PrintErrf("Skip commit and push central report: %v\n", err)
+			var paths []string
+			if onlyRepo != "" {
+				paths, err = ctr.GenerateOnly(ctx, onlyRepo)
 			} else {
-				cmd.PrintErrln("Commit and push central report")
-				if err := gh.PushUsingLocalGit(ctx, c.GitRoot, paths, "Update by octocov"); err != nil {
-					return err
-				}
+				paths, err = ctr.Generate(ctx)
+			}
+			if err != nil {
+				return err
+			}
+			if err := pushCentral(ctx, cmd, c, paths); err != nil {
+				return err
 			}
 			return nil
 		}
@@ -126,26 +176,84 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		tf, err := c.TestExecutionTimeFormat()
+		if err != nil {
+			return err
+		}
+		r.SetTestExecutionTimeFormat(tf)
+		slowest, err := c.TestExecutionTimeShowSlowest()
+		if err != nil {
+			return err
+		}
+		r.SetShowSlowest(slowest)
+
+		if c.Report != nil {
+			switch c.Report.TimestampSource {
+			case "", config.TimestampSourceNow:
+			case config.TimestampSourceCommit:
+				if err := r.SetTimestampFromCommit(c.GitRoot); err != nil {
+					cmd.PrintErrf("Use the current time as the timestamp of the report: %v\n", err)
+				}
+			default:
+				return fmt.Errorf("invalid report.timestampSource: %s", c.Report.TimestampSource)
+			}
+			if c.Report.IncludeAuthor {
+				if err := r.SetAuthors(c.GitRoot); err != nil {
+					cmd.PrintErrf("Skip setting the commit author of the report: %v\n", err)
+				}
+			}
+		}
 
 		if err := c.CoverageConfigReady(); err != nil {
 			cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
 		} else {
 			path := c.Coverage.Path
-			if err := r.MeasureCoverage(path); err != nil {
+			if err := measureCoverage(c, r, path); err != nil {
 				cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
 			}
 		}
 
+		if err := c.CriticalCoverageConfigReady(); err == nil && r.IsMeasuredCoverage() {
+			if err := r.MeasureCriticalCoverage(c.Coverage.Critical.Paths); err != nil {
+				cmd.PrintErrf("Skip measuring code coverage of critical paths: %v\n", err)
+			}
+		}
+
+		if strictPaths && r.IsMeasuredCoverage() {
+			if err := checkUnresolvedFiles(c, r); err != nil {
+				return err
+			}
+		}
+
 		if err := c.CodeToTestRatioConfigReady(); err != nil {
 			cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
 		} else {
-			if err := r.MeasureCodeToTestRatio(c.CodeToTestRatio.Code, c.CodeToTestRatio.Test); err != nil {
+			if err := r.MeasureCodeToTestRatio(c.Getwd(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test, c.CodeToTestRatioExclude(), c.CodeToTestRatioLanguages(), c.CodeToTestRatioCountLogicalLines()); err != nil {
 				cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
 			}
 		}
 
+		if err := c.LinesOfCodeConfigReady(); err != nil {
+			cmd.PrintErrf("Skip measuring lines of code: %v\n", err)
+		} else {
+			if err := r.MeasureLinesOfCode(c.Getwd(), c.LinesOfCode.Exclude); err != nil {
+				cmd.PrintErrf("Skip measuring lines of code: %v\n", err)
+			}
+		}
+
 		if err := c.TestExecutionTimeConfigReady(); err != nil {
 			cmd.PrintErrf("Skip measuring test execution time: %v\n", err)
+		} else if c.TestExecutionTime.Path != "" {
+			if err := r.MeasureTestResults(c.TestExecutionTime.Path); err != nil {
+				cmd.PrintErrf("Skip measuring test execution time: %v\n", err)
+			}
+		} else if len(c.TestExecutionTime.Steps) == 0 && (len(c.TestExecutionTime.StepsInclude) > 0 || len(c.TestExecutionTime.StepsExclude) > 0) {
+			names, err := r.MeasureTestExecutionTimeByStepPatterns(ctx, c.TestExecutionTime.StepsInclude, c.TestExecutionTime.StepsExclude)
+			if err != nil {
+				cmd.PrintErrf("Skip measuring test execution time: %v\n", err)
+			} else {
+				cmd.PrintErrf("Sum the execution time of steps: %s\n", strings.Join(names, ", "))
+			}
 		} else {
 			stepNames := []string{}
 			if len(c.TestExecutionTime.Steps) > 0 {
@@ -160,11 +268,56 @@ var rootCmd = &cobra.Command{
 			return errors.New("nothing could be measured")
 		}
 
-		cmd.Println("")
-		if err := r.Out(os.Stdout); err != nil {
-			return err
+		// Fetch the previous report once, before storing the report may overwrite it
+		var (
+			r2    *report.Report
+			r2Err error
+		)
+		acceptableDiff := (c.Coverage != nil && c.Coverage.Acceptable.Diff != "") || (c.TestExecutionTime != nil && c.TestExecutionTime.Acceptable.Diff != "")
+		showDiff := c.Coverage != nil && c.Coverage.Badge.ShowDiff
+		if err := c.DiffConfigReady(); err != nil {
+			r2Err = err
+		} else if outputFormat == outputFormatSummary || r.IsMeasuredCoverage() || c.CommentConfigReady() == nil || c.NotificationsWebhookConfigReady() == nil || acceptableDiff || showDiff {
+			r2, r2Err = previousReport(ctx, c)
+		}
+
+		if r.IsMeasuredCoverage() {
+			checkStaleCoverage(cmd, c, r, r2)
+		}
+
+		if dump {
+			// print the measured report without generating badges, commenting, storing or pushing
+			cmd.Println(r.String())
+			return nil
+		}
+
+		switch outputFormat {
+		case outputFormatSummary:
+			if r2Err != nil && c.DiffConfigReady() == nil {
+				cmd.PrintErrf("Skip comparing reports: %v\n", r2Err)
+			}
+			result := "PASS"
+			if err := c.Acceptable(r, r2); err != nil {
+				result = "FAIL"
+			}
+			cmd.Printf("%s | %s\n", r.Summary(r2), result)
+		default:
+			cmd.Println("")
+			if err := r.Out(os.Stdout); err != nil {
+				return err
+			}
+			cmd.Println("")
+			depth, ok, err := c.PerPackageDepth()
+			if err != nil {
+				return err
+			}
+			if ok && r.IsMeasuredCoverage() {
+				if err := r.PackageCoveragesOut(os.Stdout, depth); err != nil {
+					return err
+				}
+				cmd.Println("")
+			}
 		}
-		cmd.Println("")
 
 		// Generate coverage report badge
 		if err := c.CoverageBadgeConfigReady(); err == nil || coverageBadge {
@@ -173,6 +326,11 @@ var rootCmd = &cobra.Command{
 					cmd.PrintErrf("Skip generating badge: %s\n", "coverage is not measured")
 					return nil
 				}
+				// resolve the logo before truncating the badge
+				logo, err := badge.ResolveLogo(ctx, c.CoverageBadgeLogo())
+				if err != nil {
+					return fmt.Errorf("coverage.badge.logo: %w", err)
+				}
 				var out *os.File
 				cp := r.CoveragePercent()
 				if c.Coverage.Badge.Path == "" {
@@ -194,9 +352,17 @@ var rootCmd = &cobra.Command{
 					addPaths = append(addPaths, bp)
 				}
 
-				b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
+				msg := fmt.Sprintf("%.1f%%", cp)
+				if showDiff && r2Err == nil {
+					if trend := r.CoverageTrend(r2); trend != "" {
+						msg = fmt.Sprintf("%s %s", msg, trend)
+					}
+				}
+				b := badge.New(c.CoverageBadgeLabel(), msg)
 				b.MessageColor = c.CoverageColor(cp)
-				if err := b.Render(out); err != nil {
+				b.Style = c.CoverageBadgeStyle()
+				b.Logo = logo
+				if err := renderBadge(b, out, c.Coverage.Badge.Path); err != nil {
 					return err
 				}
 				return nil
@@ -208,6 +374,40 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// Generate critical-coverage report badge
+		if err := c.CriticalCoverageBadgeConfigReady(); err == nil {
+			if err := func() error {
+				if !r.IsMeasuredCriticalCoverage() {
+					cmd.PrintErrf("Skip generating badge: %s\n", "critical-coverage is not measured")
+					return nil
+				}
+				cmd.PrintErrln("Generate critical-coverage report badge...")
+				err := os.MkdirAll(filepath.Dir(c.Coverage.Critical.Badge.Path), 0755) // #nosec
+				if err != nil {
+					return err
+				}
+				bp, err := filepath.Abs(filepath.Clean(c.Coverage.Critical.Badge.Path))
+				if err != nil {
+					return err
+				}
+				out, err := os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+				if err != nil {
+					return err
+				}
+				addPaths = append(addPaths, bp)
+
+				cp := r.CriticalCoverage.Percent()
+				b := badge.New("critical coverage", fmt.Sprintf("%.1f%%", cp))
+				b.MessageColor = c.CoverageColor(cp)
+				if err := renderBadge(b, out, c.Coverage.Critical.Badge.Path); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		}
+
 		// Generate code-to-test-ratio report badge
 		if err := c.CodeToTestRatioBadgeConfigReady(); err == nil || ratioBadge {
 			if err := func() error {
@@ -237,9 +437,9 @@ var rootCmd = &cobra.Command{
 					addPaths = append(addPaths, bp)
 				}
 
-				b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
+				b := badge.New(c.CodeToTestRatioBadgeLabel(), fmt.Sprintf("1:%.1f", tr))
 				b.MessageColor = c.CodeToTestRatioColor(tr)
-				if err := b.Render(out); err != nil {
+				if err := renderBadge(b, out, c.CodeToTestRatio.Badge.Path); err != nil {
 					return err
 				}
 				return nil
@@ -281,9 +481,9 @@ var rootCmd = &cobra.Command{
 				}
 
 				d := time.Duration(*r.TestExecutionTime)
-				b := badge.New("test execution time", d.String())
+				b := badge.New("test execution time", r.TestExecutionTimeString())
 				b.MessageColor = c.TestExecutionTimeColor(d)
-				if err := b.Render(out); err != nil {
+				if err := renderBadge(b, out, c.TestExecutionTime.Badge.Path); err != nil {
 					return err
 				}
 				return nil
@@ -296,58 +496,59 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// Generate lines-of-code report badge
+		if err := c.LinesOfCodeBadgeConfigReady(); err == nil || locBadge {
+			if err := func() error {
+				if !r.IsMeasuredLinesOfCode() {
+					cmd.PrintErrf("Skip generating badge: %s\n", "lines-of-code is not measured")
+					return nil
+				}
+
+				var out *os.File
+				if c.LinesOfCode.Badge.Path == "" {
+					out = os.Stdout
+				} else {
+					cmd.PrintErrln("Generate lines-of-code report badge...")
+					err := os.MkdirAll(filepath.Dir(c.LinesOfCode.Badge.Path), 0755) // #nosec
+					if err != nil {
+						return err
+					}
+					bp, err := filepath.Abs(filepath.Clean(c.LinesOfCode.Badge.Path))
+					if err != nil {
+						return err
+					}
+					out, err = os.OpenFile(bp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+					if err != nil {
+						return err
+					}
+					addPaths = append(addPaths, bp)
+				}
+
+				b := badge.New("lines of code", r.LinesOfCode.String())
+				b.MessageColor = c.LinesOfCodeColor()
+				if err := renderBadge(b, out, c.LinesOfCode.Badge.Path); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+
+			if locBadge {
+				return nil
+			}
+		}
+
 		// Comment report to pull request
 		if err := c.CommentConfigReady(); err != nil {
 			cmd.PrintErrf("Skip commenting report to pull request: %v\n", err)
 		} else {
 			if err := func() error {
 				cmd.PrintErrln("Commenting report...")
-				var r2 *report.Report
 				if err := c.DiffConfigReady(); err != nil {
 					cmd.PrintErrf("Skip comparing reports: %v\n", err)
-				} else {
-					owner, repo, err := gh.SplitRepository(c.Repository)
-					if err != nil {
-						return err
-					}
-					path := fmt.Sprintf("%s/%s/report.json", owner, repo)
-					for _, s := range c.Diff.Datastores {
-						d, err := datastore.New(ctx, s, c.Root())
-						if err != nil {
-							return err
-						}
-						fsys, err := d.FS()
-						if err != nil {
-							return err
-						}
-						f, err := fsys.Open(path)
-						if err != nil {
-							continue
-						}
-						defer f.Close()
-						b, err := io.ReadAll(f)
-						if err != nil {
-							continue
-						}
-						rt := &report.Report{}
-						if err := json.Unmarshal(b, rt); err != nil {
-							continue
-						}
-						if r2 == nil || r2.Timestamp.UnixNano() < rt.Timestamp.UnixNano() {
-							r2 = rt
-						}
-					}
-					if c.Diff.Path != "" {
-						rt, err := report.New()
-						if err != nil {
-							return err
-						}
-						if err := rt.MeasureCoverage(c.Diff.Path); err == nil {
-							if r2 == nil || r2.Timestamp.UnixNano() < rt.Timestamp.UnixNano() {
-								r2 = rt
-							}
-						}
-					}
+				} else if r2Err != nil {
+					return r2Err
 				}
 				if err := commentReport(ctx, c, r, r2); err != nil {
 					return err
@@ -358,6 +559,35 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// Create check run with annotations on uncovered lines
+		if err := c.ChecksConfigReady(); err != nil {
+			cmd.PrintErrf("Skip creating the check run: %v\n", err)
+		} else {
+			cmd.PrintErrln("Creating check run...")
+			if err := checkReport(ctx, c, r); err != nil {
+				cmd.PrintErrf("Skip creating the check run: %v\n", err)
+			}
+		}
+
+		// Send report to webhook
+		if err := c.NotificationsWebhookConfigReady(); err != nil {
+			cmd.PrintErrf("Skip sending the report to webhook: %v\n", err)
+		} else {
+			cmd.PrintErrln("Sending report to webhook...")
+			var d *report.DiffReport
+			if err := c.DiffConfigReady(); err == nil {
+				if r2Err != nil {
+					cmd.PrintErrf("Skip comparing reports: %v\n", r2Err)
+				} else if r2 != nil {
+					d = r2.Compare(r)
+				}
+			}
+			wh := c.Notifications.Webhook
+			if err := notification.NewWebhook(wh.URL, wh.Method, wh.Headers, wh.Body).Send(ctx, r, d); err != nil {
+				cmd.PrintErrf("Failed to send the report to webhook: %v\n", err)
+			}
+		}
+
 		// Store report
 		if err := c.ReportConfigReady(); err != nil {
 			cmd.PrintErrf("Skip storing the report: %v\n", err)
@@ -373,22 +603,45 @@ var rootCmd = &cobra.Command{
 				}
 				addPaths = append(addPaths, rp)
 			}
-			if r.Coverage != nil {
-				r.Coverage.FlushBlockCoverages()
+			retry, err := c.DatastoresRetryPolicy()
+			if err != nil {
+				return err
 			}
-			datastores := []datastore.Datastore{}
-			for _, s := range c.Report.Datastores {
-				d, err := datastore.New(ctx, s, c.Root())
+			if c.Report.PatchPath != "" {
+				pc, pp, err := writePatchCoverage(ctx, c, r)
 				if err != nil {
-					return err
+					cmd.PrintErrf("Skip writing the patch coverage: %v\n", err)
+				} else {
+					addPaths = append(addPaths, pp)
+					for _, res := range datastore.StorePatchCoverageAll(ctx, c.Report.Datastores, c.Root(), pc, c.Report.Concurrency, retry) {
+						if res.Err != nil {
+							cmd.PrintErrf("Skip storing the patch coverage to %s: %v\n", res.Datastore, res.Err)
+							continue
+						}
+						cmd.PrintErrf("Stored the patch coverage to %s\n", res.Datastore)
+					}
 				}
-				datastores = append(datastores, d)
 			}
-			for _, d := range datastores {
-				if err := d.Store(ctx, r); err != nil {
+			if c.Report.MetricsPath != "" {
+				if err := writeMetrics(c.Report.MetricsPath, r); err != nil {
 					return err
 				}
 			}
+			if r.Coverage != nil {
+				r.Coverage.FlushBlockCoverages()
+			}
+			failed := []string{}
+			for _, res := range datastore.StoreAll(ctx, c.Report.Datastores, c.Root(), r, c.Report.Concurrency, c.Report.StoreByRef, retry) {
+				if res.Err != nil {
+					cmd.PrintErrf("Failed to store the report to %s: %v\n", res.Datastore, res.Err)
+					failed = append(failed, res.Datastore)
+					continue
+				}
+				cmd.PrintErrf("Stored the report to %s\n", res.Datastore)
+			}
+			if len(failed) > 0 && !c.Report.BestEffort {
+				return fmt.Errorf("failed to store the report to %s", strings.Join(failed, ", "))
+			}
 		}
 
 		// Push generated files
@@ -396,29 +649,213 @@ var rootCmd = &cobra.Command{
 			cmd.PrintErrf("Skip pushing generate files: %v\n", err)
 		} else {
 			cmd.PrintErrln("Pushing generated files...")
-			if err := gh.PushUsingLocalGit(ctx, c.GitRoot, addPaths, "Update by octocov"); err != nil {
+			retry, err := c.DatastoresRetryPolicy()
+			if err != nil {
+				return err
+			}
+			if err := gh.PushUsingLocalGit(ctx, c.GitRoot, addPaths, "Update by octocov", retry); err != nil {
 				return err
 			}
 		}
 
 		// Check for acceptable code metrics
-		if err := c.Acceptable(r); err != nil {
+		if r2Err != nil && acceptableDiff {
+			cmd.PrintErrf("Skip checking the changes from the previous report: %v\n", r2Err)
+		}
+		if err := c.Acceptable(r, r2); err != nil {
 			return err
 		}
+		if c.Diff != nil && c.Diff.Acceptable != nil && c.Diff.Acceptable.NoUncoveredNewFiles {
+			files, err := pullRequestFiles(ctx, c)
+			if err != nil {
+				cmd.PrintErrf("Skip checking new files in pull request: %v\n", err)
+			} else if err := c.AcceptableNewFiles(r, files); err != nil {
+				return err
+			}
+		}
 
 		return nil
 	},
 }
 
+// measureCoverage measures the coverage and counts only the files not matched by coverage.exclude and of coverage.includeExtensions and coverage.excludeExtensions
+func measureCoverage(c *config.Config, r *report.Report, path string) error {
+	if err := r.MeasureCoverage(path, c.CoverageProcessors()...); err != nil {
+		return err
+	}
+	if r.Coverage == nil || len(r.Coverage.Files) == 0 {
+		// summary-only reports do not have the files
+		return nil
+	}
+	if r.Coverage.Format == coverage.IstanbulFormat {
+		// Istanbul JSON report has absolute file paths
+		if err := r.Coverage.TrimRoot(c.Root()); err != nil {
+			return err
+		}
+	}
+	if err := r.ExcludeCoverageFiles(c.Root(), c.Coverage.Exclude); err != nil {
+		r.Coverage = nil
+		return err
+	}
+	if err := r.Coverage.FilterByExtensions(c.Coverage.IncludeExtensions, c.Coverage.ExcludeExtensions); err != nil {
+		r.Coverage = nil
+		return err
+	}
+	return nil
+}
+
+// writePatchCoverage writes the coverage of the lines changed in the pull request to report.patchPath
+func writePatchCoverage(ctx context.Context, c *config.Config, r *report.Report) (*report.PatchCoverage, string, error) {
+	files, err := pullRequestFiles(ctx, c)
+	if err != nil {
+		return nil, "", err
+	}
+	pc, err := r.PatchCoverage(files)
+	if err != nil {
+		return nil, "", err
+	}
+	pp, err := filepath.Abs(filepath.Clean(c.Report.PatchPath))
+	if err != nil {
+		return nil, "", err
+	}
+	if err := os.WriteFile(pp, pc.Bytes(), os.ModePerm); err != nil {
+		return nil, "", err
+	}
+	return pc, pp, nil
+}
+
+// writeMetrics writes the metrics of the report in Prometheus exposition format to report.metricsPath.
+// The file is replaced by rename so that the textfile collector does not read the file being written.
+func writeMetrics(path string, r *report.Report) error {
+	mp, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(mp), fmt.Sprintf(".%s.*", filepath.Base(mp)))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if err := r.PrometheusOut(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil { // #nosec
+		return err
+	}
+	return os.Rename(f.Name(), mp)
+}
+
+// loadConfig loads the config of configPath and checks the unknown fields ( fails with --strict, warns otherwise )
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	c := config.New()
+	if err := c.Load(configPath); err != nil {
+		return nil, err
+	}
+	if err := c.UnknownFields(); err != nil {
+		if strictConfig {
+			return nil, fmt.Errorf("unknown field in the config: %w\n(remove --strict to ignore unknown fields)", err)
+		}
+		cmd.PrintErrf("Warning: unknown field in the config (ignored): %v\n", err)
+	}
+	return c, nil
+}
+
+// renderBadge renders the badge as PNG when the path has the .png extension, otherwise as SVG
+func renderBadge(b *badge.Badge, out io.Writer, path string) error {
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		return b.RenderPNG(out)
+	}
+	return b.Render(out)
+}
+
+// checkStaleCoverage warns when the coverage report is the same as that of the previous report ( report.path or the fetched previous report of diff: ) although the source files changed
+func checkStaleCoverage(cmd *cobra.Command, c *config.Config, r *report.Report, r2 *report.Report) {
+	root := c.GitRoot
+	if root == "" {
+		root = c.Getwd()
+	}
+	if err := r.MeasureSourceChecksum(root, c.Getwd()); err != nil {
+		return
+	}
+	prevs := []*report.Report{}
+	if c.Report != nil && c.Report.Path != "" {
+		if prev, err := report.New(); err == nil {
+			if err := measureCoverage(c, prev, c.Report.Path); err == nil {
+				prevs = append(prevs, prev)
+			}
+		}
+	}
+	if r2 != nil {
+		prevs = append(prevs, r2)
+	}
+	for _, prev := range prevs {
+		if r.IsStaleCoverage(prev) {
+			cmd.PrintErrf("Warning: the coverage report %s is the same as that of the previous report, but the source files have changed. The coverage may be stale (forgot to regenerate the coverage report?)\n", c.Coverage.Path)
+			return
+		}
+	}
+}
+
+const unresolvedFilesSampleMax = 5
+
+func checkUnresolvedFiles(c *config.Config, r *report.Report) error {
+	root := c.GitRoot
+	if root == "" {
+		root = c.Getwd()
+	}
+	unresolved, total, err := r.UnresolvedFiles(root, c.Getwd())
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+	p := float64(len(unresolved)) / float64(total) * 100
+	if p <= strictPathsThreshold {
+		return nil
+	}
+	sample := unresolved
+	if len(sample) > unresolvedFilesSampleMax {
+		sample = sample[:unresolvedFilesSampleMax]
+	}
+	return fmt.Errorf("%d of %d files in the coverage report could not be resolved (%.1f%%, which is above the accepted %.1f%%): %s", len(unresolved), total, p, strictPathsThreshold, strings.Join(sample, ", "))
+}
+
 func init() {
 	rootCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	rootCmd.Flags().StringVarP(&coveragePath, "coverage", "", "", "coverage report path ( overrides coverage.path: of the config, \"-\" reads the coverage report from stdin )")
 	rootCmd.Flags().BoolVarP(&coverageBadge, "coverage-badge", "", false, "generate coverage report badge")
 	rootCmd.Flags().BoolVarP(&ratioBadge, "code-to-test-ratio-badge", "", false, "generate code-to-test-ratio report badge")
 	rootCmd.Flags().BoolVarP(&timeBadge, "test-execution-time-badge", "", false, "generate test-execution-time report badge")
+	rootCmd.Flags().BoolVarP(&locBadge, "lines-of-code-badge", "", false, "generate lines-of-code report badge")
 	rootCmd.Flags().BoolVarP(&createTable, "create-bq-table", "", false, "create table of BigQuery dataset")
+	rootCmd.Flags().BoolVarP(&noPreflight, "no-preflight", "", false, "skip checking credentials and contexts required by enabled features")
+	rootCmd.Flags().BoolVarP(&centralMode, "central", "", false, "enable central mode")
+	rootCmd.Flags().StringVarP(&gitRoot, "git-root", "", "", "root path of the git repository ( bypasses the traversal of the git root )")
+	rootCmd.Flags().StringVarP(&onlyRepo, "only", "", "", "regenerate only the badges and the index row of the repository (owner/repo) in central mode")
+	rootCmd.Flags().BoolVarP(&strictPaths, "strict-paths", "", false, "fail when files in the coverage report can not be resolved to files on disk")
+	rootCmd.Flags().StringVarP(&outputFormat, "format", "", outputFormatTable, "output format of the report (table, summary)")
+	rootCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	rootCmd.Flags().BoolVarP(&checkOnly, "check-only", "", false, "measure and check the acceptable code metrics only, without commenting, storing or pushing")
+	rootCmd.Flags().BoolVarP(&dump, "dump", "", false, "print the measured report as JSON without generating badges, commenting, storing or pushing")
+	rootCmd.Flags().Float64VarP(&strictPathsThreshold, "strict-paths-threshold", "", 0, "percentage of unresolved files allowed with --strict-paths")
 }
 
 func Execute() {
+	// mask secrets in all stderr output ( including log output and subprocesses )
+	internal.RegisterSecretsFromEnv()
+	restore, err := internal.RedactStderr()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to mask secrets in output: %v\n", err)
+		restore = func() {}
+	}
+
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.SetErr(os.Stderr)
 
@@ -428,6 +865,8 @@ func Execute() {
 	}
 
 	if err := rootCmd.Execute(); err != nil {
+		restore()
 		os.Exit(1)
 	}
+	restore()
 }
@@ -0,0 +1,125 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "validate config",
+	Long:  `validate config and show the features that are enabled or skipped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := config.New()
+		if err := c.Load(configPath); err != nil {
+			return err
+		}
+		if !c.Loaded() {
+			cmd.PrintErrf("%s are not found\n", strings.Join(config.DefaultConfigFilePaths, " and "))
+		}
+		c.Build()
+
+		for _, f := range configFeatures(c) {
+			if !f.configured {
+				continue
+			}
+			if err := f.ready(); err != nil {
+				cmd.Printf("%-24s skipped: %s\n", f.name, strings.TrimSpace(err.Error()))
+				continue
+			}
+			cmd.Printf("%-24s enabled\n", f.name)
+		}
+
+		errs := validateConfig(c)
+		if len(errs) > 0 {
+			return fmt.Errorf("invalid config:\n  - %s", strings.Join(errs, "\n  - "))
+		}
+		return nil
+	},
+}
+
+type configFeature struct {
+	name string
+	// the section of the feature is set ( coverage and testExecutionTime are measured by default )
+	configured bool
+	ready      func() error
+}
+
+func configFeatures(c *config.Config) []*configFeature {
+	return []*configFeature{
+		{"coverage", true, c.CoverageConfigReady},
+		{"coverage.badge", c.Coverage != nil && c.Coverage.Badge.Path != "", c.CoverageBadgeConfigReady},
+		{"coverage.critical", c.Coverage != nil && c.Coverage.Critical != nil, c.CriticalCoverageConfigReady},
+		{"coverage.critical.badge", c.Coverage != nil && c.Coverage.Critical != nil && c.Coverage.Critical.Badge.Path != "", c.CriticalCoverageBadgeConfigReady},
+		{"codeToTestRatio", c.CodeToTestRatio != nil, c.CodeToTestRatioConfigReady},
+		{"codeToTestRatio.badge", c.CodeToTestRatio != nil && c.CodeToTestRatio.Badge.Path != "", c.CodeToTestRatioBadgeConfigReady},
+		{"linesOfCode", c.LinesOfCode != nil, c.LinesOfCodeConfigReady},
+		{"linesOfCode.badge", c.LinesOfCode != nil && c.LinesOfCode.Badge.Path != "", c.LinesOfCodeBadgeConfigReady},
+		{"testExecutionTime", true, c.TestExecutionTimeConfigReady},
+		{"testExecutionTime.badge", c.TestExecutionTime != nil && c.TestExecutionTime.Badge.Path != "", c.TestExecutionTimeBadgeConfigReady},
+		{"report", c.Report != nil, c.ReportConfigReady},
+		{"diff", c.Diff != nil, c.DiffConfigReady},
+		{"comment", c.Comment != nil, c.CommentConfigReady},
+		{"checks", c.Checks != nil, c.ChecksConfigReady},
+		{"notifications.webhook", c.Notifications != nil && c.Notifications.Webhook != nil, c.NotificationsWebhookConfigReady},
+		{"push", c.Push != nil, c.PushConfigReady},
+		{"central", c.Central != nil, c.CentralConfigReady},
+	}
+}
+
+// validateConfig returns the hard errors of the config: the unknown fields, the invalid values and the invalid datastores
+func validateConfig(c *config.Config) []string {
+	errs := []string{}
+	if err := c.UnknownFields(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	errs = append(errs, c.ValueErrors()...)
+	validateDatastores := func(key string, datastores []string) {
+		for _, u := range datastores {
+			if err := datastore.Validate(u, c.Root()); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", key, err))
+			}
+		}
+	}
+	if c.Report != nil {
+		validateDatastores("report.datastores", c.Report.Datastores)
+	}
+	if c.Diff != nil {
+		validateDatastores("diff.datastores", c.Diff.Datastores)
+	}
+	if c.Central != nil {
+		validateDatastores("central.reports.datastores", c.Central.Reports.Datastores)
+	}
+	return errs
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+}
@@ -22,16 +22,35 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 
-	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/pkg/coverage"
 	"github.com/k1LoW/octocov/report"
 	"github.com/spf13/cobra"
 )
 
-var reportPath string
+var (
+	reportPath    string
+	viewFormat    string
+	viewUncovered bool
+	viewContext   int
+)
+
+const (
+	viewFormatText = "text"
+	viewFormatJSON = "json"
+)
+
+// viewFileCoverage is the JSON document of the file of `view --format json`
+type viewFileCoverage struct {
+	File    string                   `json:"file"`
+	Total   int                      `json:"total"`
+	Covered int                      `json:"covered"`
+	Lines   []*coverage.LineCoverage `json:"lines"`
+}
 
 // viewCmd represents the view command
 var viewCmd = &cobra.Command{
@@ -41,8 +60,16 @@ var viewCmd = &cobra.Command{
 	Aliases: []string{"cat"},
 	Args:    cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := config.New()
-		if err := c.Load(configPath); err != nil {
+		switch viewFormat {
+		case viewFormatText, viewFormatJSON:
+		default:
+			return fmt.Errorf("invalid --format: %s", viewFormat)
+		}
+		if viewContext < 0 {
+			return fmt.Errorf("invalid --context: %d", viewContext)
+		}
+		c, err := loadConfig(cmd)
+		if err != nil {
 			return err
 		}
 		c.Build()
@@ -57,7 +84,7 @@ var viewCmd = &cobra.Command{
 		if reportPath != "" {
 			path = reportPath
 		}
-		if err := r.MeasureCoverage(path); err != nil {
+		if err := measureCoverage(c, r, path); err != nil {
 			return err
 		}
 		for _, f := range args {
@@ -66,6 +93,21 @@ var viewCmd = &cobra.Command{
 					return err
 				}
 				fc, _ := r.Coverage.Files.FuzzyFindByFile(f)
+				if viewFormat == viewFormatJSON {
+					vfc := &viewFileCoverage{
+						File:  f,
+						Lines: fc.LineCoverages(),
+					}
+					if viewUncovered {
+						vfc.Lines = uncoveredLineCoverages(fc, vfc.Lines)
+					}
+					if fc != nil {
+						vfc.Total = fc.Total
+						vfc.Covered = fc.Covered
+					}
+					// one JSON document per line
+					return json.NewEncoder(os.Stdout).Encode(vfc)
+				}
 				fp, err := os.Open(filepath.Clean(f))
 				if err != nil {
 					return err
@@ -73,7 +115,11 @@ var viewCmd = &cobra.Command{
 				defer func() {
 					_ = fp.Close()
 				}()
-				if err := coverage.NewPrinter(fc).Print(fp, os.Stdout); err != nil {
+				p := coverage.NewPrinter(fc)
+				if viewUncovered {
+					p.SetUncoveredOnly(viewContext)
+				}
+				if err := p.Print(fp, os.Stdout); err != nil {
 					return err
 				}
 				return nil
@@ -86,7 +132,25 @@ var viewCmd = &cobra.Command{
 	},
 }
 
+// uncoveredLineCoverages returns the lines not fully covered ( the lines that have uncovered blocks, same as the lines printed by --uncovered )
+func uncoveredLineCoverages(fc *coverage.FileCoverage, lcs []*coverage.LineCoverage) []*coverage.LineCoverage {
+	uncovered := []*coverage.LineCoverage{}
+	for _, lc := range lcs {
+		for _, b := range fc.FindBlocksByLine(lc.Line) {
+			if b.Count != nil && *b.Count == 0 {
+				uncovered = append(uncovered, lc)
+				break
+			}
+		}
+	}
+	return uncovered
+}
+
 func init() {
 	rootCmd.AddCommand(viewCmd)
 	viewCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
+	viewCmd.Flags().BoolVarP(&strictConfig, "strict", "", false, "fail when the config has unknown fields ( e.g. misspelled keys )")
+	viewCmd.Flags().StringVarP(&viewFormat, "format", "", viewFormatText, "output format (text, json)")
+	viewCmd.Flags().BoolVarP(&viewUncovered, "uncovered", "", false, "print only the lines not fully covered")
+	viewCmd.Flags().IntVarP(&viewContext, "context", "C", 0, "number of the context lines around the uncovered lines with --uncovered (text only)")
 }
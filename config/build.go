@@ -1,11 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/coverage"
 )
 
 func (c *Config) Build() {
@@ -18,9 +20,23 @@ func (c *Config) Build() {
 	if c.Coverage == nil {
 		c.Coverage = &ConfigCoverage{}
 	}
+	if c.Coverage.Format == coverageFormatCustom && c.Coverage.Command != "" {
+		c.Coverage.Path = fmt.Sprintf("%s%s", coverage.ExecPrefix, c.Coverage.Command)
+	}
 	if c.Coverage.Path == "" {
 		c.Coverage.Path = filepath.Dir(c.path)
 	}
+	if c.Coverage.Acceptable.PolicyFile != "" && !filepath.IsAbs(c.Coverage.Acceptable.PolicyFile) {
+		c.Coverage.Acceptable.PolicyFile = filepath.Join(c.Root(), c.Coverage.Acceptable.PolicyFile)
+	}
+	if c.Coverage.Format == coverageFormatPerFile {
+		if !strings.HasPrefix(c.Coverage.Path, coverage.PerFilePrefix) {
+			c.Coverage.Path = fmt.Sprintf("%s%s", coverage.PerFilePrefix, c.Coverage.Path)
+		}
+		if c.Coverage.PerFile == nil {
+			c.Coverage.PerFile = &ConfigCoveragePerFile{}
+		}
+	}
 
 	// CodeToTestRatio
 	if c.CodeToTestRatio != nil {
@@ -32,6 +48,13 @@ func (c *Config) Build() {
 		}
 	}
 
+	// LinesOfCode
+	if c.LinesOfCode != nil {
+		if c.LinesOfCode.Exclude == nil {
+			c.LinesOfCode.Exclude = []string{}
+		}
+	}
+
 	// TestExecutionTime
 	if c.TestExecutionTime == nil {
 		c.TestExecutionTime = &ConfigTestExecutionTime{}
@@ -50,21 +73,34 @@ func (c *Config) Build() {
 		if len(c.Central.Reports.Datastores) == 0 {
 			c.Central.Reports.Datastores = append(c.Central.Reports.Datastores, defaultReportsDatastore)
 		}
-		if c.Central.Badges == "" {
-			c.Central.Badges = defaultBadgesDir
+		if c.Central.Badges.Path == "" {
+			c.Central.Badges.Path = defaultBadgesDir
+		}
+		if !strings.HasPrefix(c.Central.Badges.Path, "/") {
+			c.Central.Badges.Path = filepath.Clean(filepath.Join(c.Root(), c.Central.Badges.Path))
 		}
-		if !strings.HasPrefix(c.Central.Badges, "/") {
-			c.Central.Badges = filepath.Clean(filepath.Join(c.Root(), c.Central.Badges))
+		if c.Central.JSON != "" && !strings.HasPrefix(c.Central.JSON, "/") {
+			c.Central.JSON = filepath.Clean(filepath.Join(c.Root(), c.Central.JSON))
 		}
 	}
 
 	// Push
 
 	// Comment
+	if c.Comment != nil && c.Comment.Template != "" && !filepath.IsAbs(c.Comment.Template) {
+		c.Comment.Template = filepath.Clean(filepath.Join(c.Root(), c.Comment.Template))
+	}
 
 	// Diff
 
 	// GitRoot
+	if c.GitRoot != "" {
+		// explicitly set root bypasses the traversal
+		if !filepath.IsAbs(c.GitRoot) {
+			c.GitRoot = filepath.Clean(filepath.Join(c.Root(), c.GitRoot))
+		}
+		return
+	}
 	gitRoot, _ := internal.GetRootPath(c.Root())
 	c.GitRoot = gitRoot
 }
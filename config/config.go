@@ -2,30 +2,57 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/antonmedv/expr"
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/goccy/go-yaml"
 	"github.com/k1LoW/duration"
 	"github.com/k1LoW/expand"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/loc"
+	"github.com/k1LoW/octocov/pkg/ratio"
 	"github.com/k1LoW/octocov/report"
 )
 
+var globMetaRe = regexp.MustCompile(`[*?\[\]{}\\]`)
+
 const defaultBadgesDir = "badges"
+const coverageFormatCustom = "custom"
+const coverageFormatPerFile = "perfile"
 const defaultReportsDatastore = "local://reports"
+const defaultDiffRetryInterval = time.Second
+const defaultDatastoresRetryMaxAttempts = 3
+const defaultDatastoresRetryInitialBackoff = time.Second
+const defaultDatastoresRetryMultiplier = 2.0
+const defaultFreshnessStaleAfter = 7 * 24 * time.Hour
+const defaultTablesGroupDepth = 1
+const defaultSignedURLTTL = time.Hour
+
+// signed URLs of S3 and GCS expire in 7 days at most
+const maxSignedURLTTL = 7 * 24 * time.Hour
+
+const (
+	TablesModeFlat    = "flat"
+	TablesModeGrouped = "grouped"
+)
 
+// Color schemes of the coverage badge
 const (
-	// https://github.com/badges/shields/blob/7d452472defa0e0bd71d6443393e522e8457f856/badge-maker/lib/color.js#L8-L12
-	green       = "#97CA00"
-	yellowgreen = "#A4A61D"
-	yellow      = "#DFB317"
-	orange      = "#FE7D37"
-	red         = "#E05D44"
+	ColorSchemeClassic    = "classic"
+	ColorSchemeGradient   = "gradient"
+	ColorSchemeMonochrome = "monochrome"
 )
 
 var DefaultConfigFilePaths = []string{".octocov.yml", "octocov.yml"}
@@ -34,60 +61,308 @@ type Config struct {
 	Repository        string                   `yaml:"repository"`
 	Coverage          *ConfigCoverage          `yaml:"coverage"`
 	CodeToTestRatio   *ConfigCodeToTestRatio   `yaml:"codeToTestRatio,omitempty"`
+	LinesOfCode       *ConfigLinesOfCode       `yaml:"linesOfCode,omitempty"`
 	TestExecutionTime *ConfigTestExecutionTime `yaml:"testExecutionTime,omitempty"`
 	Report            *ConfigReport            `yaml:"report,omitempty"`
 	Central           *ConfigCentral           `yaml:"central,omitempty"`
 	Push              *ConfigPush              `yaml:"push,omitempty"`
 	Comment           *ConfigComment           `yaml:"comment,omitempty"`
+	Checks            *ConfigChecks            `yaml:"checks,omitempty"`
 	Diff              *ConfigDiff              `yaml:"diff,omitempty"`
-	GitRoot           string                   `yaml:"-"`
+	Notifications     *ConfigNotifications     `yaml:"notifications,omitempty"`
+	Datastores        *ConfigDatastores        `yaml:"datastores,omitempty"`
+	GitRoot           string                   `yaml:"gitRoot,omitempty"`
+	// path of the base config file to merge onto ( relative to the config file )
+	Extends string `yaml:"extends,omitempty"`
 	// working directory
 	wd string
 	// config file path
 	path string
+	// paths of the config files read by Load ( the base config files of extends: first )
+	paths []string
+	// measure and check only, without the side effects ( --check-only )
+	checkOnly bool
 }
 
 type ConfigCoverage struct {
-	Path       string              `yaml:"path,omitempty"`
-	Badge      ConfigCoverageBadge `yaml:"badge,omitempty"`
-	Acceptable string              `yaml:"acceptable,omitempty"`
+	Path              string                   `yaml:"path,omitempty"`
+	Format            string                   `yaml:"format,omitempty"`
+	Command           string                   `yaml:"command,omitempty"`
+	Badge             ConfigCoverageBadge      `yaml:"badge,omitempty"`
+	Acceptable        ConfigCoverageAcceptable `yaml:"acceptable,omitempty"`
+	PerFile           *ConfigCoveragePerFile   `yaml:"perFile,omitempty"`
+	Critical          *ConfigCoverageCritical  `yaml:"critical,omitempty"`
+	IncludeExtensions []string                 `yaml:"includeExtensions,omitempty"`
+	ExcludeExtensions []string                 `yaml:"excludeExtensions,omitempty"`
+	Exclude           []string                 `yaml:"exclude,omitempty"`
+	Tables            *ConfigCoverageTables    `yaml:"tables,omitempty"`
 }
 
-type ConfigCoverageBadge struct {
+// ConfigCoverageTables accepts both `tables: grouped` and `tables: {mode: grouped, depth: 2}`
+type ConfigCoverageTables struct {
+	Mode  string `yaml:"mode,omitempty"`
+	Depth int    `yaml:"depth,omitempty"`
+}
+
+func (t *ConfigCoverageTables) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		t.Mode = s
+		return nil
+	}
+	type alias ConfigCoverageTables
+	a := alias{}
+	if err := unmarshal(&a); err != nil {
+		return err
+	}
+	*t = ConfigCoverageTables(a)
+	return nil
+}
+
+// ConfigCoverageCritical accepts both `critical: [paths...]` and `critical: {paths: [...], badge: ..., acceptable: 100%}`
+type ConfigCoverageCritical struct {
+	Paths      []string                    `yaml:"paths,omitempty"`
+	Badge      ConfigCoverageCriticalBadge `yaml:"badge,omitempty"`
+	Acceptable string                      `yaml:"acceptable,omitempty"`
+}
+
+func (cc *ConfigCoverageCritical) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var paths []string
+	if err := unmarshal(&paths); err == nil {
+		cc.Paths = paths
+		return nil
+	}
+	type alias ConfigCoverageCritical
+	a := alias{}
+	if err := unmarshal(&a); err != nil {
+		return err
+	}
+	*cc = ConfigCoverageCritical(a)
+	return nil
+}
+
+type ConfigCoverageCriticalBadge struct {
 	Path string `yaml:"path,omitempty"`
 }
 
+// ConfigCoveragePerFile maps the fields of per-file JSON ( `coverage.format: perfile` ) by dot-separated paths
+type ConfigCoveragePerFile struct {
+	File    string `yaml:"file,omitempty"`
+	Covered string `yaml:"covered,omitempty"`
+	Total   string `yaml:"total,omitempty"`
+}
+
+// ConfigCoverageAcceptable accepts both `acceptable: 60%` and `acceptable: {total: 60%, minFiles: 100, policyFile: path, perFile: 50%, diff: ">= -0.5%"}`
+type ConfigCoverageAcceptable struct {
+	Total      string                           `yaml:"total,omitempty"`
+	MinFiles   int                              `yaml:"minFiles,omitempty"`
+	PolicyFile string                           `yaml:"policyFile,omitempty"`
+	PerFile    *ConfigCoverageAcceptablePerFile `yaml:"perFile,omitempty"`
+	// the accepted change of the coverage from the previous report ( diff: )
+	Diff string `yaml:"diff,omitempty"`
+}
+
+func (a *ConfigCoverageAcceptable) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		a.Total = s
+		return nil
+	}
+	type alias ConfigCoverageAcceptable
+	aa := alias{}
+	if err := unmarshal(&aa); err != nil {
+		return err
+	}
+	*a = ConfigCoverageAcceptable(aa)
+	return nil
+}
+
+// ConfigCoverageAcceptablePerFile accepts both `perFile: 50%` and `perFile: {acceptable: 50%, exclude: [legacy/**]}`
+type ConfigCoverageAcceptablePerFile struct {
+	Acceptable string   `yaml:"acceptable,omitempty"`
+	Exclude    []string `yaml:"exclude,omitempty"`
+}
+
+func (a *ConfigCoverageAcceptablePerFile) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		a.Acceptable = s
+		return nil
+	}
+	type alias ConfigCoverageAcceptablePerFile
+	aa := alias{}
+	if err := unmarshal(&aa); err != nil {
+		return err
+	}
+	*a = ConfigCoverageAcceptablePerFile(aa)
+	return nil
+}
+
+type ConfigCoverageBadge struct {
+	Path   string `yaml:"path,omitempty"`
+	Scheme string `yaml:"scheme,omitempty"`
+	Label  string `yaml:"label,omitempty"`
+	Style  string `yaml:"style,omitempty"`
+	// base64 data URI of PNG/SVG or simple-icons slug
+	Logo string `yaml:"logo,omitempty"`
+	// color bands of the coverage, which take precedence over scheme
+	Colors []*ConfigCoverageBadgeColor `yaml:"colors,omitempty"`
+	// show the trend of the coverage from the previous report ( diff: )
+	ShowDiff bool `yaml:"showDiff,omitempty"`
+}
+
+// ConfigCoverageBadgeColor is the color of the coverage greater than or equal to the threshold
+type ConfigCoverageBadgeColor struct {
+	Threshold float64 `yaml:"threshold"`
+	Color     string  `yaml:"color"`
+}
+
 type ConfigCodeToTestRatio struct {
 	Code       []string                   `yaml:"code"`
 	Test       []string                   `yaml:"test"`
 	Badge      ConfigCodeToTestRatioBadge `yaml:"badge,omitempty"`
 	Acceptable string                     `yaml:"acceptable,omitempty"`
+	// glob patterns of the files not to count, relative to the config root
+	Exclude   []string                         `yaml:"exclude,omitempty"`
+	Languages []*ConfigCodeToTestRatioLanguage `yaml:"languages,omitempty"`
+	// count only the logical lines ( default: true )
+	CountLogicalLines *bool `yaml:"countLogicalLines,omitempty"`
+}
+
+// ConfigCodeToTestRatioLanguage is the files to count as the code and the test of the language
+type ConfigCodeToTestRatioLanguage struct {
+	Name string   `yaml:"name"`
+	Code []string `yaml:"code"`
+	Test []string `yaml:"test"`
 }
 
 type ConfigCodeToTestRatioBadge struct {
+	Path  string `yaml:"path,omitempty"`
+	Label string `yaml:"label,omitempty"`
+}
+
+type ConfigLinesOfCode struct {
+	Exclude    []string               `yaml:"exclude,omitempty"`
+	Badge      ConfigLinesOfCodeBadge `yaml:"badge,omitempty"`
+	Acceptable string                 `yaml:"acceptable,omitempty"`
+}
+
+type ConfigLinesOfCodeBadge struct {
 	Path string `yaml:"path,omitempty"`
 }
 
 type ConfigTestExecutionTime struct {
-	Badge      ConfigTestExecutionTimeBadge `yaml:"badge,omitempty"`
-	Acceptable string                       `yaml:"acceptable,omitempty"`
-	Steps      []string                     `yaml:"steps,omitempty"`
+	Badge        ConfigTestExecutionTimeBadge      `yaml:"badge,omitempty"`
+	Acceptable   ConfigTestExecutionTimeAcceptable `yaml:"acceptable,omitempty"`
+	Steps        []string                          `yaml:"steps,omitempty"`
+	StepsInclude []string                          `yaml:"stepsInclude,omitempty"`
+	StepsExclude []string                          `yaml:"stepsExclude,omitempty"`
+	Path         string                            `yaml:"path,omitempty"`
+	// the number of the slowest test cases to show ( the test cases are known only from the test reports of path: )
+	ShowSlowest int `yaml:"showSlowest,omitempty"`
+}
+
+// ConfigTestExecutionTimeAcceptable accepts both `acceptable: 1min` and `acceptable: {total: 1min, diff: 30s}`
+type ConfigTestExecutionTimeAcceptable struct {
+	Total string `yaml:"total,omitempty"`
+	// the accepted increase from the previous report ( diff: ), a duration ( 30s ) or a rate ( 10% )
+	Diff string `yaml:"diff,omitempty"`
+}
+
+func (a *ConfigTestExecutionTimeAcceptable) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		a.Total = s
+		return nil
+	}
+	type alias ConfigTestExecutionTimeAcceptable
+	aa := alias{}
+	if err := unmarshal(&aa); err != nil {
+		return err
+	}
+	*a = ConfigTestExecutionTimeAcceptable(aa)
+	return nil
 }
 
 type ConfigTestExecutionTimeBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path      string `yaml:"path,omitempty"`
+	Unit      string `yaml:"unit,omitempty"`
+	Precision *int   `yaml:"precision,omitempty"`
 }
 
 type ConfigCentral struct {
-	Enable  bool                 `yaml:"enable"`
-	Root    string               `yaml:"root"`
-	Reports ConfigCentralReports `yaml:"reports"`
-	Badges  string               `yaml:"badges"`
-	Push    ConfigPush           `yaml:"push"`
+	Enable           bool                 `yaml:"enable"`
+	Root             string               `yaml:"root"`
+	Reports          ConfigCentralReports `yaml:"reports"`
+	Badges           ConfigCentralBadges  `yaml:"badges"`
+	Index            ConfigCentralIndex   `yaml:"index,omitempty"`
+	RepoLinkTemplate string               `yaml:"repoLinkTemplate,omitempty"`
+	// path of the dashboard JSON of the collected reports
+	JSON string `yaml:"json,omitempty"`
+	// mark the repositories whose report is older than StaleAfter in the index
+	StaleAfter string     `yaml:"staleAfter,omitempty"`
+	Push       ConfigPush `yaml:"push"`
+}
+
+// ConfigCentralIndex is the order of the repositories in the central index
+type ConfigCentralIndex struct {
+	// name ( default ), coverage or updated
+	Sort string `yaml:"sort,omitempty"`
+	// list only the N repositories with the lowest coverage when Top > 0
+	Top int `yaml:"top,omitempty"`
+	// owner, or the regexp of the repository whose first submatch is the group name
+	GroupBy string `yaml:"groupBy,omitempty"`
+}
+
+// ConfigCentralBadges accepts both `badges: path/to/badges` and `badges: {path: path/to/badges, layout: ...}`
+type ConfigCentralBadges struct {
+	Path      string                        `yaml:"path,omitempty"`
+	Layout    string                        `yaml:"layout,omitempty"`
+	Freshness *ConfigCentralBadgesFreshness `yaml:"freshness,omitempty"`
+	Overall   *ConfigCentralBadgesOverall   `yaml:"overall,omitempty"`
+}
+
+// ConfigCentralBadgesFreshness is the badge of how long since the last report
+type ConfigCentralBadgesFreshness struct {
+	Label      string `yaml:"label,omitempty"`
+	StaleAfter string `yaml:"staleAfter,omitempty"`
+}
+
+// ConfigCentralBadgesOverall is the badge of the coverage across all the collected repositories
+type ConfigCentralBadgesOverall struct {
+	Label string `yaml:"label,omitempty"`
+	// mean ( default ) or loc
+	Weight string `yaml:"weight,omitempty"`
+}
+
+func (b *ConfigCentralBadges) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		b.Path = s
+		return nil
+	}
+	type alias ConfigCentralBadges
+	ab := alias{}
+	if err := unmarshal(&ab); err != nil {
+		return err
+	}
+	*b = ConfigCentralBadges(ab)
+	return nil
 }
 
 type ConfigCentralReports struct {
 	Datastores []string `yaml:"datastores"`
+	// collect only the most recent reports when Limit > 0
+	Limit     int                            `yaml:"limit,omitempty"`
+	SignedURL *ConfigCentralReportsSignedURL `yaml:"signedURL,omitempty"`
+	// glob patterns of the repositories ( owner/repo ) to collect
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// ConfigCentralReportsSignedURL is the signed URLs of the reports in the central index
+type ConfigCentralReportsSignedURL struct {
+	TTL string `yaml:"ttl,omitempty"`
 }
 
 type ConfigPush struct {
@@ -96,13 +371,77 @@ type ConfigPush struct {
 }
 
 type ConfigComment struct {
-	Enable         bool `yaml:"enable"`
-	HideFooterLink bool `yaml:"hideFooterLink"`
+	Enable         bool                       `yaml:"enable"`
+	HideFooterLink bool                       `yaml:"hideFooterLink"`
+	AsReview       bool                       `yaml:"asReview,omitempty"`
+	ReviewEvents   *ConfigCommentReviewEvents `yaml:"reviewEvents,omitempty"`
+	Metrics        []string                   `yaml:"metrics,omitempty"`
+	// path of the Go text/template file of the comment
+	Template string `yaml:"template,omitempty"`
+	// collapse the table of the files when more than HideFilesThreshold files are changed ( default: report.FilesHideMin )
+	HideFilesThreshold *int `yaml:"hideFilesThreshold,omitempty"`
+	// edit the previous comment in place instead of posting a new one ( default: true )
+	Update *bool `yaml:"update,omitempty"`
+	// pass only the coverage of the files changed in the pull request to comment.template
+	ChangedFilesOnly bool `yaml:"changedFilesOnly,omitempty"`
+}
+
+// ConfigChecks is the check run with the annotations on the uncovered lines added in the pull request
+type ConfigChecks struct {
+	Enable bool `yaml:"enable"`
+	// name of the check run ( default: octocov )
+	Name string `yaml:"name,omitempty"`
+}
+
+// ConfigCommentReviewEvents maps the result of the acceptable check to the event of the review
+type ConfigCommentReviewEvents struct {
+	Pass string `yaml:"pass,omitempty"`
+	Fail string `yaml:"fail,omitempty"`
 }
 
 type ConfigDiff struct {
-	Path       string   `yaml:"path,omitempty"`
-	Datastores []string `yaml:"datastores,omitempty"`
+	Path       string                `yaml:"path,omitempty"`
+	Datastores []string              `yaml:"datastores,omitempty"`
+	Acceptable *ConfigDiffAcceptable `yaml:"acceptable,omitempty"`
+	Retry      *ConfigDiffRetry      `yaml:"retry,omitempty"`
+	StaleAfter string                `yaml:"staleAfter,omitempty"`
+	// exclude the files removed in the pull request from the coverage of the previous report
+	ExcludeRemovedFiles bool `yaml:"excludeRemovedFiles,omitempty"`
+}
+
+type ConfigDiffRetry struct {
+	Max      int    `yaml:"max,omitempty"`
+	Interval string `yaml:"interval,omitempty"`
+}
+
+// ConfigDatastores is the settings common to all datastores
+type ConfigDatastores struct {
+	Retry *ConfigDatastoresRetry `yaml:"retry,omitempty"`
+}
+
+type ConfigDatastoresRetry struct {
+	MaxAttempts    int     `yaml:"maxAttempts,omitempty"`
+	InitialBackoff string  `yaml:"initialBackoff,omitempty"`
+	Multiplier     float64 `yaml:"multiplier,omitempty"`
+}
+
+type ConfigDiffAcceptable struct {
+	NoUncoveredNewFiles bool `yaml:"noUncoveredNewFiles,omitempty"`
+	// coverage threshold for a new file to be regarded as uncovered
+	NewFileThreshold string `yaml:"newFileThreshold,omitempty"`
+	// patterns of new files exempt from noUncoveredNewFiles in the pull request that adds them
+	NewFileGrace []string `yaml:"newFileGrace,omitempty"`
+}
+
+type ConfigNotifications struct {
+	Webhook *ConfigNotificationsWebhook `yaml:"webhook,omitempty"`
+}
+
+type ConfigNotificationsWebhook struct {
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
 }
 
 func New() *Config {
@@ -135,13 +474,39 @@ func (c *Config) Load(path string) error {
 		return nil
 	}
 	c.path = filepath.Join(c.wd, path)
-	buf, err := os.ReadFile(filepath.Clean(c.path))
+	b, paths, err := readConfigFile(c.path, map[string]struct{}{})
 	if err != nil {
 		return err
 	}
-	if err := yaml.Unmarshal(expand.ExpandenvYAMLBytes(buf), c); err != nil {
+	if err := yaml.Unmarshal(b, c); err != nil {
 		return err
 	}
+	c.paths = paths
+	return nil
+}
+
+// expandenv expands ${VAR} and $VAR in the values of the config file to the environment variables, and $$ to the literal $
+func expandenv(k string) string {
+	if k == "$" {
+		return "$"
+	}
+	return os.Getenv(k)
+}
+
+// UnknownFields returns the error of the unknown field in the loaded config files ( e.g. a misspelled key ), which is ignored by Load
+func (c *Config) UnknownFields() error {
+	for _, p := range c.paths {
+		buf, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return err
+		}
+		if err := yaml.UnmarshalWithOptions(expand.ExpandYAMLBytes(buf, expandenv), New(), yaml.DisallowUnknownField()); err != nil {
+			if p != c.path {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			return err
+		}
+	}
 	return nil
 }
 
@@ -156,9 +521,11 @@ func (c *Config) Loaded() bool {
 	return c.path != ""
 }
 
-func (c *Config) Acceptable(r *report.Report) error {
-	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable != "" {
-		a, err := strconv.ParseFloat(strings.TrimSuffix(c.Coverage.Acceptable, "%"), 64)
+// Acceptable checks the report against the acceptable conditions.
+// rPrev is the previous report ( diff: ) and coverage.acceptable.diff is skipped when it is nil.
+func (c *Config) Acceptable(r, rPrev *report.Report) error {
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable.Total != "" {
+		a, err := strconv.ParseFloat(strings.TrimSuffix(c.Coverage.Acceptable.Total, "%"), 64)
 		if err != nil {
 			return err
 		}
@@ -167,8 +534,79 @@ func (c *Config) Acceptable(r *report.Report) error {
 		}
 	}
 
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable.MinFiles > 0 {
+		n := 0
+		if r.Coverage != nil {
+			n = len(r.Coverage.Files)
+		}
+		if n < c.Coverage.Acceptable.MinFiles {
+			return fmt.Errorf("the number of measured files is %d, which is below the accepted %d", n, c.Coverage.Acceptable.MinFiles)
+		}
+	}
+
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable.PolicyFile != "" && r.Coverage != nil {
+		p, err := report.ReadCoveragePolicy(c.Coverage.Acceptable.PolicyFile)
+		if err != nil {
+			return fmt.Errorf("coverage.acceptable.policyFile: %w", err)
+		}
+		violations, warnings, err := r.PolicyViolations(p)
+		if err != nil {
+			return fmt.Errorf("coverage.acceptable.policyFile: %w", err)
+		}
+		for _, w := range warnings {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: coverage.acceptable.policyFile: %s\n", w)
+		}
+		if len(violations) > 0 {
+			files := []string{}
+			for _, v := range violations {
+				files = append(files, v.String())
+			}
+			return fmt.Errorf("code coverage of files is below the accepted coverage of the policy: %s", strings.Join(files, ", "))
+		}
+	}
+
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable.PerFile != nil && c.Coverage.Acceptable.PerFile.Acceptable != "" && r.Coverage != nil {
+		a, err := c.CoverageAcceptablePerFile()
+		if err != nil {
+			return err
+		}
+		violations, err := r.PerFileViolations(a, c.Coverage.Acceptable.PerFile.Exclude)
+		if err != nil {
+			return fmt.Errorf("coverage.acceptable.perFile: %w", err)
+		}
+		if len(violations) > 0 {
+			files := []string{}
+			for _, v := range violations {
+				files = append(files, v.String())
+			}
+			return fmt.Errorf("code coverage of files is below the accepted %.1f%%: %s", a, strings.Join(files, ", "))
+		}
+	}
+
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable.Diff != "" && rPrev != nil && rPrev.IsMeasuredCoverage() {
+		a, err := c.CoverageAcceptableDiff()
+		if err != nil {
+			return err
+		}
+		prev := rPrev.CoveragePercent()
+		cur := r.CoveragePercent()
+		if cur-prev < a {
+			return fmt.Errorf("code coverage is %.2f%% (previous %.2f%%), which changed by %+.2f%% and is below the accepted %+.2f%%", cur, prev, cur-prev, a)
+		}
+	}
+
+	if err := c.CriticalCoverageConfigReady(); err == nil && r.CriticalCoverage != nil && c.Coverage.Critical.Acceptable != "" {
+		a, err := strconv.ParseFloat(strings.TrimSuffix(c.Coverage.Critical.Acceptable, "%"), 64)
+		if err != nil {
+			return err
+		}
+		if r.CriticalCoverage.Percent() < a {
+			return fmt.Errorf("code coverage of critical paths is %.1f%%, which is below the accepted %.1f%%", r.CriticalCoverage.Percent(), a)
+		}
+	}
+
 	if err := c.CodeToTestRatioConfigReady(); err == nil && c.CodeToTestRatio.Acceptable != "" {
-		a, err := strconv.ParseFloat(strings.TrimPrefix(c.CodeToTestRatio.Acceptable, "1:"), 64)
+		a, err := c.CodeToTestRatioAcceptable()
 		if err != nil {
 			return err
 		}
@@ -177,61 +615,629 @@ func (c *Config) Acceptable(r *report.Report) error {
 		}
 	}
 
-	if err := c.TestExecutionTimeConfigReady(); err == nil && r.TestExecutionTime != nil && c.TestExecutionTime.Acceptable != "" {
-		a, err := duration.Parse(c.TestExecutionTime.Acceptable)
+	if err := c.LinesOfCodeConfigReady(); err == nil && r.LinesOfCode != nil && c.LinesOfCode.Acceptable != "" {
+		a, err := loc.Parse(c.LinesOfCode.Acceptable)
+		if err != nil {
+			return err
+		}
+		if r.LinesOfCode.Code > a {
+			return fmt.Errorf("lines of code is %d, which is above the accepted %d", r.LinesOfCode.Code, a)
+		}
+	}
+
+	if err := c.TestExecutionTimeConfigReady(); err == nil && r.TestExecutionTime != nil && c.TestExecutionTime.Acceptable.Total != "" {
+		a, err := duration.Parse(c.TestExecutionTime.Acceptable.Total)
 		if err != nil {
 			return err
 		}
 		if *r.TestExecutionTime > float64(a) {
-			return fmt.Errorf("test execution time is %v, which is below the accepted %v", time.Duration(*r.TestExecutionTime), a)
+			f, err := c.TestExecutionTimeFormat()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("test execution time is %s, which is above the accepted %s", f.Format(time.Duration(*r.TestExecutionTime)), f.Format(a))
+		}
+	}
+
+	if err := c.TestExecutionTimeConfigReady(); err == nil && r.TestExecutionTime != nil && c.TestExecutionTime.Acceptable.Diff != "" && rPrev != nil && rPrev.TestExecutionTime != nil {
+		prev := time.Duration(*rPrev.TestExecutionTime)
+		a, err := c.TestExecutionTimeAcceptableDiff(prev)
+		if err != nil {
+			return err
+		}
+		cur := time.Duration(*r.TestExecutionTime)
+		if cur-prev > a {
+			f, err := c.TestExecutionTimeFormat()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("test execution time is %s (previous %s), which increased by %s and is above the accepted +%s", f.Format(cur), f.Format(prev), f.Format(cur-prev), f.Format(a))
 		}
 	}
 
 	return nil
 }
 
+// CoverageProcessors returns the coverage report parsers configured by coverage.format ( the per-file parser with the fields of coverage.perFile )
+// The parser of coverage.format is tried before detecting the format.
+func (c *Config) CoverageProcessors() []coverage.Processor {
+	if c.Coverage == nil || c.Coverage.Format == "" {
+		return nil
+	}
+	if c.Coverage.Format != coverageFormatPerFile {
+		p, ok := coverage.Parser(c.Coverage.Format)
+		if !ok {
+			return nil
+		}
+		return []coverage.Processor{p}
+	}
+	pf := c.Coverage.PerFile
+	if pf == nil {
+		pf = &ConfigCoveragePerFile{}
+	}
+	return []coverage.Processor{coverage.NewPerFile(pf.File, pf.Covered, pf.Total)}
+}
+
+// CoverageAcceptablePerFile returns the minimum acceptable coverage of each file ( coverage.acceptable.perFile.acceptable )
+func (c *Config) CoverageAcceptablePerFile() (float64, error) {
+	if c.Coverage == nil || c.Coverage.Acceptable.PerFile == nil || c.Coverage.Acceptable.PerFile.Acceptable == "" {
+		return 0, nil
+	}
+	a, err := strconv.ParseFloat(strings.TrimSuffix(c.Coverage.Acceptable.PerFile.Acceptable, "%"), 64)
+	if err != nil || a < 0 || a > 100 {
+		return 0, fmt.Errorf("coverage.acceptable.perFile: invalid value %s", c.Coverage.Acceptable.PerFile.Acceptable)
+	}
+	return a, nil
+}
+
+// CoverageAcceptableDiff returns the accepted change of the coverage from the previous report ( coverage.acceptable.diff: ">= -0.5%" ).
+// The leading ">=" is optional.
+func (c *Config) CoverageAcceptableDiff() (float64, error) {
+	if c.Coverage == nil || c.Coverage.Acceptable.Diff == "" {
+		return 0, nil
+	}
+	v := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(c.Coverage.Acceptable.Diff), ">="))
+	a, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+	if err != nil || a < -100 || a > 100 {
+		return 0, fmt.Errorf("coverage.acceptable.diff: invalid value %s", c.Coverage.Acceptable.Diff)
+	}
+	return a, nil
+}
+
+// CodeToTestRatioExclude returns the patterns of codeToTestRatio.exclude as the patterns of the absolute paths under the config root
+func (c *Config) CodeToTestRatioExclude() []string {
+	exclude := []string{}
+	if c.CodeToTestRatio == nil {
+		return exclude
+	}
+	root, err := filepath.Abs(c.Root())
+	if err != nil {
+		root = c.Root()
+	}
+	// the meta characters in the root are matched literally
+	root = globMetaRe.ReplaceAllString(filepath.ToSlash(root), `\$0`)
+	for _, p := range c.CodeToTestRatio.Exclude {
+		exclude = append(exclude, strings.TrimSuffix(root, "/")+"/"+strings.TrimPrefix(filepath.ToSlash(p), "./"))
+	}
+	return exclude
+}
+
+// CodeToTestRatioLanguages returns codeToTestRatio.languages
+func (c *Config) CodeToTestRatioLanguages() []*ratio.Language {
+	languages := []*ratio.Language{}
+	if c.CodeToTestRatio == nil {
+		return languages
+	}
+	for _, l := range c.CodeToTestRatio.Languages {
+		languages = append(languages, &ratio.Language{Name: l.Name, Code: l.Code, Test: l.Test})
+	}
+	return languages
+}
+
+// CodeToTestRatioCountLogicalLines returns whether only the logical lines are counted ( the blank lines and the comments are not counted )
+func (c *Config) CodeToTestRatioCountLogicalLines() bool {
+	if c.CodeToTestRatio == nil || c.CodeToTestRatio.CountLogicalLines == nil {
+		return true
+	}
+	return *c.CodeToTestRatio.CountLogicalLines
+}
+
+// CodeToTestRatioAcceptable returns the minimum acceptable test per code ( codeToTestRatio.acceptable: 1:1.2 ).
+// The leading "1:" is optional.
+func (c *Config) CodeToTestRatioAcceptable() (float64, error) {
+	if c.CodeToTestRatio == nil || c.CodeToTestRatio.Acceptable == "" {
+		return 0, nil
+	}
+	a, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(c.CodeToTestRatio.Acceptable), "1:"), 64)
+	if err != nil || a < 0 {
+		return 0, fmt.Errorf("codeToTestRatio.acceptable: invalid value %s (e.g. 1:1.2)", c.CodeToTestRatio.Acceptable)
+	}
+	return a, nil
+}
+
+// TestExecutionTimeAcceptableDiff returns the accepted increase of the test execution time from the previous one ( testExecutionTime.acceptable.diff: 30s or 10% ).
+func (c *Config) TestExecutionTimeAcceptableDiff(prev time.Duration) (time.Duration, error) {
+	if c.TestExecutionTime == nil || c.TestExecutionTime.Acceptable.Diff == "" {
+		return 0, nil
+	}
+	v := strings.TrimPrefix(strings.TrimSpace(c.TestExecutionTime.Acceptable.Diff), "+")
+	if strings.HasSuffix(v, "%") {
+		p, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		if err != nil || p < 0 {
+			return 0, fmt.Errorf("testExecutionTime.acceptable.diff: invalid value %s", c.TestExecutionTime.Acceptable.Diff)
+		}
+		return time.Duration(float64(prev) * p / 100), nil
+	}
+	d, err := duration.Parse(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("testExecutionTime.acceptable.diff: invalid value %s", c.TestExecutionTime.Acceptable.Diff)
+	}
+	return d, nil
+}
+
+// SetCheckOnly disables commenting, creating the check run, sending to the webhook, storing and pushing ( --check-only )
+func (c *Config) SetCheckOnly(v bool) {
+	c.checkOnly = v
+}
+
+// TestExecutionTimeShowSlowest returns the number of the slowest test cases to show ( testExecutionTime.showSlowest ).
+func (c *Config) TestExecutionTimeShowSlowest() (int, error) {
+	if c.TestExecutionTime == nil {
+		return 0, nil
+	}
+	if c.TestExecutionTime.ShowSlowest < 0 {
+		return 0, fmt.Errorf("testExecutionTime.showSlowest: invalid value %d", c.TestExecutionTime.ShowSlowest)
+	}
+	return c.TestExecutionTime.ShowSlowest, nil
+}
+
+// TestExecutionTimeFormat returns the format of test execution time ( testExecutionTime.badge.unit and testExecutionTime.badge.precision ).
+// It returns nil when neither the unit nor the precision is set.
+func (c *Config) TestExecutionTimeFormat() (*report.DurationFormat, error) {
+	if c.TestExecutionTime == nil || (c.TestExecutionTime.Badge.Unit == "" && c.TestExecutionTime.Badge.Precision == nil) {
+		return nil, nil
+	}
+	unit := c.TestExecutionTime.Badge.Unit
+	if unit == "" {
+		// the precision is applied to the Go duration string of the default format
+		unit = report.DurationUnitAuto
+	}
+	f, err := report.NewDurationFormat(unit, c.TestExecutionTime.Badge.Precision)
+	if err != nil {
+		return nil, fmt.Errorf("testExecutionTime.badge: %w", err)
+	}
+	return f, nil
+}
+
+// TablesGroupDepth returns the depth of the directories to group the files of the coverage table by ( coverage.tables ).
+// It returns 0 when the files are not grouped.
+func (c *Config) TablesGroupDepth() (int, error) {
+	if c.Coverage == nil || c.Coverage.Tables == nil {
+		return 0, nil
+	}
+	switch c.Coverage.Tables.Mode {
+	case "", TablesModeFlat:
+		return 0, nil
+	case TablesModeGrouped:
+	default:
+		return 0, fmt.Errorf("coverage.tables: invalid mode: %s", c.Coverage.Tables.Mode)
+	}
+	if c.Coverage.Tables.Depth < 0 {
+		return 0, fmt.Errorf("coverage.tables.depth: invalid depth: %d", c.Coverage.Tables.Depth)
+	}
+	if c.Coverage.Tables.Depth == 0 {
+		return defaultTablesGroupDepth, nil
+	}
+	return c.Coverage.Tables.Depth, nil
+}
+
+// PerPackageDepth returns the depth of the directories to group the files of the per-package coverage by ( report.coverage.perPackage ).
+// When report.coverage.perPackage is not set, it follows the grouped file coverage table ( coverage.tables: grouped ).
+// It reports false when the per-package coverage is not enabled.
+func (c *Config) PerPackageDepth() (int, bool, error) {
+	if c.Report == nil || c.Report.Coverage == nil || c.Report.Coverage.PerPackage == nil {
+		depth, err := c.TablesGroupDepth()
+		if err != nil || depth == 0 {
+			return 0, false, err
+		}
+		return depth, true, nil
+	}
+	if !c.Report.Coverage.PerPackage.Enable {
+		return 0, false, nil
+	}
+	if c.Report.Coverage.PerPackage.Depth < 0 {
+		return 0, false, fmt.Errorf("report.coverage.perPackage.depth: invalid depth: %d", c.Report.Coverage.PerPackage.Depth)
+	}
+	return c.Report.Coverage.PerPackage.Depth, true, nil
+}
+
+// NewFileThreshold returns the coverage threshold for a new file in the pull request to be regarded as uncovered
+func (c *Config) NewFileThreshold() (float64, error) {
+	if c.Diff == nil || c.Diff.Acceptable == nil || c.Diff.Acceptable.NewFileThreshold == "" {
+		return 0.0, nil
+	}
+	return strconv.ParseFloat(strings.TrimSuffix(c.Diff.Acceptable.NewFileThreshold, "%"), 64)
+}
+
+// DiffRetryPolicy returns the policy to retry reading the previous report from diff.datastores on transient errors ( diff.retry ).
+// It returns nil when diff.retry.max is not set.
+func (c *Config) DiffRetryPolicy() (*internal.RetryPolicy, error) {
+	if c.Diff == nil || c.Diff.Retry == nil || c.Diff.Retry.Max <= 0 {
+		return nil, nil
+	}
+	p := &internal.RetryPolicy{
+		MaxAttempts:    c.Diff.Retry.Max + 1,
+		InitialBackoff: defaultDiffRetryInterval,
+		Multiplier:     defaultDatastoresRetryMultiplier,
+	}
+	if c.Diff.Retry.Interval != "" {
+		i, err := duration.Parse(c.Diff.Retry.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("diff.retry.interval: %w", err)
+		}
+		p.InitialBackoff = i
+	}
+	return p, nil
+}
+
+// DatastoresRetryPolicy returns the policy to retry storing the report to the datastores and pushing the central report on transient errors ( datastores.retry ).
+// It returns nil when datastores.retry is not set.
+func (c *Config) DatastoresRetryPolicy() (*internal.RetryPolicy, error) {
+	if c.Datastores == nil || c.Datastores.Retry == nil {
+		return nil, nil
+	}
+	r := c.Datastores.Retry
+	p := &internal.RetryPolicy{
+		MaxAttempts:    defaultDatastoresRetryMaxAttempts,
+		InitialBackoff: defaultDatastoresRetryInitialBackoff,
+		Multiplier:     defaultDatastoresRetryMultiplier,
+	}
+	if r.MaxAttempts < 0 {
+		return nil, fmt.Errorf("datastores.retry.maxAttempts: invalid attempts: %d", r.MaxAttempts)
+	}
+	if r.MaxAttempts > 0 {
+		p.MaxAttempts = r.MaxAttempts
+	}
+	if r.InitialBackoff != "" {
+		d, err := duration.Parse(r.InitialBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("datastores.retry.initialBackoff: %w", err)
+		}
+		p.InitialBackoff = d
+	}
+	if r.Multiplier != 0 {
+		if r.Multiplier < 1 {
+			return nil, fmt.Errorf("datastores.retry.multiplier: invalid multiplier: %v", r.Multiplier)
+		}
+		p.Multiplier = r.Multiplier
+	}
+	return p, nil
+}
+
+// CentralReportsSignedURLTTL returns the TTL of the signed URLs of the reports in the central index ( central.reports.signedURL.ttl ).
+// It returns false when central.reports.signedURL is not set.
+func (c *Config) CentralReportsSignedURLTTL() (time.Duration, bool, error) {
+	if c.Central == nil || c.Central.Reports.SignedURL == nil {
+		return 0, false, nil
+	}
+	if c.Central.Reports.SignedURL.TTL == "" {
+		return defaultSignedURLTTL, true, nil
+	}
+	d, err := duration.Parse(c.Central.Reports.SignedURL.TTL)
+	if err != nil {
+		return 0, false, fmt.Errorf("central.reports.signedURL.ttl: %w", err)
+	}
+	if d <= 0 || d > maxSignedURLTTL {
+		return 0, false, fmt.Errorf("central.reports.signedURL.ttl: invalid ttl: %s", c.Central.Reports.SignedURL.TTL)
+	}
+	return d, true, nil
+}
+
+// CommentUpdate returns whether the previous comment is edited in place ( comment.update )
+func (c *Config) CommentUpdate() bool {
+	if c.Comment == nil || c.Comment.Update == nil {
+		return true
+	}
+	return *c.Comment.Update
+}
+
+// CommentHideFilesThreshold returns comment.hideFilesThreshold
+func (c *Config) CommentHideFilesThreshold() (int, error) {
+	if c.Comment == nil || c.Comment.HideFilesThreshold == nil {
+		return report.FilesHideMin, nil
+	}
+	if *c.Comment.HideFilesThreshold < 0 {
+		return 0, fmt.Errorf("comment.hideFilesThreshold: invalid value %d", *c.Comment.HideFilesThreshold)
+	}
+	return *c.Comment.HideFilesThreshold, nil
+}
+
+// CommentTemplate returns the template of the comment ( comment.template ). It returns nil when comment.template is not set.
+func (c *Config) CommentTemplate() (*template.Template, error) {
+	if c.Comment == nil || c.Comment.Template == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(filepath.Clean(c.Comment.Template))
+	if err != nil {
+		return nil, fmt.Errorf("comment.template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(c.Comment.Template)).Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("comment.template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// CentralStaleAfter returns central.staleAfter. It returns 0 when central.staleAfter is not set.
+func (c *Config) CentralStaleAfter() (time.Duration, error) {
+	if c.Central == nil || c.Central.StaleAfter == "" {
+		return 0, nil
+	}
+	d, err := duration.Parse(c.Central.StaleAfter)
+	if err != nil {
+		return 0, fmt.Errorf("central.staleAfter: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("central.staleAfter: invalid value %s", c.Central.StaleAfter)
+	}
+	return d, nil
+}
+
+// DiffStale checks that the previous report is not older than diff.staleAfter
+func (c *Config) DiffStale(r2 *report.Report, now time.Time) error {
+	if c.Diff == nil || c.Diff.StaleAfter == "" || r2 == nil {
+		return nil
+	}
+	a, err := duration.Parse(c.Diff.StaleAfter)
+	if err != nil {
+		return err
+	}
+	if d := now.Sub(r2.Timestamp); d > a {
+		return fmt.Errorf("the previous report was created %v ago, which is older than the accepted %v", d.Round(time.Second), a)
+	}
+	return nil
+}
+
+// ReviewEvent returns the event of the review ( comment.asReview ) for the result of the acceptable check
+func (c *Config) ReviewEvent(accepted bool) (string, error) {
+	pass, fail := gh.ReviewEventComment, gh.ReviewEventRequestChanges
+	if c.Comment != nil && c.Comment.ReviewEvents != nil {
+		if c.Comment.ReviewEvents.Pass != "" {
+			pass = strings.ToUpper(c.Comment.ReviewEvents.Pass)
+		}
+		if c.Comment.ReviewEvents.Fail != "" {
+			fail = strings.ToUpper(c.Comment.ReviewEvents.Fail)
+		}
+	}
+	for _, e := range []string{pass, fail} {
+		switch e {
+		case gh.ReviewEventApprove, gh.ReviewEventComment, gh.ReviewEventRequestChanges:
+		default:
+			return "", fmt.Errorf("comment.reviewEvents: invalid event: %s", e)
+		}
+	}
+	if accepted {
+		return pass, nil
+	}
+	return fail, nil
+}
+
+// AcceptableNewFiles checks that the files newly added in the pull request are covered
+func (c *Config) AcceptableNewFiles(r *report.Report, files []*gh.PullRequestFile) error {
+	if c.Diff == nil || c.Diff.Acceptable == nil || !c.Diff.Acceptable.NoUncoveredNewFiles {
+		return nil
+	}
+	threshold, err := c.NewFileThreshold()
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, f := range r.UncoveredNewFiles(files, threshold) {
+		grace, err := c.newFileGrace(f.Filename)
+		if err != nil {
+			return err
+		}
+		if grace {
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s (%.1f%%)", f.Filename, f.Coverage))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("new files are not covered: %s", strings.Join(names, ", "))
+}
+
+// newFileGrace reports whether the new file is matched by diff.acceptable.newFileGrace
+func (c *Config) newFileGrace(file string) (bool, error) {
+	for _, p := range c.Diff.Acceptable.NewFileGrace {
+		match, err := doublestar.Match(strings.TrimPrefix(filepath.ToSlash(p), "./"), strings.TrimPrefix(filepath.ToSlash(file), "./"))
+		if err != nil {
+			return false, fmt.Errorf("diff.acceptable.newFileGrace: %w", err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CoverageBadgeLabel returns the label of the coverage badge ( coverage.badge.label )
+func (c *Config) CoverageBadgeLabel() string {
+	if c.Coverage == nil || c.Coverage.Badge.Label == "" {
+		return "coverage"
+	}
+	return c.Coverage.Badge.Label
+}
+
+// CoverageBadgeStyle returns the style of the coverage badge ( coverage.badge.style )
+func (c *Config) CoverageBadgeStyle() string {
+	if c.Coverage == nil {
+		return ""
+	}
+	return c.Coverage.Badge.Style
+}
+
+// CoverageBadgeLogo returns the logo of the coverage badge ( coverage.badge.logo )
+func (c *Config) CoverageBadgeLogo() string {
+	if c.Coverage == nil {
+		return ""
+	}
+	return c.Coverage.Badge.Logo
+}
+
+// CodeToTestRatioBadgeLabel returns the label of the code to test ratio badge ( codeToTestRatio.badge.label )
+func (c *Config) CodeToTestRatioBadgeLabel() string {
+	if c.CodeToTestRatio == nil || c.CodeToTestRatio.Badge.Label == "" {
+		return "code to test ratio"
+	}
+	return c.CodeToTestRatio.Badge.Label
+}
+
+// CoverageBadgeColors returns the color bands of coverage.badge.colors in descending order of the threshold
+func (c *Config) CoverageBadgeColors() (badge.Thresholds, error) {
+	if c.Coverage == nil || len(c.Coverage.Badge.Colors) == 0 {
+		return nil, nil
+	}
+	ts := badge.Thresholds{}
+	for _, bc := range c.Coverage.Badge.Colors {
+		if bc == nil {
+			continue
+		}
+		col, err := badge.ParseColor(bc.Color)
+		if err != nil {
+			return nil, fmt.Errorf("coverage.badge.colors: %w", err)
+		}
+		ts = append(ts, badge.Threshold{Min: bc.Threshold, Color: col})
+	}
+	sort.SliceStable(ts, func(i, j int) bool {
+		return ts[i].Min > ts[j].Min
+	})
+	return ts, nil
+}
+
+// CoverageColor returns the color of the coverage badge.
+// With coverage.badge.colors, the coverage below all the thresholds has the color of the lowest threshold.
 func (c *Config) CoverageColor(cover float64) string {
+	if ts, err := c.CoverageBadgeColors(); err == nil && len(ts) > 0 {
+		for _, t := range ts {
+			if cover >= t.Min {
+				return t.Color
+			}
+		}
+		return ts[len(ts)-1].Color
+	}
+	if c.Coverage != nil {
+		switch c.Coverage.Badge.Scheme {
+		case ColorSchemeGradient:
+			return gradientColor(cover)
+		case ColorSchemeMonochrome:
+			return monochromeColor(cover)
+		}
+	}
+	switch {
+	case cover >= 80.0:
+		return badge.ColorGreen
+	case cover >= 60.0:
+		return badge.ColorYellowGreen
+	case cover >= 40.0:
+		return badge.ColorYellow
+	case cover >= 20.0:
+		return badge.ColorOrange
+	default:
+		return badge.ColorRed
+	}
+}
+
+// gradientColor returns the color interpolated from red ( 0% ) through yellow ( 50% ) to green ( 100% )
+func gradientColor(cover float64) string {
+	if cover < 0 {
+		cover = 0
+	}
+	if cover > 100 {
+		cover = 100
+	}
+	from, to, t := badge.ColorRed, badge.ColorYellow, cover/50
+	if cover >= 50 {
+		from, to, t = badge.ColorYellow, badge.ColorGreen, (cover-50)/50
+	}
+	var fr, fg, fb, tr, tg, tb int
+	_, _ = fmt.Sscanf(from, "#%02x%02x%02x", &fr, &fg, &fb)
+	_, _ = fmt.Sscanf(to, "#%02x%02x%02x", &tr, &tg, &tb)
+	lerp := func(a, b int) int {
+		return a + int(math.Round(float64(b-a)*t))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", lerp(fr, tr), lerp(fg, tg), lerp(fb, tb))
+}
+
+// monochromeColor returns shades of blue ( darker is better )
+func monochromeColor(cover float64) string {
 	switch {
 	case cover >= 80.0:
-		return green
+		return "#08519C"
 	case cover >= 60.0:
-		return yellowgreen
+		return "#3182BD"
 	case cover >= 40.0:
-		return yellow
+		return "#6BAED6"
 	case cover >= 20.0:
-		return orange
+		return "#9ECAE1"
 	default:
-		return red
+		return "#C6DBEF"
 	}
 }
 
 func (c *Config) CodeToTestRatioColor(ratio float64) string {
 	switch {
 	case ratio >= 1.2:
-		return green
+		return badge.ColorGreen
 	case ratio >= 1.0:
-		return yellowgreen
+		return badge.ColorYellowGreen
 	case ratio >= 0.8:
-		return yellow
+		return badge.ColorYellow
 	case ratio >= 0.6:
-		return orange
+		return badge.ColorOrange
+	default:
+		return badge.ColorRed
+	}
+}
+
+// LinesOfCodeColor returns a fixed color because lines of code is neither good nor bad
+func (c *Config) LinesOfCodeColor() string {
+	return badge.ColorBlue
+}
+
+// FreshnessColor returns the color of the age of the report relative to central.badges.freshness.staleAfter
+func (c *Config) FreshnessColor(age time.Duration) string {
+	stale := defaultFreshnessStaleAfter
+	if c.Central != nil && c.Central.Badges.Freshness != nil && c.Central.Badges.Freshness.StaleAfter != "" {
+		if d, err := duration.Parse(c.Central.Badges.Freshness.StaleAfter); err == nil && d > 0 {
+			stale = d
+		}
+	}
+	switch {
+	case age <= stale/2:
+		return badge.ColorGreen
+	case age <= stale:
+		return badge.ColorYellow
+	case age <= stale*2:
+		return badge.ColorOrange
 	default:
-		return red
+		return badge.ColorRed
 	}
 }
 
 func (c *Config) TestExecutionTimeColor(d time.Duration) string {
 	switch {
 	case d < 5*time.Minute:
-		return green
+		return badge.ColorGreen
 	case d < 10*time.Minute:
-		return yellowgreen
+		return badge.ColorYellowGreen
 	case d < 15*time.Minute:
-		return yellow
+		return badge.ColorYellow
 	case d < 20*time.Minute:
-		return orange
+		return badge.ColorOrange
 	default:
-		return red
+		return badge.ColorRed
 	}
 }
 
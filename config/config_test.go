@@ -1,13 +1,21 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/badge"
 	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/loc"
 	"github.com/k1LoW/octocov/pkg/ratio"
 	"github.com/k1LoW/octocov/report"
 )
@@ -48,28 +56,755 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoadExpandEnv(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Unsetenv("OCTOCOV_TEST_GO_VERSION")
+	})
+	if err := os.Setenv("OCTOCOV_TEST_GO_VERSION", "1.17"); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"coverage-${OCTOCOV_TEST_GO_VERSION}.out", "coverage-1.17.out"},
+		{"coverage-$OCTOCOV_TEST_GO_VERSION.out", "coverage-1.17.out"},
+		{"coverage-$${OCTOCOV_TEST_GO_VERSION}.out", "coverage-${OCTOCOV_TEST_GO_VERSION}.out"},
+		{"cost$$.out", "cost$.out"},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(dir, ".octocov.yml"), []byte(fmt.Sprintf("coverage:\n  path: %s\n", tt.in)), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		c.wd = dir
+		if err := c.Load(""); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Coverage.Path; got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"coverage:\n  acceptable: 60%\n  badge:\n    path: docs/coverage.svg\n", false},
+		{"coverage:\n  badge:\n    paht: docs/coverage.svg\n", true},
+		{"comments:\n  enable: true\n", true},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(dir, ".octocov.yml"), []byte(tt.in), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		c.wd = dir
+		if err := c.Load(""); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.UnknownFields(); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+func TestBuildCoveragePerFile(t *testing.T) {
+	dir := filepath.Join(testdataDir(t), "..", "pkg", "coverage", "testdata", "perfile")
+	c := New()
+	c.Coverage = &ConfigCoverage{
+		Format: coverageFormatPerFile,
+		Path:   dir,
+		PerFile: &ConfigCoveragePerFile{
+			File:    "source.path",
+			Covered: "summary.lines.covered",
+			Total:   "summary.lines.total",
+		},
+	}
+	c.Build()
+	if want := coverage.PerFilePrefix + dir; c.Coverage.Path != want {
+		t.Errorf("got %v\nwant %v", c.Coverage.Path, want)
+	}
+	r := &report.Report{}
+	if err := r.MeasureCoverage(c.Coverage.Path, c.CoverageProcessors()...); err != nil {
+		t.Fatal(err)
+	}
+	if want := 15; r.Coverage.Total != want {
+		t.Errorf("got %v\nwant %v", r.Coverage.Total, want)
+	}
+	if want := 9; r.Coverage.Covered != want {
+		t.Errorf("got %v\nwant %v", r.Coverage.Covered, want)
+	}
+}
+
+func TestCoverageFormat(t *testing.T) {
+	tests := []struct {
+		format     string
+		command    string
+		wantErr    bool
+		wantParser string
+	}{
+		{"", "", false, ""},
+		{"lcov", "", false, "LCOV"},
+		{"cobertura", "", false, "Cobertura"},
+		{"custom", "./coverage.sh", false, "Custom"},
+		{"custom", "", true, "Custom"},
+		{"perfile", "", false, "Per-file JSON"},
+		{"LCOV", "", true, ""},
+		{"unknown", "", true, ""},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{
+			Format:  tt.format,
+			Command: tt.command,
+		}
+		c.Build()
+		if err := c.CoverageConfigReady(); tt.wantErr != (err != nil) {
+			t.Errorf("%s: got %v\nwantErr %v", tt.format, err, tt.wantErr)
+		}
+		got := ""
+		if ps := c.CoverageProcessors(); len(ps) > 0 {
+			got = ps[0].Name()
+		}
+		if got != tt.wantParser {
+			t.Errorf("%s: got %v\nwant %v", tt.format, got, tt.wantParser)
+		}
+	}
+	c := New()
+	c.Coverage = &ConfigCoverage{Format: "unknown"}
+	want := "coverage.format: invalid value unknown"
+	if errs := c.ValueErrors(); len(errs) == 0 || errs[0] != want {
+		t.Errorf("got %v\nwant %v", errs, want)
+	}
+}
+
+func TestBuildGitRoot(t *testing.T) {
+	tests := []struct {
+		gitRoot string
+		want    string
+	}{
+		{"", filepath.Dir(testdataDir(t))},
+		{"/path/to/root", "/path/to/root"},
+		{"sub/repo", filepath.Join(testdataDir(t), "sub", "repo")},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.wd = testdataDir(t)
+		c.GitRoot = tt.gitRoot
+		c.Build()
+		if c.GitRoot != tt.want {
+			t.Errorf("got %v\nwant %v", c.GitRoot, tt.want)
+		}
+	}
+}
+
 func TestCoverageAcceptable(t *testing.T) {
 	tests := []struct {
-		in      string
-		wantErr bool
+		in      string
+		wantErr bool
+	}{
+		{"60%", true},
+		{"50%", false},
+		{"49.9%", false},
+		{"49.9", false},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{}
+		c.Coverage.Acceptable.Total = tt.in
+		c.Build()
+
+		r := &report.Report{}
+		r.Coverage = &coverage.Coverage{
+			Covered: 50,
+			Total:   100,
+		}
+		if err := c.Acceptable(r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestCoverageAcceptableMinFiles(t *testing.T) {
+	tests := []struct {
+		minFiles int
+		files    int
+		wantErr  bool
+	}{
+		{0, 0, false},
+		{2, 2, false},
+		{2, 3, false},
+		{3, 2, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{}
+		c.Coverage.Acceptable.MinFiles = tt.minFiles
+		c.Build()
+
+		r := &report.Report{}
+		r.Coverage = &coverage.Coverage{
+			Covered: 50,
+			Total:   100,
+		}
+		for i := 0; i < tt.files; i++ {
+			r.Coverage.Files = append(r.Coverage.Files, &coverage.FileCoverage{})
+		}
+		if err := c.Acceptable(r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestUnmarshalCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ConfigCoverageAcceptable
+	}{
+		{"acceptable: 60%", ConfigCoverageAcceptable{Total: "60%"}},
+		{"acceptable:\n  total: 60%\n  minFiles: 100", ConfigCoverageAcceptable{Total: "60%", MinFiles: 100}},
+		{"acceptable:\n  minFiles: 100", ConfigCoverageAcceptable{MinFiles: 100}},
+		{"path: coverage.out", ConfigCoverageAcceptable{}},
+	}
+	for _, tt := range tests {
+		got := &ConfigCoverage{}
+		if err := yaml.Unmarshal([]byte(tt.in), got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Acceptable != tt.want {
+			t.Errorf("got %v\nwant %v", got.Acceptable, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalTestExecutionTimeAcceptable(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ConfigTestExecutionTimeAcceptable
+	}{
+		{"acceptable: 1min", ConfigTestExecutionTimeAcceptable{Total: "1min"}},
+		{"acceptable:\n  total: 1min\n  diff: 30s", ConfigTestExecutionTimeAcceptable{Total: "1min", Diff: "30s"}},
+		{"acceptable:\n  diff: 10%", ConfigTestExecutionTimeAcceptable{Diff: "10%"}},
+		{"path: report.xml", ConfigTestExecutionTimeAcceptable{}},
+	}
+	for _, tt := range tests {
+		got := &ConfigTestExecutionTime{}
+		if err := yaml.Unmarshal([]byte(tt.in), got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Acceptable != tt.want {
+			t.Errorf("got %v\nwant %v", got.Acceptable, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalCoverageCritical(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *ConfigCoverageCritical
+	}{
+		{"critical:\n  - internal/auth/**", &ConfigCoverageCritical{Paths: []string{"internal/auth/**"}}},
+		{"critical:\n  paths:\n    - internal/auth/**\n  acceptable: 95%\n  badge:\n    path: critical.svg", &ConfigCoverageCritical{Paths: []string{"internal/auth/**"}, Acceptable: "95%", Badge: ConfigCoverageCriticalBadge{Path: "critical.svg"}}},
+		{"path: coverage.out", nil},
+	}
+	for _, tt := range tests {
+		got := &ConfigCoverage{}
+		if err := yaml.Unmarshal([]byte(tt.in), got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got.Critical, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestTablesGroupDepth(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"path: coverage.out", 0, false},
+		{"tables: flat", 0, false},
+		{"tables: grouped", 1, false},
+		{"tables:\n  mode: grouped\n  depth: 2", 2, false},
+		{"tables:\n  depth: 2", 0, false},
+		{"tables: nested", 0, true},
+		{"tables:\n  mode: grouped\n  depth: -1", 0, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{}
+		if err := yaml.Unmarshal([]byte(tt.in), c.Coverage); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.TablesGroupDepth()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestPerPackageDepth(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantOK  bool
+		wantErr bool
+	}{
+		{"path: report.json", 0, false, false},
+		{"coverage:\n  perPackage: false", 0, false, false},
+		{"coverage:\n  perPackage: true", 0, true, false},
+		{"coverage:\n  perPackage:\n    depth: 2", 2, true, false},
+		{"coverage:\n  perPackage:\n    enable: false\n    depth: 2", 0, false, false},
+		{"coverage:\n  perPackage:\n    depth: -1", 0, false, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Report = &ConfigReport{}
+		if err := yaml.Unmarshal([]byte(tt.in), c.Report); err != nil {
+			t.Fatal(err)
+		}
+		got, ok, err := c.PerPackageDepth()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if ok != tt.wantOK {
+			t.Errorf("got %v\nwant %v", ok, tt.wantOK)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestPerPackageDepthWithGroupedTables(t *testing.T) {
+	tests := []struct {
+		report  string
+		tables  *ConfigCoverageTables
+		want    int
+		wantOK  bool
+		wantErr bool
+	}{
+		{"path: report.json", nil, 0, false, false},
+		{"path: report.json", &ConfigCoverageTables{Mode: TablesModeFlat}, 0, false, false},
+		{"path: report.json", &ConfigCoverageTables{Mode: TablesModeGrouped}, 1, true, false},
+		{"path: report.json", &ConfigCoverageTables{Mode: TablesModeGrouped, Depth: 2}, 2, true, false},
+		{"coverage:\n  perPackage:\n    depth: 3", &ConfigCoverageTables{Mode: TablesModeGrouped, Depth: 2}, 3, true, false},
+		{"coverage:\n  perPackage: false", &ConfigCoverageTables{Mode: TablesModeGrouped}, 0, false, false},
+		{"path: report.json", &ConfigCoverageTables{Mode: "invalid"}, 0, false, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Report = &ConfigReport{}
+		if err := yaml.Unmarshal([]byte(tt.report), c.Report); err != nil {
+			t.Fatal(err)
+		}
+		c.Coverage = &ConfigCoverage{Tables: tt.tables}
+		got, ok, err := c.PerPackageDepth()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if ok != tt.wantOK {
+			t.Errorf("got %v\nwant %v", ok, tt.wantOK)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestDatastoresRetryPolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *internal.RetryPolicy
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"retry: {}", &internal.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, Multiplier: 2}, false},
+		{"retry:\n  maxAttempts: 5\n  initialBackoff: 500ms\n  multiplier: 1.5", &internal.RetryPolicy{MaxAttempts: 5, InitialBackoff: 500 * time.Millisecond, Multiplier: 1.5}, false},
+		{"retry:\n  maxAttempts: -1", nil, true},
+		{"retry:\n  initialBackoff: soon", nil, true},
+		{"retry:\n  multiplier: 0.5", nil, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Datastores = &ConfigDatastores{}
+		if err := yaml.Unmarshal([]byte(tt.in), c.Datastores); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.DatastoresRetryPolicy()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestCentralConfigReadyIndex(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"reports:\n  datastores: [local://reports]", false},
+		{"reports:\n  datastores: [local://reports]\n  include: [owner/*]\n  exclude: ['*/archived-*']\nindex:\n  sort: coverage\n  top: 10", false},
+		{"reports:\n  datastores: [local://reports]\nindex:\n  sort: updated", false},
+		{"reports:\n  datastores: [local://reports]\nindex:\n  sort: stars", true},
+		{"reports:\n  datastores: [local://reports]\nindex:\n  top: -1", true},
+		{"reports:\n  datastores: [local://reports]\n  exclude: ['owner/[']", true},
+		{"reports:\n  datastores: [local://reports]\nindex:\n  groupBy: owner", false},
+		{"reports:\n  datastores: [local://reports]\nindex:\n  groupBy: '^[^/]+/([^-]+)-'", false},
+		{"reports:\n  datastores: [local://reports]\nindex:\n  groupBy: '^owner/(team'", true},
+		{"reports:\n  datastores: [local://reports]\nbadges:\n  path: badges\n  overall:\n    weight: loc", false},
+		{"reports:\n  datastores: [local://reports]\nbadges:\n  path: badges\n  overall:\n    weight: median", true},
+		{"reports:\n  datastores: [local://reports]\nstaleAfter: 30day", false},
+		{"reports:\n  datastores: [local://reports]\nstaleAfter: someday", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Repository = "owner/central"
+		c.Central = &ConfigCentral{}
+		if err := yaml.Unmarshal([]byte(tt.in), c.Central); err != nil {
+			t.Fatal(err)
+		}
+		c.Central.Enable = true
+		if err := c.CentralConfigReady(); tt.wantErr != (err != nil) {
+			t.Errorf("%q: got %v\nwantErr %v", tt.in, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCentralReportsSignedURLTTL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantOk  bool
+		wantErr bool
+	}{
+		{"datastores: []", 0, false, false},
+		{"signedURL: {}", time.Hour, true, false},
+		{"signedURL:\n  ttl: 30min", 30 * time.Minute, true, false},
+		{"signedURL:\n  ttl: 7day", 7 * 24 * time.Hour, true, false},
+		{"signedURL:\n  ttl: 8day", 0, false, true},
+		{"signedURL:\n  ttl: soon", 0, false, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Central = &ConfigCentral{}
+		if err := yaml.Unmarshal([]byte(tt.in), &c.Central.Reports); err != nil {
+			t.Fatal(err)
+		}
+		got, ok, err := c.CentralReportsSignedURLTTL()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if ok != tt.wantOk {
+			t.Errorf("got %v\nwant %v", ok, tt.wantOk)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCriticalCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"", false},
+		{"90%", false},
+		{"90.1%", true},
+		{"100", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{
+			Critical: &ConfigCoverageCritical{
+				Paths:      []string{"internal/**"},
+				Acceptable: tt.in,
+			},
+		}
+		c.Build()
+
+		r := &report.Report{}
+		r.Coverage = &coverage.Coverage{
+			Covered: 50,
+			Total:   100,
+		}
+		r.CriticalCoverage = &report.CriticalCoverage{
+			Covered: 9,
+			Total:   10,
+		}
+		if err := c.Acceptable(r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestCoverageAcceptablePolicyFile(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr bool
+	}{
+		{"internal/** 50% @org/platform\n", false},
+		{"internal/** 80% @org/platform\n", true},
+		{"internal/** 50%\ninternal/a.go 80%\n", true},
+		{"internal/**\n", true},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "COVERAGEPOLICY"), []byte(tt.policy), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		c.Setwd(dir)
+		c.Coverage = &ConfigCoverage{
+			Acceptable: ConfigCoverageAcceptable{
+				PolicyFile: "COVERAGEPOLICY",
+			},
+		}
+		c.Build()
+
+		r := &report.Report{}
+		r.Coverage = &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "internal/a.go", Total: 10, Covered: 6},
+			},
+		}
+		if err := c.Acceptable(r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestUnmarshalCoverageAcceptablePerFile(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *ConfigCoverageAcceptablePerFile
+	}{
+		{"acceptable:\n  perFile: 50%", &ConfigCoverageAcceptablePerFile{Acceptable: "50%"}},
+		{"acceptable:\n  perFile:\n    acceptable: 50%\n    exclude:\n      - legacy/**", &ConfigCoverageAcceptablePerFile{Acceptable: "50%", Exclude: []string{"legacy/**"}}},
+		{"acceptable: 60%", nil},
+	}
+	for _, tt := range tests {
+		got := &ConfigCoverage{}
+		if err := yaml.Unmarshal([]byte(tt.in), got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got.Acceptable.PerFile, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestCoverageAcceptablePerFile(t *testing.T) {
+	r := &report.Report{}
+	r.Coverage = &coverage.Coverage{
+		Covered: 85,
+		Total:   100,
+		Files: coverage.FileCoverages{
+			{File: "internal/a.go", Total: 80, Covered: 80},
+			{File: "legacy/b.go", Total: 10, Covered: 2},
+			{File: "new.go", Total: 10, Covered: 3},
+		},
+	}
+	tests := []struct {
+		perFile *ConfigCoverageAcceptablePerFile
+		want    string
 	}{
-		{"60%", true},
-		{"50%", false},
-		{"49.9%", false},
-		{"49.9", false},
+		{nil, ""},
+		{&ConfigCoverageAcceptablePerFile{Acceptable: "10%"}, ""},
+		{&ConfigCoverageAcceptablePerFile{Acceptable: "50%"}, "code coverage of files is below the accepted 50.0%: legacy/b.go (20.0%, accepted 50.0%), new.go (30.0%, accepted 50.0%)"},
+		{&ConfigCoverageAcceptablePerFile{Acceptable: "50%", Exclude: []string{"legacy/**"}}, "code coverage of files is below the accepted 50.0%: new.go (30.0%, accepted 50.0%)"},
+		{&ConfigCoverageAcceptablePerFile{Acceptable: "50%", Exclude: []string{"legacy/**", "./new.go"}}, ""},
+		{&ConfigCoverageAcceptablePerFile{Acceptable: "150%"}, "coverage.acceptable.perFile: invalid value 150%"},
 	}
 	for _, tt := range tests {
 		c := New()
-		c.Coverage = &ConfigCoverage{}
-		c.Coverage.Acceptable = tt.in
+		c.Coverage = &ConfigCoverage{
+			Acceptable: ConfigCoverageAcceptable{
+				PerFile: tt.perFile,
+			},
+		}
 		c.Build()
+		got := ""
+		if err := c.Acceptable(r, nil); err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
 
-		r := &report.Report{}
-		r.Coverage = &coverage.Coverage{
-			Covered: 50,
-			Total:   100,
+func TestCoverageAcceptableDiff(t *testing.T) {
+	r := &report.Report{}
+	r.Coverage = &coverage.Coverage{
+		Covered: 795,
+		Total:   1000,
+	}
+	prev := &report.Report{}
+	prev.Coverage = &coverage.Coverage{
+		Covered: 80,
+		Total:   100,
+	}
+	tests := []struct {
+		diff string
+		prev *report.Report
+		want string
+	}{
+		{"", prev, ""},
+		{">= -0.5%", prev, ""},
+		{"-0.5%", prev, ""},
+		{">=-0.5", prev, ""},
+		{">= 0%", prev, "code coverage is 79.50% (previous 80.00%), which changed by -0.50% and is below the accepted +0.00%"},
+		{">= -0.4%", prev, "code coverage is 79.50% (previous 80.00%), which changed by -0.50% and is below the accepted -0.40%"},
+		{">= 0%", nil, ""},
+		{">= 0%", &report.Report{}, ""},
+		{"invalid", prev, "coverage.acceptable.diff: invalid value invalid"},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{
+			Acceptable: ConfigCoverageAcceptable{
+				Diff: tt.diff,
+			},
+		}
+		c.Build()
+		got := ""
+		if err := c.Acceptable(r, tt.prev); err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestAcceptableNewFiles(t *testing.T) {
+	r := &report.Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "new.go", Total: 10, Covered: 4},
+			},
+		},
+	}
+	files := []*gh.PullRequestFile{{Filename: "new.go", Status: "added"}}
+	tests := []struct {
+		acceptable *ConfigDiffAcceptable
+		wantErr    bool
+	}{
+		{nil, false},
+		{&ConfigDiffAcceptable{NoUncoveredNewFiles: true}, false},
+		{&ConfigDiffAcceptable{NoUncoveredNewFiles: true, NewFileThreshold: "50%"}, true},
+		{&ConfigDiffAcceptable{NoUncoveredNewFiles: false, NewFileThreshold: "50%"}, false},
+		{&ConfigDiffAcceptable{NoUncoveredNewFiles: true, NewFileThreshold: "50%", NewFileGrace: []string{"*.go"}}, false},
+		{&ConfigDiffAcceptable{NoUncoveredNewFiles: true, NewFileThreshold: "50%", NewFileGrace: []string{"./new.go"}}, false},
+		{&ConfigDiffAcceptable{NoUncoveredNewFiles: true, NewFileThreshold: "50%", NewFileGrace: []string{"pkg/**"}}, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Diff = &ConfigDiff{Acceptable: tt.acceptable}
+		if err := c.AcceptableNewFiles(r, files); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestDiffRetryPolicy(t *testing.T) {
+	tests := []struct {
+		retry   *ConfigDiffRetry
+		want    *internal.RetryPolicy
+		wantErr bool
+	}{
+		{nil, nil, false},
+		{&ConfigDiffRetry{Max: 3}, &internal.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, Multiplier: 2}, false},
+		{&ConfigDiffRetry{Max: 3, Interval: "500ms"}, &internal.RetryPolicy{MaxAttempts: 4, InitialBackoff: 500 * time.Millisecond, Multiplier: 2}, false},
+		{&ConfigDiffRetry{Interval: "5s"}, nil, false},
+		{&ConfigDiffRetry{Max: 3, Interval: "invalid"}, nil, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Diff = &ConfigDiff{Retry: tt.retry}
+		got, err := c.DiffRetryPolicy()
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
 		}
-		if err := c.Acceptable(r); err != nil {
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestDiffStale(t *testing.T) {
+	now := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		staleAfter string
+		timestamp  time.Time
+		wantErr    bool
+	}{
+		{"", now.Add(-24 * 365 * time.Hour), false},
+		{"1day", now.Add(-time.Hour), false},
+		{"1day", now.Add(-25 * time.Hour), true},
+		{"10min", now.Add(-11 * time.Minute), true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Diff = &ConfigDiff{StaleAfter: tt.staleAfter}
+		r2 := &report.Report{Timestamp: tt.timestamp}
+		if err := c.DiffStale(r2, now); err != nil {
 			if !tt.wantErr {
 				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
 			}
@@ -81,6 +816,88 @@ func TestCoverageAcceptable(t *testing.T) {
 	}
 }
 
+func TestBadgeLabel(t *testing.T) {
+	c := New()
+	if got, want := c.CoverageBadgeLabel(), "coverage"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.CodeToTestRatioBadgeLabel(), "code to test ratio"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	c.Coverage = &ConfigCoverage{Badge: ConfigCoverageBadge{Label: "cov", Style: "flat-square"}}
+	c.CodeToTestRatio = &ConfigCodeToTestRatio{Badge: ConfigCodeToTestRatioBadge{Label: "test ratio"}}
+	if got, want := c.CoverageBadgeLabel(), "cov"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.CoverageBadgeStyle(), "flat-square"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.CodeToTestRatioBadgeLabel(), "test ratio"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestCoverageBadgeColors(t *testing.T) {
+	in := "badge:\n  colors:\n    - threshold: 90\n      color: \"#4c1\"\n    - threshold: 0\n      color: red\n    - threshold: 70\n      color: yellow\n"
+	cc := &ConfigCoverage{}
+	if err := yaml.Unmarshal([]byte(in), cc); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		cover float64
+		want  string
+	}{
+		{95, "#4c1"},
+		{90, "#4c1"},
+		{85, "#DFB317"},
+		{60, "#E05D44"},
+		{-1, "#E05D44"},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = cc
+		if got := c.CoverageColor(tt.cover); got != tt.want {
+			t.Errorf("%v: got %v\nwant %v", tt.cover, got, tt.want)
+		}
+	}
+
+	c := New()
+	c.Coverage = &ConfigCoverage{Badge: ConfigCoverageBadge{Colors: []*ConfigCoverageBadgeColor{{Threshold: 50, Color: "invalid"}}}}
+	if _, err := c.CoverageBadgeColors(); err == nil {
+		t.Error("want err")
+	}
+	// invalid colors fall back to the default scheme
+	if got, want := c.CoverageColor(40), "#DFB317"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestCoverageColor(t *testing.T) {
+	tests := []struct {
+		scheme string
+		cover  float64
+		want   string
+	}{
+		{"", 85.0, badge.ColorGreen},
+		{"", 10.0, badge.ColorRed},
+		{ColorSchemeClassic, 50.0, badge.ColorYellow},
+		{"unknown", 50.0, badge.ColorYellow},
+		{ColorSchemeGradient, 0.0, badge.ColorRed},
+		{ColorSchemeGradient, 50.0, badge.ColorYellow},
+		{ColorSchemeGradient, 100.0, badge.ColorGreen},
+		{ColorSchemeGradient, 75.0, "#BBBF0B"},
+		{ColorSchemeMonochrome, 85.0, "#08519C"},
+		{ColorSchemeMonochrome, 10.0, "#C6DBEF"},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &ConfigCoverage{Badge: ConfigCoverageBadge{Scheme: tt.scheme}}
+		if got := c.CoverageColor(tt.cover); got != tt.want {
+			t.Errorf("%s %v: got %v\nwant %v", tt.scheme, tt.cover, got, tt.want)
+		}
+	}
+}
+
 func TestCodeToTestRatioAcceptable(t *testing.T) {
 	tests := []struct {
 		in      string
@@ -90,6 +907,8 @@ func TestCodeToTestRatioAcceptable(t *testing.T) {
 		{"1:1.1", true},
 		{"1", false},
 		{"1.1", true},
+		{"2:3", true},
+		{"1:-1", true},
 	}
 	for _, tt := range tests {
 		c := New()
@@ -103,7 +922,39 @@ func TestCodeToTestRatioAcceptable(t *testing.T) {
 			Code: 100,
 			Test: 100,
 		}
-		if err := c.Acceptable(r); err != nil {
+		if err := c.Acceptable(r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestLinesOfCodeAcceptable(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"12345", false},
+		{"12.3k", true},
+		{"13k", false},
+		{"1M", false},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.LinesOfCode = &ConfigLinesOfCode{
+			Acceptable: tt.in,
+		}
+		c.Build()
+		r := &report.Report{}
+		r.LinesOfCode = &loc.LOC{
+			Code: 12345,
+		}
+		if err := c.Acceptable(r, nil); err != nil {
 			if !tt.wantErr {
 				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
 			}
@@ -127,13 +978,192 @@ func TestTestExecutionTimeAcceptable(t *testing.T) {
 	for _, tt := range tests {
 		c := New()
 		c.TestExecutionTime = &ConfigTestExecutionTime{
-			Acceptable: tt.in,
+			Acceptable: ConfigTestExecutionTimeAcceptable{Total: tt.in},
 		}
 		c.Build()
 		r := &report.Report{}
 		e := float64(time.Minute)
 		r.TestExecutionTime = &e
-		if err := c.Acceptable(r); err != nil {
+		if err := c.Acceptable(r, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestTestExecutionTimeAcceptableDiff(t *testing.T) {
+	e := float64(70 * time.Second)
+	r := &report.Report{TestExecutionTime: &e}
+	pe := float64(time.Minute)
+	prev := &report.Report{TestExecutionTime: &pe}
+	tests := []struct {
+		in   string
+		prev *report.Report
+		want string
+	}{
+		{"", prev, ""},
+		{"10s", prev, ""},
+		{"+15s", prev, ""},
+		{"20%", prev, ""},
+		{"5s", prev, "test execution time is 1m10s (previous 1m0s), which increased by 10s and is above the accepted +5s"},
+		{"10%", prev, "test execution time is 1m10s (previous 1m0s), which increased by 10s and is above the accepted +6s"},
+		{"5s", nil, ""},
+		{"5s", &report.Report{}, ""},
+		{"-10%", prev, "testExecutionTime.acceptable.diff: invalid value -10%"},
+		{"invalid", prev, "testExecutionTime.acceptable.diff: invalid value invalid"},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.TestExecutionTime = &ConfigTestExecutionTime{
+			Acceptable: ConfigTestExecutionTimeAcceptable{Diff: tt.in},
+		}
+		c.Build()
+		got := ""
+		if err := c.Acceptable(r, tt.prev); err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestTestExecutionTimeAcceptableFormat(t *testing.T) {
+	c := New()
+	c.TestExecutionTime = &ConfigTestExecutionTime{
+		Acceptable: ConfigTestExecutionTimeAcceptable{Total: "1min"},
+		Badge: ConfigTestExecutionTimeBadge{
+			Unit: "s",
+		},
+	}
+	c.Build()
+	r := &report.Report{}
+	e := float64(62345 * time.Millisecond)
+	r.TestExecutionTime = &e
+	want := "test execution time is 62.3s, which is above the accepted 60.0s"
+	if err := c.Acceptable(r, nil); err == nil || err.Error() != want {
+		t.Errorf("got %v\nwant %v", err, want)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	envCache := os.Environ()
+	defer func() {
+		if err := revertEnv(envCache); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	tests := []struct {
+		c       *Config
+		envs    map[string]string
+		wantErr bool
+	}{
+		{
+			&Config{},
+			map[string]string{},
+			false,
+		},
+		{
+			&Config{Repository: "owner/repo", Report: &ConfigReport{Datastores: []string{"github://owner/reports"}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Repository: "owner/repo", Report: &ConfigReport{Datastores: []string{"github://owner/reports"}}},
+			map[string]string{"GITHUB_TOKEN": "xxx"},
+			false,
+		},
+		{
+			&Config{Repository: "owner/repo", Report: &ConfigReport{Datastores: []string{"gitlab://group/project"}}},
+			map[string]string{"CI_JOB_TOKEN": "xxx"},
+			false,
+		},
+		{
+			&Config{Repository: "owner/repo", Report: &ConfigReport{Datastores: []string{"s3://bucket/reports"}}},
+			map[string]string{},
+			false,
+		},
+		{
+			&Config{Report: &ConfigReport{Datastores: []string{"s3://bucket/reports"}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Comment: &ConfigComment{Enable: true}},
+			map[string]string{},
+			false,
+		},
+		{
+			&Config{Comment: &ConfigComment{Enable: true}},
+			map[string]string{"GITHUB_ACTIONS": "true"},
+			true,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Acceptable: ConfigCoverageAcceptable{PerFile: &ConfigCoverageAcceptablePerFile{Acceptable: "50%", Exclude: []string{"legacy/**"}}}}},
+			map[string]string{},
+			false,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Badge: ConfigCoverageBadge{Style: "for-the-badge"}}},
+			map[string]string{},
+			false,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Badge: ConfigCoverageBadge{Style: "plastic"}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Badge: ConfigCoverageBadge{Colors: []*ConfigCoverageBadgeColor{{Threshold: 90, Color: "purple"}}}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Badge: ConfigCoverageBadge{Logo: "go"}}},
+			map[string]string{},
+			false,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Badge: ConfigCoverageBadge{Path: "coverage.png", Logo: "go"}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Badge: ConfigCoverageBadge{Logo: "https://example.com/logo.svg"}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{CodeToTestRatio: &ConfigCodeToTestRatio{Acceptable: "1:x"}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Acceptable: ConfigCoverageAcceptable{PerFile: &ConfigCoverageAcceptablePerFile{Acceptable: "invalid"}}}},
+			map[string]string{},
+			true,
+		},
+		{
+			&Config{Coverage: &ConfigCoverage{Acceptable: ConfigCoverageAcceptable{PerFile: &ConfigCoverageAcceptablePerFile{Acceptable: "50%", Exclude: []string{"legacy/[**"}}}}},
+			map[string]string{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		if err := clearEnv(); err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range tt.envs {
+			if err := os.Setenv(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tt.c.Preflight(); err != nil {
 			if !tt.wantErr {
 				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
 			}
@@ -180,3 +1210,260 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestReviewEvent(t *testing.T) {
+	tests := []struct {
+		events   *ConfigCommentReviewEvents
+		accepted bool
+		want     string
+		wantErr  bool
+	}{
+		{nil, true, "COMMENT", false},
+		{nil, false, "REQUEST_CHANGES", false},
+		{&ConfigCommentReviewEvents{Pass: "approve"}, true, "APPROVE", false},
+		{&ConfigCommentReviewEvents{Fail: "COMMENT"}, false, "COMMENT", false},
+		{&ConfigCommentReviewEvents{Fail: "REJECT"}, true, "", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Comment = &ConfigComment{Enable: true, AsReview: true, ReviewEvents: tt.events}
+		got, err := c.ReviewEvent(tt.accepted)
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", got)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCodeToTestRatioExclude(t *testing.T) {
+	tests := []struct {
+		root    string
+		exclude []string
+		want    []string
+	}{
+		{"/path/to", []string{"**/*.pb.go", "./testdata/**"}, []string{"/path/to/**/*.pb.go", "/path/to/testdata/**"}},
+		{"/path/[to]", []string{"gen/*"}, []string{`/path/\[to\]/gen/*`}},
+		{"/", []string{"gen/*"}, []string{"/gen/*"}},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.path = filepath.Join(tt.root, ".octocov.yml")
+		c.CodeToTestRatio = &ConfigCodeToTestRatio{Exclude: tt.exclude}
+		got := c.CodeToTestRatioExclude()
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestCodeToTestRatioCountLogicalLines(t *testing.T) {
+	f := false
+	tr := true
+	tests := []struct {
+		c    *ConfigCodeToTestRatio
+		want bool
+	}{
+		{nil, true},
+		{&ConfigCodeToTestRatio{}, true},
+		{&ConfigCodeToTestRatio{CountLogicalLines: &tr}, true},
+		{&ConfigCodeToTestRatio{CountLogicalLines: &f}, false},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.CodeToTestRatio = tt.c
+		if got := c.CodeToTestRatioCountLogicalLines(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCommentHideFilesThreshold(t *testing.T) {
+	zero := 0
+	ten := 10
+	minus := -1
+	tests := []struct {
+		threshold *int
+		want      int
+		wantErr   bool
+	}{
+		{nil, report.FilesHideMin, false},
+		{&zero, 0, false},
+		{&ten, 10, false},
+		{&minus, 0, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Comment = &ConfigComment{Enable: true, HideFilesThreshold: tt.threshold}
+		got, err := c.CommentHideFilesThreshold()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCheckOnly(t *testing.T) {
+	c := New()
+	c.Coverage = &ConfigCoverage{Path: "coverage.out"}
+	c.Comment = &ConfigComment{Enable: true}
+	c.Checks = &ConfigChecks{Enable: true}
+	c.Report = &ConfigReport{Path: "report.json"}
+	c.Coverage.Badge.Path = "coverage.svg"
+	c.Notifications = &ConfigNotifications{Webhook: &ConfigNotificationsWebhook{URL: "https://example.com/hook"}}
+	readies := []func() error{c.CommentConfigReady, c.ChecksConfigReady, c.ReportConfigReady, c.NotificationsWebhookConfigReady, c.CoverageBadgeConfigReady}
+	for _, ready := range readies {
+		if err := ready(); err != nil {
+			t.Errorf("got %v\nwant %v", err, nil)
+		}
+	}
+	c.SetCheckOnly(true)
+	for _, ready := range append(readies, c.PushConfigReady) {
+		if err := ready(); err == nil {
+			t.Error("want error")
+		}
+	}
+	if err := c.CoverageConfigReady(); err != nil {
+		t.Errorf("got %v\nwant %v", err, nil)
+	}
+}
+
+func TestTestExecutionTimeFormat(t *testing.T) {
+	zero := 0
+	d := 62345 * time.Millisecond
+	tests := []struct {
+		badge   ConfigTestExecutionTimeBadge
+		want    string
+		wantErr bool
+	}{
+		{ConfigTestExecutionTimeBadge{}, "1m2.345s", false},
+		{ConfigTestExecutionTimeBadge{Unit: "s"}, "62.3s", false},
+		{ConfigTestExecutionTimeBadge{Precision: &zero}, "1m2s", false},
+		{ConfigTestExecutionTimeBadge{Unit: "ms", Precision: &zero}, "62345ms", false},
+		{ConfigTestExecutionTimeBadge{Unit: "h"}, "", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.TestExecutionTime = &ConfigTestExecutionTime{Badge: tt.badge}
+		f, err := c.TestExecutionTimeFormat()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := f.Format(d); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestTestExecutionTimeShowSlowest(t *testing.T) {
+	tests := []struct {
+		in      *ConfigTestExecutionTime
+		want    int
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{&ConfigTestExecutionTime{}, 0, false},
+		{&ConfigTestExecutionTime{ShowSlowest: 5}, 5, false},
+		{&ConfigTestExecutionTime{ShowSlowest: -1}, 0, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.TestExecutionTime = tt.in
+		got, err := c.TestExecutionTimeShowSlowest()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCommentTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "comment.md.tmpl"), []byte("## {{ .Repository }}\n{{ .Table }}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "invalid.md.tmpl"), []byte("{{ .Table "), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"comment.md.tmpl", "## owner/repo\n| table |", false},
+		{"invalid.md.tmpl", "", true},
+		{"notexist.md.tmpl", "", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Comment = &ConfigComment{Enable: true, Template: tt.template}
+		c.path = filepath.Join(dir, ".octocov.yml")
+		c.Build()
+		tmpl, err := c.CommentTemplate()
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want err", tt.template)
+			continue
+		}
+		if tmpl == nil {
+			if tt.want != "" {
+				t.Errorf("%s: got nil", tt.template)
+			}
+			continue
+		}
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, map[string]string{"Repository": "owner/repo", "Table": "| table |"}); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestFreshnessColor(t *testing.T) {
+	tests := []struct {
+		staleAfter string
+		age        time.Duration
+		want       string
+	}{
+		{"", 24 * time.Hour, badge.ColorGreen},
+		{"", 5 * 24 * time.Hour, badge.ColorYellow},
+		{"", 10 * 24 * time.Hour, badge.ColorOrange},
+		{"", 15 * 24 * time.Hour, badge.ColorRed},
+		{"1d", 10 * time.Hour, badge.ColorGreen},
+		{"1d", 13 * time.Hour, badge.ColorYellow},
+		{"1d", 3 * 24 * time.Hour, badge.ColorRed},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Central = &ConfigCentral{Badges: ConfigCentralBadges{Freshness: &ConfigCentralBadgesFreshness{StaleAfter: tt.staleAfter}}}
+		if got := c.FreshnessColor(tt.age); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
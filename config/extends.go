@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/expand"
+)
+
+// readConfigFile reads the config file and merges it onto the base config files of extends: recursively.
+// It returns the merged YAML and the paths of the read config files ( from the base ).
+func readConfigFile(p string, visited map[string]struct{}) ([]byte, []string, error) {
+	if _, ok := visited[p]; ok {
+		return nil, nil, fmt.Errorf("extends: circular reference of %s", p)
+	}
+	visited[p] = struct{}{}
+	buf, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		return nil, nil, err
+	}
+	b := expand.ExpandYAMLBytes(buf, expandenv)
+	e := struct {
+		Extends string `yaml:"extends"`
+	}{}
+	if err := yaml.Unmarshal(b, &e); err != nil {
+		return nil, nil, err
+	}
+	if e.Extends == "" {
+		return b, []string{p}, nil
+	}
+	bp := e.Extends
+	if !filepath.IsAbs(bp) {
+		// relative to the extending config file
+		bp = filepath.Join(filepath.Dir(p), bp)
+	}
+	base, paths, err := readConfigFile(bp, visited)
+	if err != nil {
+		return nil, nil, fmt.Errorf("extends: %w", err)
+	}
+	merged, err := mergeConfigYAML(base, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return merged, append(paths, p), nil
+}
+
+// mergeConfigYAML merges the YAML of the config onto the YAML of the base config.
+// The maps are merged recursively, and the other values ( including the arrays such as datastores ) replace the values of the base.
+func mergeConfigYAML(base, override []byte) ([]byte, error) {
+	bm := map[string]interface{}{}
+	if err := yaml.Unmarshal(base, &bm); err != nil {
+		return nil, err
+	}
+	om := map[string]interface{}{}
+	if err := yaml.Unmarshal(override, &om); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(mergeConfigMap(bm, om))
+}
+
+func mergeConfigMap(base, override map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+	for k, v := range override {
+		bv, ok := base[k].(map[string]interface{})
+		ov, ook := v.(map[string]interface{})
+		if ok && ook {
+			base[k] = mergeConfigMap(bv, ov)
+			continue
+		}
+		base[k] = v
+	}
+	return base
+}
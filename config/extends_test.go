@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadExtends(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base/base.yml": `coverage:
+  acceptable: 60%
+  badge:
+    path: docs/coverage.svg
+report:
+  datastores:
+    - s3://bucket/reports
+    - gs://bucket/reports
+comment:
+  enable: true
+  hideFooterLink: true
+`,
+		"base/octocov.yml": `extends: base.yml
+coverage:
+  badge:
+    label: cov
+`,
+		".octocov.yml": `extends: base/octocov.yml
+coverage:
+  acceptable: 80%
+report:
+  datastores:
+    - local://reports
+comment:
+  hideFooterLink: false
+`,
+	}
+	for p, in := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, p), []byte(in), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := New()
+	c.wd = dir
+	if err := c.Load(""); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Coverage.Acceptable.Total, "80%"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.Coverage.Badge.Path, "docs/coverage.svg"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.Coverage.Badge.Label, "cov"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	// arrays are replaced
+	if diff := cmp.Diff(c.Report.Datastores, []string{"local://reports"}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if !c.Comment.Enable || c.Comment.HideFooterLink {
+		t.Errorf("got %v and %v\nwant %v and %v", c.Comment.Enable, c.Comment.HideFooterLink, true, false)
+	}
+	if got, want := c.Root(), dir; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if err := c.UnknownFields(); err != nil {
+		t.Error(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "base", "base.yml"), []byte("extends: ../.octocov.yml\ncomments:\n  enable: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c = New()
+	c.wd = dir
+	if err := c.Load(""); err == nil {
+		t.Error("want error")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "base", "base.yml"), []byte("comments:\n  enable: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c = New()
+	c.wd = dir
+	if err := c.Load(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UnknownFields(); err == nil {
+		t.Error("want error")
+	}
+}
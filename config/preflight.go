@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/k1LoW/duration"
+	"github.com/k1LoW/octocov/pkg/badge"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+// Preflight checks that credentials and contexts required by the enabled features are available.
+// It reports all problems at once so that they are found before measuring.
+func (c *Config) Preflight() error {
+	errs := []string{}
+	if c.Central != nil && c.Central.Enable {
+		if err := c.CentralConfigReady(); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			errs = append(errs, datastoresPreflight("central.reports.datastores", c.Central.Reports.Datastores)...)
+			if os.Getenv("GITHUB_TOKEN") == "" {
+				errs = append(errs, "central: env GITHUB_TOKEN is not set")
+			}
+		}
+		return preflightError(errs)
+	}
+
+	if err := c.CommentConfigReady(); err == nil && os.Getenv("GITHUB_ACTIONS") != "" {
+		if os.Getenv("GITHUB_TOKEN") == "" {
+			errs = append(errs, "comment: env GITHUB_TOKEN is not set")
+		}
+		if c.Repository == "" {
+			errs = append(errs, "comment: repository: not set (or env GITHUB_REPOSITORY is not set)")
+		}
+		if err := c.DiffConfigReady(); err == nil {
+			errs = append(errs, datastoresPreflight("diff.datastores", c.Diff.Datastores)...)
+		}
+	}
+
+	if err := c.ChecksConfigReady(); err == nil && os.Getenv("GITHUB_ACTIONS") != "" {
+		if os.Getenv("GITHUB_TOKEN") == "" {
+			errs = append(errs, "checks: env GITHUB_TOKEN is not set")
+		}
+		if c.Repository == "" {
+			errs = append(errs, "checks: repository: not set (or env GITHUB_REPOSITORY is not set)")
+		}
+	}
+
+	errs = append(errs, c.ValueErrors()...)
+
+	if err := c.ReportConfigReady(); err == nil {
+		if c.Report.Path != "" {
+			if fi, err := os.Stat(filepath.Dir(c.Report.Path)); err != nil || !fi.IsDir() {
+				errs = append(errs, fmt.Sprintf("report.path: directory of %s does not exist", c.Report.Path))
+			}
+		}
+		if c.Report.PatchPath != "" {
+			if fi, err := os.Stat(filepath.Dir(c.Report.PatchPath)); err != nil || !fi.IsDir() {
+				errs = append(errs, fmt.Sprintf("report.patchPath: directory of %s does not exist", c.Report.PatchPath))
+			}
+			if os.Getenv("GITHUB_TOKEN") == "" {
+				errs = append(errs, "report.patchPath: env GITHUB_TOKEN is not set")
+			}
+		}
+		if len(c.Report.Datastores) > 0 && c.Repository == "" {
+			errs = append(errs, "report.datastores: repository: not set (or env GITHUB_REPOSITORY is not set)")
+		}
+		errs = append(errs, datastoresPreflight("report.datastores", c.Report.Datastores)...)
+	}
+
+	if err := c.PushConfigReady(); err == nil && os.Getenv("GITHUB_TOKEN") == "" {
+		errs = append(errs, "push: env GITHUB_TOKEN is not set")
+	}
+
+	return preflightError(errs)
+}
+
+// ValueErrors returns the invalid values of the config. They are checked regardless of the credentials and the contexts of the environment.
+func (c *Config) ValueErrors() []string {
+	errs := []string{}
+	if c.Coverage != nil && c.Coverage.Format != "" {
+		if _, ok := coverage.Parser(c.Coverage.Format); !ok {
+			errs = append(errs, fmt.Sprintf("coverage.format: invalid value %s", c.Coverage.Format))
+		}
+	}
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.Acceptable.PolicyFile != "" {
+		if _, err := report.ReadCoveragePolicy(c.Coverage.Acceptable.PolicyFile); err != nil {
+			errs = append(errs, fmt.Sprintf("coverage.acceptable.policyFile: %v", err))
+		}
+	}
+
+	if _, err := c.CoverageAcceptablePerFile(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if _, err := c.CoverageAcceptableDiff(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if _, err := c.CodeToTestRatioAcceptable(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if c.CodeToTestRatio != nil {
+		for _, p := range c.CodeToTestRatio.Exclude {
+			if !doublestar.ValidatePattern(filepath.ToSlash(p)) {
+				errs = append(errs, fmt.Sprintf("codeToTestRatio.exclude: invalid pattern: %s", p))
+			}
+		}
+		for i, l := range c.CodeToTestRatio.Languages {
+			if l.Name == "" {
+				errs = append(errs, fmt.Sprintf("codeToTestRatio.languages[%d].name: is not set", i))
+			}
+			if len(l.Code) == 0 {
+				errs = append(errs, fmt.Sprintf("codeToTestRatio.languages[%d].code: is not set", i))
+			}
+		}
+	}
+
+	if c.TestExecutionTime != nil && c.TestExecutionTime.Acceptable.Total != "" {
+		if _, err := duration.Parse(c.TestExecutionTime.Acceptable.Total); err != nil {
+			errs = append(errs, fmt.Sprintf("testExecutionTime.acceptable: invalid value %s", c.TestExecutionTime.Acceptable.Total))
+		}
+	}
+
+	if _, err := c.TestExecutionTimeAcceptableDiff(0); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if _, err := c.TestExecutionTimeShowSlowest(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if _, err := c.CoverageBadgeColors(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if err := badge.ValidateStyle(c.CoverageBadgeStyle()); err != nil {
+		errs = append(errs, fmt.Sprintf("coverage.badge.style: %v", err))
+	}
+
+	if err := badge.ValidateLogo(c.CoverageBadgeLogo()); err != nil {
+		errs = append(errs, fmt.Sprintf("coverage.badge.logo: %v", err))
+	} else if logo := c.CoverageBadgeLogo(); logo != "" && strings.EqualFold(filepath.Ext(c.Coverage.Badge.Path), ".png") && !strings.HasPrefix(logo, "data:image/png;") {
+		errs = append(errs, "coverage.badge.logo: only the PNG logo is supported in PNG badges")
+	}
+
+	if c.Coverage != nil && c.Coverage.Acceptable.PerFile != nil {
+		for _, p := range c.Coverage.Acceptable.PerFile.Exclude {
+			if !doublestar.ValidatePattern(strings.TrimPrefix(filepath.ToSlash(p), "./")) {
+				errs = append(errs, fmt.Sprintf("coverage.acceptable.perFile.exclude: invalid pattern: %s", p))
+			}
+		}
+	}
+
+	if _, err := c.TablesGroupDepth(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if _, _, err := c.PerPackageDepth(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if _, err := c.DatastoresRetryPolicy(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if c.Coverage != nil {
+		for _, p := range c.Coverage.Exclude {
+			if !doublestar.ValidatePattern(strings.TrimPrefix(filepath.ToSlash(p), "./")) {
+				errs = append(errs, fmt.Sprintf("coverage.exclude: invalid pattern: %s", p))
+			}
+		}
+	}
+
+	if c.Diff != nil && c.Diff.Acceptable != nil {
+		for _, p := range c.Diff.Acceptable.NewFileGrace {
+			if !doublestar.ValidatePattern(strings.TrimPrefix(filepath.ToSlash(p), "./")) {
+				errs = append(errs, fmt.Sprintf("diff.acceptable.newFileGrace: invalid pattern: %s", p))
+			}
+		}
+	}
+
+	if err := c.CommentConfigReady(); err == nil && c.Comment.AsReview {
+		if _, err := c.ReviewEvent(true); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if err := c.CommentConfigReady(); err == nil {
+		if err := report.ValidateMetrics(c.Comment.Metrics); err != nil {
+			errs = append(errs, fmt.Sprintf("comment.metrics: %v", err))
+		}
+		if _, err := c.CommentTemplate(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if _, err := c.CommentHideFilesThreshold(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	return errs
+}
+
+func datastoresPreflight(key string, datastores []string) []string {
+	errs := []string{}
+	for _, u := range datastores {
+		for _, env := range datastoreRequiredEnvs(u) {
+			vars := strings.Split(env, "|")
+			ok := false
+			for _, v := range vars {
+				if os.Getenv(v) != "" {
+					ok = true
+					break
+				}
+			}
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: env %s is not set (%s)", key, strings.Join(vars, " or "), u))
+			}
+		}
+	}
+	return errs
+}
+
+// datastoreRequiredEnvs returns environment variables required by the datastore.
+// Alternatives are separated by `|`.
+func datastoreRequiredEnvs(u string) []string {
+	switch {
+	case strings.HasPrefix(u, "github://"):
+		return []string{"GITHUB_TOKEN"}
+	case strings.HasPrefix(u, "gitlab://"):
+		return []string{"GITLAB_TOKEN|CI_JOB_TOKEN"}
+	case strings.HasPrefix(u, "gitea://"):
+		return []string{"GITEA_URL", "GITEA_TOKEN"}
+	case strings.HasPrefix(u, "mackerel://"):
+		return []string{"MACKEREL_APIKEY"}
+	default:
+		// S3, GCS and BigQuery credentials can be provided in many ways ( instance profile, workload identity, ... )
+		return []string{}
+	}
+}
+
+func preflightError(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("preflight check failed:\n  - %s", strings.Join(errs, "\n  - "))
+}
@@ -3,18 +3,44 @@ package config
 import (
 	"errors"
 	"fmt"
+	"regexp"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/k1LoW/duration"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/pkg/coverage"
 )
 
+var errCheckOnly = errors.New("check-only mode is enabled")
+
 func (c *Config) CoverageConfigReady() error {
 	if c.Coverage == nil {
 		return errors.New("coverage: is not set")
 	}
+	if c.Coverage.Format != "" {
+		if _, ok := coverage.Parser(c.Coverage.Format); !ok {
+			return fmt.Errorf("coverage.format: invalid value %s", c.Coverage.Format)
+		}
+	}
+	if c.Coverage.Format == coverageFormatCustom && c.Coverage.Command == "" {
+		return errors.New("coverage.command: is not set")
+	}
 	if c.Coverage.Path == "" {
 		return errors.New("coverage.path: is not set")
 	}
 	return nil
 }
 
+func (c *Config) CriticalCoverageConfigReady() error {
+	if err := c.CoverageConfigReady(); err != nil {
+		return err
+	}
+	if c.Coverage.Critical == nil || len(c.Coverage.Critical.Paths) == 0 {
+		return errors.New("coverage.critical.paths: is not set")
+	}
+	return nil
+}
+
 func (c *Config) CodeToTestRatioConfigReady() error {
 	if c.CodeToTestRatio == nil {
 		return errors.New("codeToTestRatio: is not set")
@@ -25,25 +51,35 @@ func (c *Config) CodeToTestRatioConfigReady() error {
 	return nil
 }
 
+func (c *Config) LinesOfCodeConfigReady() error {
+	if c.LinesOfCode == nil {
+		return errors.New("linesOfCode: is not set")
+	}
+	return nil
+}
+
 func (c *Config) TestExecutionTimeConfigReady() error {
 	if c.TestExecutionTime == nil {
 		return errors.New("testExecutionTime: is not set")
 	}
-	if err := c.CoverageConfigReady(); err != nil && len(c.TestExecutionTime.Steps) == 0 {
+	if err := c.CoverageConfigReady(); err != nil && len(c.TestExecutionTime.Steps) == 0 && len(c.TestExecutionTime.StepsInclude) == 0 && len(c.TestExecutionTime.StepsExclude) == 0 && c.TestExecutionTime.Path == "" {
 		return err
 	}
 	return nil
 }
 
 func (c *Config) PushConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
 	if c.Push == nil {
 		return errors.New("push: is not set")
 	}
 	if !c.Push.Enable {
 		return errors.New("push.enable: is false")
 	}
-	if c.GitRoot == "" {
-		return errors.New("failed to traverse the Git root path")
+	if err := c.GitRootReady(); err != nil {
+		return err
 	}
 	ok, err := CheckIf(c.Push.If)
 	if err != nil {
@@ -56,6 +92,9 @@ func (c *Config) PushConfigReady() error {
 }
 
 func (c *Config) CommentConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
 	if c.Comment == nil {
 		return errors.New("comment: is not set")
 	}
@@ -65,7 +104,26 @@ func (c *Config) CommentConfigReady() error {
 	return nil
 }
 
+func (c *Config) ChecksConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
+	if c.Checks == nil {
+		return errors.New("checks: is not set")
+	}
+	if !c.Checks.Enable {
+		return errors.New("checks.enable: is false")
+	}
+	if err := c.CoverageConfigReady(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Config) CoverageBadgeConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
 	if err := c.CoverageConfigReady(); err != nil {
 		return err
 	}
@@ -75,7 +133,23 @@ func (c *Config) CoverageBadgeConfigReady() error {
 	return nil
 }
 
+func (c *Config) CriticalCoverageBadgeConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
+	if err := c.CriticalCoverageConfigReady(); err != nil {
+		return err
+	}
+	if c.Coverage.Critical.Badge.Path == "" {
+		return errors.New("coverage.critical.badge.path: is not set")
+	}
+	return nil
+}
+
 func (c *Config) CodeToTestRatioBadgeConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
 	if err := c.CodeToTestRatioConfigReady(); err != nil {
 		return err
 	}
@@ -85,7 +159,23 @@ func (c *Config) CodeToTestRatioBadgeConfigReady() error {
 	return nil
 }
 
+func (c *Config) LinesOfCodeBadgeConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
+	if err := c.LinesOfCodeConfigReady(); err != nil {
+		return err
+	}
+	if c.LinesOfCode.Badge.Path == "" {
+		return errors.New("linesOfCode.badge.path: is not set")
+	}
+	return nil
+}
+
 func (c *Config) TestExecutionTimeBadgeConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
 	if err := c.TestExecutionTimeConfigReady(); err != nil {
 		return err
 	}
@@ -108,6 +198,50 @@ func (c *Config) CentralConfigReady() error {
 	if len(c.Central.Reports.Datastores) == 0 {
 		return errors.New("central.reports.datastores is not set")
 	}
+	if c.Central.Reports.Limit < 0 {
+		return fmt.Errorf("central.reports.limit: invalid value %d", c.Central.Reports.Limit)
+	}
+	if _, _, err := c.CentralReportsSignedURLTTL(); err != nil {
+		return err
+	}
+	for _, p := range c.Central.Reports.Include {
+		if !doublestar.ValidatePattern(p) {
+			return fmt.Errorf("central.reports.include: invalid pattern: %s", p)
+		}
+	}
+	for _, p := range c.Central.Reports.Exclude {
+		if !doublestar.ValidatePattern(p) {
+			return fmt.Errorf("central.reports.exclude: invalid pattern: %s", p)
+		}
+	}
+	switch c.Central.Index.Sort {
+	case "", "name", "coverage", "updated":
+	default:
+		return fmt.Errorf("central.index.sort: invalid value %s (name, coverage or updated)", c.Central.Index.Sort)
+	}
+	if c.Central.Index.Top < 0 {
+		return fmt.Errorf("central.index.top: invalid value %d", c.Central.Index.Top)
+	}
+	if g := c.Central.Index.GroupBy; g != "" && g != "owner" {
+		if _, err := regexp.Compile(g); err != nil {
+			return fmt.Errorf("central.index.groupBy: invalid value %s: %w", g, err)
+		}
+	}
+	if o := c.Central.Badges.Overall; o != nil {
+		switch o.Weight {
+		case "", "mean", "loc":
+		default:
+			return fmt.Errorf("central.badges.overall.weight: invalid value %s (mean or loc)", o.Weight)
+		}
+	}
+	if _, err := c.CentralStaleAfter(); err != nil {
+		return err
+	}
+	if f := c.Central.Badges.Freshness; f != nil && f.StaleAfter != "" {
+		if _, err := duration.Parse(f.StaleAfter); err != nil {
+			return fmt.Errorf("central.badges.freshness.staleAfter: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -118,8 +252,8 @@ func (c *Config) CentralPushConfigReady() error {
 	if !c.Central.Push.Enable {
 		return errors.New("central.puth.enable: is false")
 	}
-	if c.GitRoot == "" {
-		return errors.New("failed to traverse the Git root path")
+	if err := c.GitRootReady(); err != nil {
+		return err
 	}
 	ok, err := CheckIf(c.Central.Push.If)
 	if err != nil {
@@ -142,6 +276,9 @@ func (c *Config) DiffConfigReady() error {
 }
 
 func (c *Config) ReportConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
 	if c.Report == nil {
 		return errors.New("report: is not set")
 	}
@@ -162,8 +299,31 @@ func (c *Config) ReportConfigTargetReady() error {
 	if c.Report == nil {
 		return errors.New("report: is not set")
 	}
-	if c.Report.Path == "" && len(c.Report.Datastores) == 0 {
-		return errors.New("report.datastores: and report.path: are not set")
+	if c.Report.Path == "" && c.Report.PatchPath == "" && c.Report.MetricsPath == "" && len(c.Report.Datastores) == 0 {
+		return errors.New("report.datastores:, report.path:, report.patchPath: and report.metricsPath: are not set")
+	}
+	return nil
+}
+
+func (c *Config) NotificationsWebhookConfigReady() error {
+	if c.checkOnly {
+		return errCheckOnly
+	}
+	if c.Notifications == nil || c.Notifications.Webhook == nil {
+		return errors.New("notifications.webhook: is not set")
+	}
+	if c.Notifications.Webhook.URL == "" {
+		return errors.New("notifications.webhook.url: is not set")
+	}
+	return nil
+}
+
+func (c *Config) GitRootReady() error {
+	if c.GitRoot == "" {
+		return errors.New("failed to traverse the Git root path")
+	}
+	if err := internal.ValidateGitRoot(c.GitRoot); err != nil {
+		return fmt.Errorf("gitRoot: %w", err)
 	}
 	return nil
 }
@@ -1,7 +1,49 @@
 package config
 
+const (
+	TimestampSourceNow    = "now"
+	TimestampSourceCommit = "commit"
+)
+
 type ConfigReport struct {
-	If         string   `yaml:"if,omitempty"`
-	Path       string   `yaml:"path,omitempty"`
-	Datastores []string `yaml:"datastores,omitempty"`
+	If              string                `yaml:"if,omitempty"`
+	Path            string                `yaml:"path,omitempty"`
+	Datastores      []string              `yaml:"datastores,omitempty"`
+	Concurrency     int                   `yaml:"concurrency,omitempty"`
+	BestEffort      bool                  `yaml:"bestEffort,omitempty"`
+	TimestampSource string                `yaml:"timestampSource,omitempty"`
+	IncludeAuthor   bool                  `yaml:"includeAuthor,omitempty"`
+	StoreByRef      bool                  `yaml:"storeByRef,omitempty"`
+	PatchPath       string                `yaml:"patchPath,omitempty"`
+	MetricsPath     string                `yaml:"metricsPath,omitempty"`
+	Coverage        *ConfigReportCoverage `yaml:"coverage,omitempty"`
+}
+
+type ConfigReportCoverage struct {
+	PerPackage *ConfigReportCoveragePerPackage `yaml:"perPackage,omitempty"`
+}
+
+// ConfigReportCoveragePerPackage accepts both `perPackage: true` and `perPackage: {depth: 2}`
+type ConfigReportCoveragePerPackage struct {
+	Enable bool `yaml:"enable,omitempty"`
+	Depth  int  `yaml:"depth,omitempty"`
+}
+
+func (p *ConfigReportCoveragePerPackage) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var b bool
+	if err := unmarshal(&b); err == nil {
+		p.Enable = b
+		return nil
+	}
+	type alias struct {
+		Enable *bool `yaml:"enable,omitempty"`
+		Depth  int   `yaml:"depth,omitempty"`
+	}
+	a := alias{}
+	if err := unmarshal(&a); err != nil {
+		return err
+	}
+	p.Enable = a.Enable == nil || *a.Enable
+	p.Depth = a.Depth
+	return nil
 }
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaURI is the JSON Schema dialect of the schema of the config
+const SchemaURI = "http://json-schema.org/draft-07/schema#"
+
+// schemaShorthands are the schemas of the shorthand forms accepted by UnmarshalYAML of the types ( e.g. `acceptable: 60%` )
+var schemaShorthands = map[reflect.Type]map[string]interface{}{
+	reflect.TypeOf(ConfigCoverageTables{}):              {"type": "string"},
+	reflect.TypeOf(ConfigCoverageCritical{}):            {"type": "array", "items": map[string]interface{}{"type": "string"}},
+	reflect.TypeOf(ConfigCoverageAcceptable{}):          {"type": "string"},
+	reflect.TypeOf(ConfigCoverageAcceptablePerFile{}):   {"type": "string"},
+	reflect.TypeOf(ConfigTestExecutionTimeAcceptable{}): {"type": "string"},
+	reflect.TypeOf(ConfigCentralBadges{}):               {"type": "string"},
+	reflect.TypeOf(ConfigReportCoveragePerPackage{}):    {"type": "boolean"},
+}
+
+// Schema returns the JSON Schema of the config generated from the struct tags of Config
+func Schema() map[string]interface{} {
+	s := typeSchema(reflect.TypeOf(Config{}))
+	s["$schema"] = SchemaURI
+	s["title"] = "octocov config"
+	return s
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			props[name] = typeSchema(f.Type)
+		}
+		s := map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if sh, ok := schemaShorthands[t]; ok {
+			return map[string]interface{}{"anyOf": []interface{}{sh, s}}
+		}
+		return s
+	default:
+		return map[string]interface{}{}
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+type yamlUnmarshaler interface {
+	UnmarshalYAML(unmarshal func(interface{}) error) error
+}
+
+func TestSchema(t *testing.T) {
+	s := Schema()
+	if got := s["$schema"]; got != SchemaURI {
+		t.Errorf("got %v\nwant %v", got, SchemaURI)
+	}
+	props, ok := s["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("got %v\nwant properties", s)
+	}
+	tests := []string{"repository", "coverage", "codeToTestRatio", "testExecutionTime", "central", "push", "report", "comment", "diff", "extends"}
+	for _, tt := range tests {
+		if _, ok := props[tt]; !ok {
+			t.Errorf("got %v\nwant property %s", props, tt)
+		}
+	}
+	if _, ok := props["paths"]; ok {
+		t.Error("unexported field should not be in the schema")
+	}
+}
+
+func TestSchemaShorthands(t *testing.T) {
+	u := reflect.TypeOf((*yamlUnmarshaler)(nil)).Elem()
+	visited := map[reflect.Type]bool{}
+	var walk func(typ reflect.Type)
+	walk = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || visited[typ] {
+			return
+		}
+		visited[typ] = true
+		if reflect.PtrTo(typ).Implements(u) {
+			if _, ok := schemaShorthands[typ]; !ok {
+				t.Errorf("%s has UnmarshalYAML but its shorthand is not in schemaShorthands", typ)
+			}
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).PkgPath != "" {
+				continue
+			}
+			walk(typ.Field(i).Type)
+		}
+	}
+	walk(reflect.TypeOf(Config{}))
+}
@@ -0,0 +1,189 @@
+package azblob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/fstest"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/report"
+)
+
+const defaultEndpointSuffix = "core.windows.net"
+
+type AzBlob struct {
+	client *container.Client
+	prefix string
+}
+
+func New(client *container.Client, prefix string) (*AzBlob, error) {
+	return &AzBlob{
+		client: client,
+		prefix: prefix,
+	}, nil
+}
+
+// NewContainerClient returns the client of the container authenticated by env AZURE_STORAGE_CONNECTION_STRING,
+// or AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY
+func NewContainerClient(name string) (*container.Client, error) {
+	var (
+		endpoint string
+		cred     *container.SharedKeyCredential
+		sas      string
+	)
+	if cs := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); cs != "" {
+		var (
+			account, key string
+			err          error
+		)
+		endpoint, account, key, sas, err = parseConnectionString(cs)
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			cred, err = container.NewSharedKeyCredential(account, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+		key := os.Getenv("AZURE_STORAGE_KEY")
+		if account == "" || key == "" {
+			return nil, errors.New("env AZURE_STORAGE_CONNECTION_STRING or AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY are not set")
+		}
+		c, err := container.NewSharedKeyCredential(account, key)
+		if err != nil {
+			return nil, err
+		}
+		cred = c
+		endpoint = fmt.Sprintf("https://%s.blob.%s", account, defaultEndpointSuffix)
+	}
+	u, err := url.Parse(fmt.Sprintf("%s/%s", strings.TrimSuffix(endpoint, "/"), name))
+	if err != nil {
+		return nil, err
+	}
+	if cred == nil {
+		// the shared access signature in the URL
+		u.RawQuery = strings.TrimPrefix(sas, "?")
+		return container.NewClientWithNoCredential(u.String(), nil)
+	}
+	return container.NewClientWithSharedKeyCredential(u.String(), cred, nil)
+}
+
+// parseConnectionString parses the connection string of the storage account into the blob endpoint, the account name, the account key and the shared access signature
+func parseConnectionString(cs string) (string, string, string, string, error) {
+	values := map[string]string{}
+	for i, kv := range strings.Split(cs, ";") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		splitted := strings.SplitN(kv, "=", 2)
+		if len(splitted) != 2 {
+			// the segment is not printed because it may be a part of the secret
+			return "", "", "", "", fmt.Errorf("invalid connection string: segment %d is not key=value", i)
+		}
+		values[splitted[0]] = splitted[1]
+	}
+	account := values["AccountName"]
+	key := values["AccountKey"]
+	sas := values["SharedAccessSignature"]
+	if key == "" && sas == "" {
+		return "", "", "", "", errors.New("invalid connection string: AccountKey or SharedAccessSignature is not set")
+	}
+	if key != "" && account == "" {
+		return "", "", "", "", errors.New("invalid connection string: AccountName is not set")
+	}
+	endpoint := values["BlobEndpoint"]
+	if endpoint == "" {
+		if account == "" {
+			return "", "", "", "", errors.New("invalid connection string: AccountName or BlobEndpoint is not set")
+		}
+		protocol := values["DefaultEndpointsProtocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := values["EndpointSuffix"]
+		if suffix == "" {
+			suffix = defaultEndpointSuffix
+		}
+		endpoint = fmt.Sprintf("%s://%s.blob.%s", protocol, account, suffix)
+	}
+	return endpoint, account, key, sas, nil
+}
+
+func (a *AzBlob) Store(ctx context.Context, r *report.Report) error {
+	return a.store(ctx, fmt.Sprintf("%s/report.json", r.Repository), r.Bytes())
+}
+
+// StoreByRef stores the report keyed by the ref of the report
+func (a *AzBlob) StoreByRef(ctx context.Context, r *report.Report) error {
+	return a.store(ctx, internal.RefReportPath(r.Repository, r.Ref), r.Bytes())
+}
+
+// StorePatchCoverage stores the patch coverage keyed by the ref of the patch coverage
+func (a *AzBlob) StorePatchCoverage(ctx context.Context, pc *report.PatchCoverage) error {
+	return a.store(ctx, internal.RefPatchCoveragePath(pc.Repository, pc.Ref), pc.Bytes())
+}
+
+func (a *AzBlob) store(ctx context.Context, path string, content []byte) error {
+	key := filepath.ToSlash(filepath.Join(a.prefix, path))
+	contentType := "application/json"
+	if _, err := a.client.NewBlockBlobClient(key).UploadBuffer(ctx, content, &blockblob.UploadBufferOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FS returns the JSON blobs under the prefix. The blobs are listed page by page, and the content of a blob is downloaded when it is opened.
+func (a *AzBlob) FS() (fs.FS, error) {
+	ctx := context.Background()
+	index := fstest.MapFS{}
+	prefix := ""
+	if a.prefix != "" {
+		prefix = strings.Trim(filepath.ToSlash(a.prefix), "/") + "/"
+	}
+	pager := a.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		res, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, bi := range res.Segment.BlobItems {
+			if bi.Name == nil || !strings.HasSuffix(*bi.Name, ".json") {
+				continue
+			}
+			f := &fstest.MapFile{Mode: fs.ModePerm}
+			if bi.Properties != nil && bi.Properties.LastModified != nil {
+				f.ModTime = *bi.Properties.LastModified
+			}
+			index[strings.TrimPrefix(*bi.Name, prefix)] = f
+		}
+	}
+	return internal.NewLazyFS(index, func(ctx context.Context, name string) ([]byte, error) {
+		return a.download(ctx, prefix+name)
+	}), nil
+}
+
+func (a *AzBlob) download(ctx context.Context, name string) ([]byte, error) {
+	res, err := a.client.NewBlobClient(name).DownloadStream(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	body := res.NewRetryReader(ctx, &blob.RetryReaderOptions{MaxRetries: 3})
+	defer body.Close()
+	return io.ReadAll(body)
+}
@@ -0,0 +1,204 @@
+package azblob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/k1LoW/octocov/internal/testutil"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStoreAndFS(t *testing.T) {
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	downloads := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := strings.TrimPrefix(req.URL.Path, "/container/")
+		switch {
+		case req.Method == http.MethodPut:
+			b, err := io.ReadAll(req.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			blobs[name] = b
+			w.WriteHeader(http.StatusCreated)
+		case req.URL.Query().Get("comp") == "list":
+			names := []string{}
+			for n := range blobs {
+				if strings.HasPrefix(n, req.URL.Query().Get("prefix")) {
+					names = append(names, n)
+				}
+			}
+			sort.Strings(names)
+			// one blob per page
+			i := 0
+			if m := req.URL.Query().Get("marker"); m != "" {
+				i, _ = strconv.Atoi(m)
+			}
+			items := ""
+			next := ""
+			if i < len(names) {
+				items = fmt.Sprintf("<Blob><Name>%s</Name><Properties><Last-Modified>%s</Last-Modified></Properties></Blob>", names[i], time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC).Format(http.TimeFormat))
+				if i+1 < len(names) {
+					next = strconv.Itoa(i + 1)
+				}
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>%s</Blobs><NextMarker>%s</NextMarker></EnumerationResults>`, items, next)
+		case req.Method == http.MethodGet:
+			b, ok := blobs[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			downloads++
+			_, _ = w.Write(b)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	testutil.SetEnv(t, "AZURE_STORAGE_CONNECTION_STRING", fmt.Sprintf("BlobEndpoint=%s;AccountName=account;AccountKey=a2V5", ts.URL))
+
+	cc, err := NewContainerClient("container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(cc, "reports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	r := &report.Report{Repository: "owner/repo", Ref: "refs/heads/main"}
+	if err := a.Store(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Store(ctx, &report.Report{Repository: "owner/other", Ref: "refs/heads/main"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blobs["reports/owner/repo/report.json"]; !ok {
+		t.Errorf("report is not stored: %v", blobs)
+	}
+	blobs["reports/README.md"] = []byte("not a report")
+	fsys, err := a.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := fs.ReadDir(fsys, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(entries); got != 2 {
+		t.Errorf("got %v\nwant %v", got, 2)
+	}
+	if downloads != 0 {
+		t.Errorf("got %v\nwant %v", downloads, 0)
+	}
+	f, err := fsys.Open("owner/repo/report.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 1 {
+		t.Errorf("got %v\nwant %v", downloads, 1)
+	}
+	got := &report.Report{}
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Repository != r.Repository {
+		t.Errorf("got %v\nwant %v", got.Repository, r.Repository)
+	}
+	if _, err := fsys.Open("README.md"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestFSWithSharedAccessSignature(t *testing.T) {
+	sigs := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sigs = append(sigs, req.URL.Query().Get("sig"))
+		if req.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs></Blobs><NextMarker></NextMarker></EnumerationResults>`)
+	}))
+	t.Cleanup(ts.Close)
+	testutil.SetEnv(t, "AZURE_STORAGE_CONNECTION_STRING", fmt.Sprintf("BlobEndpoint=%s;SharedAccessSignature=sv=2020-08-04&sig=xxx", ts.URL))
+
+	cc, err := NewContainerClient("container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(cc, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.FS(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs) != 1 || sigs[0] != "xxx" {
+		t.Errorf("got %v\nwant %v", sigs, []string{"xxx"})
+	}
+}
+
+func TestParseConnectionString(t *testing.T) {
+	tests := []struct {
+		in           string
+		wantEndpoint string
+		wantAccount  string
+		wantKey      string
+		wantSAS      string
+		wantErr      bool
+	}{
+		{"DefaultEndpointsProtocol=https;AccountName=account;AccountKey=a2V5;EndpointSuffix=core.windows.net", "https://account.blob.core.windows.net", "account", "a2V5", "", false},
+		{"AccountName=account;AccountKey=a2V5==", "https://account.blob.core.windows.net", "account", "a2V5==", "", false},
+		{"BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;AccountName=devstoreaccount1;AccountKey=a2V5", "http://127.0.0.1:10000/devstoreaccount1", "devstoreaccount1", "a2V5", "", false},
+		{"BlobEndpoint=https://account.blob.core.windows.net/;SharedAccessSignature=sv=2020-08-04&sig=xxx", "https://account.blob.core.windows.net/", "", "", "sv=2020-08-04&sig=xxx", false},
+		{"AccountName=account", "", "", "", "", true},
+		{"AccountKey=a2V5", "", "", "", "", true},
+		{"invalid", "", "", "", "", true},
+		{"AccountName=account;c2VjcmV0", "", "", "", "", true},
+	}
+	for _, tt := range tests {
+		endpoint, account, key, sas, err := parseConnectionString(tt.in)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil && strings.Contains(err.Error(), "c2VjcmV0") {
+			t.Errorf("got %v\nwant the error without the segment", err)
+		}
+		if endpoint != tt.wantEndpoint {
+			t.Errorf("got %v\nwant %v", endpoint, tt.wantEndpoint)
+		}
+		if account != tt.wantAccount {
+			t.Errorf("got %v\nwant %v", account, tt.wantAccount)
+		}
+		if key != tt.wantKey {
+			t.Errorf("got %v\nwant %v", key, tt.wantKey)
+		}
+		if sas != tt.wantSAS {
+			t.Errorf("got %v\nwant %v", sas, tt.wantSAS)
+		}
+	}
+}
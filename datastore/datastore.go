@@ -2,34 +2,72 @@ package datastore
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io/fs"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	azd "github.com/k1LoW/octocov/datastore/azblob"
 	"github.com/k1LoW/octocov/datastore/bq"
 	"github.com/k1LoW/octocov/datastore/gcs"
+	"github.com/k1LoW/octocov/datastore/gitea"
 	"github.com/k1LoW/octocov/datastore/github"
+	"github.com/k1LoW/octocov/datastore/gitlab"
 	"github.com/k1LoW/octocov/datastore/local"
+	"github.com/k1LoW/octocov/datastore/mackerel"
 	s3d "github.com/k1LoW/octocov/datastore/s3"
+	"github.com/k1LoW/octocov/datastore/sheets"
+	"github.com/k1LoW/octocov/datastore/shields"
+	"github.com/k1LoW/octocov/datastore/sqlite"
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/report"
 	"google.golang.org/api/option"
+	sheetsapi "google.golang.org/api/sheets/v4"
 )
 
 type DatastoreType int
 
 var (
 	_ Datastore = (*github.Github)(nil)
+	_ Datastore = (*gitlab.Gitlab)(nil)
+	_ Datastore = (*gitea.Gitea)(nil)
 	_ Datastore = (*s3d.S3)(nil)
 	_ Datastore = (*gcs.GCS)(nil)
+	_ Datastore = (*azd.AzBlob)(nil)
 	_ Datastore = (*bq.BQ)(nil)
 	_ Datastore = (*local.Local)(nil)
+	_ Datastore = (*shields.Shields)(nil)
+	_ Datastore = (*sheets.Sheets)(nil)
+	_ Datastore = (*mackerel.Mackerel)(nil)
+	_ Datastore = (*sqlite.SQLite)(nil)
+
+	_ RefStorer = (*github.Github)(nil)
+	_ RefStorer = (*gitlab.Gitlab)(nil)
+	_ RefStorer = (*gitea.Gitea)(nil)
+	_ RefStorer = (*s3d.S3)(nil)
+	_ RefStorer = (*gcs.GCS)(nil)
+	_ RefStorer = (*azd.AzBlob)(nil)
+	_ RefStorer = (*local.Local)(nil)
+
+	_ PatchStorer = (*github.Github)(nil)
+	_ PatchStorer = (*s3d.S3)(nil)
+	_ PatchStorer = (*gcs.GCS)(nil)
+	_ PatchStorer = (*azd.AzBlob)(nil)
+	_ PatchStorer = (*local.Local)(nil)
+
+	_ URLSigner = (*s3d.S3)(nil)
+	_ URLSigner = (*gcs.GCS)(nil)
 )
 
 type Datastore interface {
@@ -37,6 +75,21 @@ type Datastore interface {
 	FS() (fs.FS, error)
 }
 
+// RefStorer is implemented by datastores that can store reports keyed by ref
+type RefStorer interface {
+	StoreByRef(ctx context.Context, r *report.Report) error
+}
+
+// PatchStorer is implemented by datastores that can store the patch coverage ( report.patchPath ) keyed by ref
+type PatchStorer interface {
+	StorePatchCoverage(ctx context.Context, pc *report.PatchCoverage) error
+}
+
+// URLSigner is implemented by datastores that can issue signed URLs of the stored reports
+type URLSigner interface {
+	SignedURL(ctx context.Context, path string, ttl time.Duration) (string, error)
+}
+
 func New(ctx context.Context, u, configRoot string) (Datastore, error) {
 	d, args, err := parse(u, configRoot)
 	if err != nil {
@@ -62,10 +115,38 @@ func New(ctx context.Context, u, configRoot string) (Datastore, error) {
 			}
 		}
 		return github.New(g, repo, branch, prefix)
+	case "gitlab":
+		project := args[0]
+		pkg := args[1]
+		return gitlab.New(project, pkg)
+	case "gitea":
+		owner := args[0]
+		pkg := args[1]
+		return gitea.New(owner, pkg)
 	case "s3":
 		bucket := args[0]
 		prefix := args[1]
-		sess, err := session.NewSession()
+		endpoint := args[2]
+		region := args[3]
+		forcePathStyle := args[4]
+		insecure := args[5]
+		cfg := aws.NewConfig()
+		if endpoint != "" {
+			// S3-compatible storages ( e.g. MinIO ) are usually addressed in path-style
+			cfg = cfg.WithEndpoint(endpoint).WithS3ForcePathStyle(forcePathStyle != "false")
+		} else if forcePathStyle != "" {
+			cfg = cfg.WithS3ForcePathStyle(forcePathStyle == "true")
+		}
+		if region != "" {
+			cfg = cfg.WithRegion(region)
+		}
+		if insecure == "true" {
+			// for the endpoint with the self-signed certificate
+			tr := http.DefaultTransport.(*http.Transport).Clone()
+			tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			cfg = cfg.WithHTTPClient(&http.Client{Transport: tr})
+		}
+		sess, err := session.NewSession(cfg)
 		if err != nil {
 			return nil, err
 		}
@@ -84,6 +165,14 @@ func New(ctx context.Context, u, configRoot string) (Datastore, error) {
 			return nil, err
 		}
 		return gcs.New(client, bucket, prefix)
+	case "az":
+		container := args[0]
+		prefix := args[1]
+		cc, err := azd.NewContainerClient(container)
+		if err != nil {
+			return nil, err
+		}
+		return azd.New(cc, prefix)
 	case "bq":
 		project := args[0]
 		dataset := args[1]
@@ -98,6 +187,29 @@ func New(ctx context.Context, u, configRoot string) (Datastore, error) {
 			return nil, err
 		}
 		return bq.New(client, dataset, table)
+	case "sheets":
+		spreadsheetID := args[0]
+		rng := args[1]
+		opts := []option.ClientOption{option.WithScopes(sheetsapi.SpreadsheetsScope)}
+		if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON") != "" {
+			opts = append(opts, option.WithCredentialsJSON([]byte(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON"))))
+		}
+		service, err := sheetsapi.NewService(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return sheets.New(service, spreadsheetID, rng)
+	case "mackerel":
+		service := args[0]
+		prefix := args[1]
+		return mackerel.New(service, prefix)
+	case "shields":
+		repo := args[0]
+		endpoint := args[1]
+		return shields.New(repo, endpoint)
+	case "sqlite":
+		path := args[0]
+		return sqlite.New(path)
 	case "local":
 		root := args[0]
 		return local.New(root)
@@ -105,6 +217,26 @@ func New(ctx context.Context, u, configRoot string) (Datastore, error) {
 	return nil, fmt.Errorf("invalid datastore: %s", u)
 }
 
+var urlSchemes = []string{"github", "gitlab", "gitea", "s3", "gs", "az", "bq", "sheets", "mackerel", "shields", "sqlite", "local"}
+
+// IsURL returns true if u is the URL of the datastore with the explicit scheme ( e.g. s3://bucket/reports )
+func IsURL(u string) bool {
+	for _, s := range urlSchemes {
+		if strings.HasPrefix(u, fmt.Sprintf("%s://", s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the URL of the datastore without connecting to the datastore ( e.g. for `octocov validate` )
+func Validate(u, configRoot string) error {
+	if _, _, err := parse(u, configRoot); err != nil {
+		return err
+	}
+	return nil
+}
+
 func parse(u, configRoot string) (datastore string, args []string, err error) {
 	switch {
 	case strings.HasPrefix(u, "github://"):
@@ -123,14 +255,48 @@ func parse(u, configRoot string) (datastore string, args []string, err error) {
 		ownerrepo := fmt.Sprintf("%s/%s", owner, repo)
 		prefix := strings.Join(splitted[2:], "/")
 		return "github", []string{ownerrepo, branch, prefix}, nil
+	case strings.HasPrefix(u, "gitlab://"):
+		project := strings.Trim(strings.TrimPrefix(u, "gitlab://"), "/")
+		pkg := gitlab.DefaultPackage
+		if strings.Contains(project, "@") {
+			splitted := strings.Split(project, "@")
+			project = splitted[0]
+			pkg = splitted[1]
+		}
+		if project == "" || pkg == "" {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		return "gitlab", []string{project, pkg}, nil
+	case strings.HasPrefix(u, "gitea://"):
+		owner := strings.Trim(strings.TrimPrefix(u, "gitea://"), "/")
+		pkg := gitea.DefaultPackage
+		if strings.Contains(owner, "@") {
+			splitted := strings.Split(owner, "@")
+			owner = splitted[0]
+			pkg = splitted[1]
+		}
+		if owner == "" || pkg == "" || strings.Contains(owner, "/") {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		return "gitea", []string{owner, pkg}, nil
 	case strings.HasPrefix(u, "s3://"):
-		splitted := strings.Split(strings.Trim(strings.TrimPrefix(u, "s3://"), "/"), "/")
+		// s3://bucket/prefix?endpoint=https://minio.example.com:9000&region=us-east-1
+		p := strings.TrimPrefix(u, "s3://")
+		q := ""
+		if i := strings.Index(p, "?"); i >= 0 {
+			p, q = p[:i], p[i+1:]
+		}
+		splitted := strings.Split(strings.Trim(p, "/"), "/")
 		if splitted[0] == "" {
 			return "", nil, fmt.Errorf("invalid datastore: %s", u)
 		}
 		bucket := splitted[0]
 		prefix := strings.Join(splitted[1:], "/")
-		return "s3", []string{bucket, prefix}, nil
+		opts, err := parseS3Options(q)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid datastore: %s: %w", u, err)
+		}
+		return "s3", append([]string{bucket, prefix}, opts...), nil
 	case strings.HasPrefix(u, "gs://"):
 		splitted := strings.Split(strings.Trim(strings.TrimPrefix(u, "gs://"), "/"), "/")
 		if splitted[0] == "" {
@@ -142,6 +308,14 @@ func parse(u, configRoot string) (datastore string, args []string, err error) {
 			prefix = strings.Join(splitted[1:], "/")
 		}
 		return "gs", []string{bucket, prefix}, nil
+	case strings.HasPrefix(u, "az://"):
+		splitted := strings.Split(strings.Trim(strings.TrimPrefix(u, "az://"), "/"), "/")
+		if splitted[0] == "" {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		container := splitted[0]
+		prefix := strings.Join(splitted[1:], "/")
+		return "az", []string{container, prefix}, nil
 	case strings.HasPrefix(u, "bq://"):
 		splitted := strings.Split(strings.Trim(strings.TrimPrefix(u, "bq://"), "/"), "/")
 		if len(splitted) != 3 {
@@ -151,6 +325,53 @@ func parse(u, configRoot string) (datastore string, args []string, err error) {
 		dataset := splitted[1]
 		table := splitted[2]
 		return "bq", []string{project, dataset, table}, nil
+	case strings.HasPrefix(u, "sheets://"):
+		// sheets://spreadsheet-id/Sheet1!A:E
+		splitted := strings.SplitN(strings.TrimPrefix(u, "sheets://"), "/", 2)
+		if splitted[0] == "" {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		rng := ""
+		if len(splitted) > 1 {
+			rng = strings.Trim(splitted[1], "/")
+		}
+		return "sheets", []string{splitted[0], rng}, nil
+	case strings.HasPrefix(u, "mackerel://"):
+		// mackerel://service/metric.prefix
+		splitted := strings.SplitN(strings.Trim(strings.TrimPrefix(u, "mackerel://"), "/"), "/", 2)
+		if splitted[0] == "" {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		prefix := ""
+		if len(splitted) > 1 {
+			prefix = strings.Trim(splitted[1], "/")
+			if strings.Contains(prefix, "/") {
+				return "", nil, fmt.Errorf("invalid datastore: %s", u)
+			}
+		}
+		return "mackerel", []string{splitted[0], prefix}, nil
+	case strings.HasPrefix(u, "shields://"):
+		// shields://owner/repo@example.com/path/to/coverage.json
+		splitted := strings.SplitN(strings.TrimPrefix(u, "shields://"), "@", 2)
+		if len(splitted) != 2 || splitted[1] == "" {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		repo := strings.Trim(splitted[0], "/")
+		if _, _, err := gh.SplitRepository(repo); err != nil {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		return "shields", []string{repo, fmt.Sprintf("https://%s", splitted[1])}, nil
+	case strings.HasPrefix(u, "sqlite://"):
+		p := strings.TrimPrefix(u, "sqlite://")
+		if p == "" || strings.HasSuffix(p, "/") {
+			return "", nil, fmt.Errorf("invalid datastore: %s", u)
+		}
+		if !strings.HasPrefix(p, "/") {
+			p = filepath.Join(configRoot, p)
+		}
+		return "sqlite", []string{p}, nil
+	case strings.Contains(u, "://") && !strings.HasPrefix(u, "file://") && !strings.HasPrefix(u, "local://"):
+		return "", nil, fmt.Errorf("invalid datastore: unsupported scheme: %s", u)
 	default:
 		root := configRoot
 		p := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(u, "file://"), "local://"), "/")
@@ -162,3 +383,41 @@ func parse(u, configRoot string) (datastore string, args []string, err error) {
 		return "local", []string{root}, nil
 	}
 }
+
+// parseS3Options parses the query of s3:// datastore into endpoint, region, forcePathStyle and insecure
+func parseS3Options(q string) ([]string, error) {
+	opts := []string{"", "", "", ""}
+	if q == "" {
+		return opts, nil
+	}
+	values, err := url.ParseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range values {
+		if len(v) != 1 {
+			return nil, fmt.Errorf("duplicate option: %s", k)
+		}
+		switch k {
+		case "endpoint":
+			opts[0] = v[0]
+		case "region":
+			opts[1] = v[0]
+		case "forcePathStyle":
+			b, err := strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid option: %s=%s", k, v[0])
+			}
+			opts[2] = strconv.FormatBool(b)
+		case "insecure":
+			b, err := strconv.ParseBool(v[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid option: %s=%s", k, v[0])
+			}
+			opts[3] = strconv.FormatBool(b)
+		default:
+			return nil, fmt.Errorf("unknown option: %s", k)
+		}
+	}
+	return opts, nil
+}
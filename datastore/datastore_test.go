@@ -21,11 +21,27 @@ func TestParse(t *testing.T) {
 		{"github://owner", "", []string{}, true},
 		{"github://owner/repo@branch/reports", "github", []string{"owner/repo", "branch", "reports"}, false},
 		{"github://owner/repo@branch/reports/", "github", []string{"owner/repo", "branch", "reports"}, false},
-		{"s3://bucket/reports", "s3", []string{"bucket", "reports"}, false},
-		{"s3://bucket/path/to/reports", "s3", []string{"bucket", "path/to/reports"}, false},
-		{"s3://bucket", "s3", []string{"bucket", ""}, false},
-		{"s3://bucket/", "s3", []string{"bucket", ""}, false},
+		{"gitlab://group/project", "gitlab", []string{"group/project", "octocov"}, false},
+		{"gitlab://group/subgroup/project@reports", "gitlab", []string{"group/subgroup/project", "reports"}, false},
+		{"gitlab://", "", []string{}, true},
+		{"gitea://owner", "gitea", []string{"owner", "octocov"}, false},
+		{"gitea://owner@reports", "gitea", []string{"owner", "reports"}, false},
+		{"gitea://owner/repo", "", []string{}, true},
+		{"gitea://", "", []string{}, true},
+		{"s3://bucket/reports", "s3", []string{"bucket", "reports", "", "", "", ""}, false},
+		{"s3://bucket/path/to/reports", "s3", []string{"bucket", "path/to/reports", "", "", "", ""}, false},
+		{"s3://bucket", "s3", []string{"bucket", "", "", "", "", ""}, false},
+		{"s3://bucket/", "s3", []string{"bucket", "", "", "", "", ""}, false},
 		{"s3://", "", []string{}, true},
+		{"s3://bucket/reports?endpoint=https://minio.example.com:9000&region=us-east-1", "s3", []string{"bucket", "reports", "https://minio.example.com:9000", "us-east-1", "", ""}, false},
+		{"s3://bucket?endpoint=http://localhost:9000&forcePathStyle=false&insecure=1", "s3", []string{"bucket", "", "http://localhost:9000", "", "false", "true"}, false},
+		{"s3://bucket/reports?endpoint=https://minio.example.com&unknown=1", "", []string{}, true},
+		{"s3://bucket/reports?insecure=maybe", "", []string{}, true},
+		{"s3://?endpoint=https://minio.example.com", "", []string{}, true},
+		{"az://container/reports", "az", []string{"container", "reports"}, false},
+		{"az://container/path/to/reports/", "az", []string{"container", "path/to/reports"}, false},
+		{"az://container", "az", []string{"container", ""}, false},
+		{"az://", "", []string{}, true},
 		{"gs://bucket/reports", "gs", []string{"bucket", "reports"}, false},
 		{"gs://bucket/path/to/reports", "gs", []string{"bucket", "path/to/reports"}, false},
 		{"gs://bucket", "gs", []string{"bucket", ""}, false},
@@ -34,12 +50,28 @@ func TestParse(t *testing.T) {
 		{"bq://project/dataset/table", "bq", []string{"project", "dataset", "table"}, false},
 		{"bq://project/dataset", "", []string{}, true},
 		{"bq://project/dataset/table/more", "", []string{}, true},
+		{"shields://owner/repo@example.com/badges/coverage.json", "shields", []string{"owner/repo", "https://example.com/badges/coverage.json"}, false},
+		{"shields://owner/repo@example.com/badge?repo=owner/repo", "shields", []string{"owner/repo", "https://example.com/badge?repo=owner/repo"}, false},
+		{"shields://owner/repo", "", []string{}, true},
+		{"sheets://1AbCdEf/Sheet1!A:E", "sheets", []string{"1AbCdEf", "Sheet1!A:E"}, false},
+		{"sheets://1AbCdEf", "sheets", []string{"1AbCdEf", ""}, false},
+		{"sheets:///Sheet1", "", []string{}, true},
+		{"mackerel://my-service/octocov.owner-repo", "mackerel", []string{"my-service", "octocov.owner-repo"}, false},
+		{"mackerel://my-service", "mackerel", []string{"my-service", ""}, false},
+		{"mackerel://my-service/a/b", "", []string{}, true},
+		{"mackerel://", "", []string{}, true},
+		{"sqlite://reports.db", "sqlite", []string{filepath.Join(testdataDir(t), "reports.db")}, false},
+		{"sqlite:///var/octocov/reports.db", "sqlite", []string{"/var/octocov/reports.db"}, false},
+		{"sqlite://", "", []string{}, true},
+		{"shields://owner@example.com/coverage.json", "", []string{}, true},
 		{"file://reports", "local", []string{filepath.Join(testdataDir(t), "reports")}, false},
 		{"reports", "local", []string{filepath.Join(testdataDir(t), "reports")}, false},
 		{"file:///reports", "local", []string{"/reports"}, false},
 		{"/reports", "local", []string{"/reports"}, false},
 		{"local://reports", "local", []string{filepath.Join(testdataDir(t), "reports")}, false},
 		{"local:///reports", "local", []string{"/reports"}, false},
+		{"ftp://example.com/reports", "", []string{}, true},
+		{"s4://bucket/reports", "", []string{}, true},
 	}
 	for _, tt := range tests {
 		gotType, gotArgs, err := parse(tt.in, testdataDir(t))
@@ -73,3 +105,45 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestIsURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"s3://bucket/reports", true},
+		{"gs://bucket", true},
+		{"github://owner/repo@main/reports", true},
+		{"local://reports", true},
+		{"sqlite://reports.db", true},
+		{"report.json", false},
+		{"path/to/coverage.out", false},
+		{"https://example.com/report.json", false},
+		{"file://reports", false},
+	}
+	for _, tt := range tests {
+		if got := IsURL(tt.in); got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"s3://bucket/reports", false},
+		{"github://owner/repo@main/reports", false},
+		{"file://reports", false},
+		{"reports", false},
+		{"github://owner", true},
+		{"shields://owner/repo", true},
+		{"s4://bucket/reports", true},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.in, "/root"); (err != nil) != tt.wantErr {
+			t.Errorf("%s: got %v\nwantErr %v", tt.in, err, tt.wantErr)
+		}
+	}
+}
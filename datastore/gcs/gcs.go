@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"net/http"
+	"os"
+	"path"
 	"path/filepath"
+	"time"
 
 	"cloud.google.com/go/storage"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 	"github.com/mauri870/gcsfs"
+	"golang.org/x/oauth2/google"
 )
 
 type GCS struct {
@@ -26,11 +32,23 @@ func New(client *storage.Client, bucket, prefix string) (*GCS, error) {
 }
 
 func (g *GCS) Store(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
-	content := r.String()
+	return g.store(ctx, fmt.Sprintf("%s/report.json", r.Repository), r.Bytes())
+}
+
+// StoreByRef stores the report keyed by the ref of the report
+func (g *GCS) StoreByRef(ctx context.Context, r *report.Report) error {
+	return g.store(ctx, internal.RefReportPath(r.Repository, r.Ref), r.Bytes())
+}
+
+// StorePatchCoverage stores the patch coverage keyed by the ref of the patch coverage
+func (g *GCS) StorePatchCoverage(ctx context.Context, pc *report.PatchCoverage) error {
+	return g.store(ctx, internal.RefPatchCoveragePath(pc.Repository, pc.Ref), pc.Bytes())
+}
+
+func (g *GCS) store(ctx context.Context, path string, content []byte) error {
 	o := filepath.Join(g.prefix, path)
 	w := g.client.Bucket(g.bucket).Object(o).NewWriter(ctx)
-	if _, err := w.Write([]byte(content)); err != nil {
+	if _, err := w.Write(content); err != nil {
 		return err
 	}
 	if err := w.Close(); err != nil {
@@ -39,6 +57,30 @@ func (g *GCS) Store(ctx context.Context, r *report.Report) error {
 	return nil
 }
 
+// SignedURL returns the V4 signed URL of the object at the path relative to the prefix.
+// It is signed with the service account key of the credentials of the client.
+func (g *GCS) SignedURL(ctx context.Context, p string, ttl time.Duration) (string, error) {
+	b := []byte(os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON"))
+	if len(b) == 0 {
+		creds, err := google.FindDefaultCredentials(ctx, storage.ScopeReadOnly)
+		if err != nil {
+			return "", err
+		}
+		b = creds.JSON
+	}
+	conf, err := google.JWTConfigFromJSON(b)
+	if err != nil {
+		return "", fmt.Errorf("signing URLs requires the service account key: %w", err)
+	}
+	return storage.SignedURL(g.bucket, path.Join(g.prefix, p), &storage.SignedURLOptions{
+		GoogleAccessID: conf.Email,
+		PrivateKey:     conf.PrivateKey,
+		Method:         http.MethodGet,
+		Expires:        time.Now().Add(ttl),
+		Scheme:         storage.SigningSchemeV4,
+	})
+}
+
 type GCSFS struct {
 	prefix string
 	gscfs  *gcsfs.FS
@@ -48,6 +90,7 @@ func (fsys *GCSFS) Open(name string) (fs.File, error) {
 	return fsys.gscfs.Open(filepath.Join(fsys.prefix, name))
 }
 
+// FS returns the objects under the prefix. gcsfs lists the objects with the paginated iterator and reads the content of an object when it is opened.
 func (g *GCS) FS() (fs.FS, error) {
 	return &GCSFS{
 		prefix: g.prefix,
@@ -0,0 +1,216 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/report"
+)
+
+const (
+	DefaultPackage  = "octocov"
+	defaultVersion  = "latest"
+	perPage         = 50
+	requestTimeout  = 30 * time.Second
+	maxResponseSize = 100 << 20
+)
+
+// Gitea stores reports to the Gitea generic package registry.
+// Reports are keyed by ref ( package version ) and repository ( package file name ).
+type Gitea struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	owner   string
+	pkg     string
+}
+
+func New(owner, pkg string) (*Gitea, error) {
+	baseURL := os.Getenv("GITEA_URL")
+	if baseURL == "" {
+		return nil, errors.New("env GITEA_URL is not set")
+	}
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		return nil, errors.New("env GITEA_TOKEN is not set")
+	}
+	if pkg == "" {
+		pkg = DefaultPackage
+	}
+	return &Gitea{
+		client:  &http.Client{Timeout: requestTimeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		owner:   owner,
+		pkg:     pkg,
+	}, nil
+}
+
+func (g *Gitea) Store(ctx context.Context, r *report.Report) error {
+	v := internal.RefKey(r.Ref)
+	if v == "" {
+		v = defaultVersion
+	}
+	u := g.genericURL(v, internal.RepositoryFileName(r.Repository))
+	// Gitea does not overwrite an existing package file
+	if _, err := g.do(ctx, http.MethodDelete, u, nil); err != nil && !errors.Is(err, errNotFound) {
+		return err
+	}
+	_, err := g.do(ctx, http.MethodPut, u, r.Bytes())
+	return err
+}
+
+// StoreByRef stores the report. Reports are always keyed by ref ( package version )
+func (g *Gitea) StoreByRef(ctx context.Context, r *report.Report) error {
+	return g.Store(ctx, r)
+}
+
+type gtPackage struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type gtPackageFile struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (g *Gitea) FS() (fs.FS, error) {
+	ctx := context.Background()
+	fsys := fstest.MapFS{}
+	latest := map[string]*report.Report{}
+	pkgs, err := g.listPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pkgs {
+		files, err := g.listPackageFiles(ctx, p.Version)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if !strings.HasSuffix(f.Name, ".json") {
+				continue
+			}
+			b, err := g.do(ctx, http.MethodGet, g.genericURL(p.Version, f.Name), nil)
+			if err != nil {
+				return nil, err
+			}
+			r := &report.Report{}
+			if err := json.Unmarshal(b, r); err != nil {
+				continue
+			}
+			if r.Repository == "" {
+				continue
+			}
+			fsys[internal.RefReportPath(r.Repository, r.Ref)] = &fstest.MapFile{
+				Data:    b,
+				Mode:    fs.ModePerm,
+				ModTime: r.Timestamp,
+			}
+			l, ok := latest[r.Repository]
+			if ok && !r.Timestamp.After(l.Timestamp) {
+				continue
+			}
+			latest[r.Repository] = r
+			fsys[fmt.Sprintf("%s/report.json", r.Repository)] = &fstest.MapFile{
+				Data:    b,
+				Mode:    fs.ModePerm,
+				ModTime: r.Timestamp,
+			}
+		}
+	}
+	return &fsys, nil
+}
+
+func (g *Gitea) listPackages(ctx context.Context) ([]gtPackage, error) {
+	pkgs := []gtPackage{}
+	page := 1
+	for {
+		q := url.Values{}
+		q.Set("type", "generic")
+		q.Set("q", g.pkg)
+		q.Set("limit", fmt.Sprintf("%d", perPage))
+		q.Set("page", fmt.Sprintf("%d", page))
+		b, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/packages/%s?%s", g.baseURL, url.PathEscape(g.owner), q.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		l := []gtPackage{}
+		if err := json.Unmarshal(b, &l); err != nil {
+			return nil, err
+		}
+		for _, p := range l {
+			// q is a fuzzy search
+			if p.Name == g.pkg {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(l) < perPage {
+			break
+		}
+		page += 1
+	}
+	return pkgs, nil
+}
+
+func (g *Gitea) listPackageFiles(ctx context.Context, version string) ([]gtPackageFile, error) {
+	b, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/packages/%s/generic/%s/%s/files", g.baseURL, url.PathEscape(g.owner), url.PathEscape(g.pkg), url.PathEscape(version)), nil)
+	if err != nil {
+		return nil, err
+	}
+	files := []gtPackageFile{}
+	if err := json.Unmarshal(b, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (g *Gitea) genericURL(version, fileName string) string {
+	return fmt.Sprintf("%s/api/packages/%s/generic/%s/%s/%s", g.baseURL, url.PathEscape(g.owner), url.PathEscape(g.pkg), url.PathEscape(version), url.PathEscape(fileName))
+}
+
+var errNotFound = errors.New("not found")
+
+func (g *Gitea) do(ctx context.Context, method, u string, body []byte) ([]byte, error) {
+	var br io.Reader
+	if body != nil {
+		br = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, br)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", g.token))
+	res, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to request Gitea API (%s %s): %w", method, req.URL.Path, errNotFound)
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("failed to request Gitea API (%s %s): %w", method, req.URL.Path, &internal.StatusError{StatusCode: res.StatusCode, Status: res.Status, Body: string(b)})
+	}
+	return b, nil
+}
@@ -0,0 +1,181 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/internal/testutil"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStoreAndFS(t *testing.T) {
+	ts := newTestServer(t)
+	defer ts.Close()
+	testutil.SetEnv(t, "GITEA_URL", ts.URL)
+	testutil.SetEnv(t, "GITEA_TOKEN", "xxx")
+	g, err := New("owner", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	reports := []*report.Report{
+		{Repository: "owner/repo", Ref: "refs/heads/main", Timestamp: now.Add(-2 * time.Hour), Coverage: &coverage.Coverage{Total: 10, Covered: 5}},
+		{Repository: "owner/repo", Ref: "refs/tags/v1.0.0", Timestamp: now.Add(-1 * time.Hour), Coverage: &coverage.Coverage{Total: 10, Covered: 6}},
+		{Repository: "owner/other", Ref: "refs/heads/main", Timestamp: now, Coverage: &coverage.Coverage{Total: 10, Covered: 7}},
+		// Gitea does not overwrite the existing file, so the file is deleted before storing
+		{Repository: "owner/repo", Ref: "refs/heads/main", Timestamp: now, Coverage: &coverage.Coverage{Total: 10, Covered: 8}},
+	}
+	for _, r := range reports {
+		if err := g.StoreByRef(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := 1; ts.deleted != want {
+		t.Errorf("got %v\nwant %v", ts.deleted, want)
+	}
+
+	fsys, err := g.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path        string
+		wantCovered int
+	}{
+		{"owner/repo/report.json", 8},
+		{"owner/repo/refs/main/report.json", 8},
+		{"owner/repo/refs/v1.0.0/report.json", 6},
+		{"owner/other/report.json", 7},
+		{"owner/other/refs/main/report.json", 7},
+	}
+	for _, tt := range tests {
+		b, err := fs.ReadFile(fsys, tt.path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		r := &report.Report{}
+		if err := json.Unmarshal(b, r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Coverage.Covered != tt.wantCovered {
+			t.Errorf("%s: got %v\nwant %v", tt.path, r.Coverage.Covered, tt.wantCovered)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		url     string
+		token   string
+		wantErr bool
+	}{
+		{"https://gitea.example.com", "xxx", false},
+		{"", "xxx", true},
+		{"https://gitea.example.com", "", true},
+	}
+	for _, tt := range tests {
+		testutil.SetEnv(t, "GITEA_URL", tt.url)
+		testutil.SetEnv(t, "GITEA_TOKEN", tt.token)
+		_, err := New("owner", "")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+type testServer struct {
+	*httptest.Server
+	files   *testutil.PackageFiles
+	mu      sync.Mutex
+	deleted int
+}
+
+// newTestServer returns the fake Gitea generic package registry of the owner "owner"
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	s := &testServer{files: testutil.NewPackageFiles()}
+	genericPrefix := "/api/packages/owner/generic/"
+	filesPrefix := "/api/v1/packages/owner/generic/"
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token xxx" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case strings.HasPrefix(r.URL.Path, genericPrefix):
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, genericPrefix), "/")
+			if len(parts) != 3 || parts[0] != DefaultPackage {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			version, name := parts[1], parts[2]
+			b, exist := s.files.Get(version, name)
+			switch r.Method {
+			case http.MethodPut:
+				if exist {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				s.files.Put(version, name, b)
+				w.WriteHeader(http.StatusCreated)
+			case http.MethodDelete:
+				if !s.files.Delete(version, name) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				s.mu.Lock()
+				s.deleted++
+				s.mu.Unlock()
+				w.WriteHeader(http.StatusNoContent)
+			case http.MethodGet:
+				if !exist {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(b)
+			default:
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, filesPrefix) && strings.HasSuffix(r.URL.Path, "/files"):
+			version := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, filesPrefix+DefaultPackage+"/"), "/files")
+			files := []gtPackageFile{}
+			for i, n := range s.files.Names(version) {
+				files = append(files, gtPackageFile{ID: i + 1, Name: n})
+			}
+			b, _ := json.Marshal(files)
+			_, _ = w.Write(b)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/packages/owner":
+			if r.URL.Query().Get("type") != "generic" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			versions := s.files.Versions()
+			// q is a fuzzy search
+			pkgs := []gtPackage{{ID: 100, Name: "octocov-ignored", Version: "main"}}
+			for i, v := range versions {
+				pkgs = append(pkgs, gtPackage{ID: i + 1, Name: DefaultPackage, Version: v})
+			}
+			b, _ := json.Marshal(pkgs)
+			_, _ = w.Write(b)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
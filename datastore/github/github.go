@@ -2,12 +2,13 @@ package github
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"testing/fstest"
 
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 )
 
@@ -28,10 +29,21 @@ func New(gh *gh.Gh, r, b, prefix string) (*Github, error) {
 }
 
 func (g *Github) Store(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
+	return g.store(ctx, fmt.Sprintf("%s/report.json", r.Repository), r.String(), fmt.Sprintf("Store coverage report of %s", r.Repository))
+}
+
+// StoreByRef stores the report keyed by the ref of the report
+func (g *Github) StoreByRef(ctx context.Context, r *report.Report) error {
+	return g.store(ctx, internal.RefReportPath(r.Repository, r.Ref), r.String(), fmt.Sprintf("Store coverage report of %s", r.Repository))
+}
+
+// StorePatchCoverage stores the patch coverage keyed by the ref of the patch coverage
+func (g *Github) StorePatchCoverage(ctx context.Context, pc *report.PatchCoverage) error {
+	return g.store(ctx, internal.RefPatchCoveragePath(pc.Repository, pc.Ref), string(pc.Bytes()), fmt.Sprintf("Store patch coverage of %s", pc.Repository))
+}
+
+func (g *Github) store(ctx context.Context, path, content, message string) error {
 	branch := g.branch
-	content := r.String()
-	message := fmt.Sprintf("Store coverage report of %s", r.Repository)
 	owner, repo, err := gh.SplitRepository(g.repository)
 	if err != nil {
 		return err
@@ -40,6 +52,27 @@ func (g *Github) Store(ctx context.Context, r *report.Report) error {
 	return g.gh.PushContent(ctx, owner, repo, branch, content, cp, message)
 }
 
+// FS lists the blobs under the prefix of the branch and downloads the content of a blob when it is opened
 func (g *Github) FS() (fs.FS, error) {
-	return nil, errors.New("not implemented")
+	ctx := context.Background()
+	owner, repo, err := gh.SplitRepository(g.repository)
+	if err != nil {
+		return nil, err
+	}
+	blobs, mtime, err := g.gh.GetTreeBlobs(ctx, owner, repo, g.branch, g.prefix)
+	if err != nil {
+		return nil, err
+	}
+	index := fstest.MapFS{}
+	shas := map[string]string{}
+	for _, b := range blobs {
+		index[b.Path] = &fstest.MapFile{
+			Mode:    fs.ModePerm,
+			ModTime: mtime,
+		}
+		shas[b.Path] = b.SHA
+	}
+	return internal.NewLazyFS(index, func(ctx context.Context, name string) ([]byte, error) {
+		return g.gh.GetBlob(ctx, owner, repo, shas[name])
+	}), nil
 }
@@ -0,0 +1,160 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal/testutil"
+)
+
+func TestFS(t *testing.T) {
+	tests := []struct {
+		truncated bool
+		prefix    string
+		want      []string
+	}{
+		{false, "reports", []string{"owner/repo/README.md", "owner/repo/report.json"}},
+		{true, "reports", []string{"owner/repo/README.md", "owner/repo/report.json"}},
+		{false, "", []string{"README.md", "reports/owner/repo/README.md", "reports/owner/repo/report.json"}},
+		{false, "missing", []string{}},
+	}
+	for _, tt := range tests {
+		ts := newTestServer(t, tt.truncated)
+		g, err := New(newTestGh(t, ts.URL), "owner/central", "main", tt.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys, err := g.FS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				got = append(got, path)
+			}
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(got)
+		if fmt.Sprintf("%v", got) != fmt.Sprintf("%v", tt.want) {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		for _, p := range got {
+			if !strings.HasSuffix(p, "report.json") {
+				continue
+			}
+			f, err := fsys.Open(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := `{"repository":"owner/repo"}`; string(b) != want {
+				t.Errorf("got %v\nwant %v", string(b), want)
+			}
+			fi, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC); !fi.ModTime().Equal(want) {
+				t.Errorf("got %v\nwant %v", fi.ModTime(), want)
+			}
+		}
+		ts.Close()
+	}
+}
+
+type testTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+}
+
+// newTestServer serves the tree of / ( README.md, reports/owner/repo/report.json and reports/owner/repo/README.md )
+func newTestServer(t *testing.T, truncated bool) *httptest.Server {
+	t.Helper()
+	trees := map[string][]testTreeEntry{
+		"tree-root":    {{"README.md", "blob", "blob-readme"}, {"reports", "tree", "tree-reports"}},
+		"tree-reports": {{"owner", "tree", "tree-owner"}},
+		"tree-owner":   {{"repo", "tree", "tree-repo"}},
+		"tree-repo":    {{"README.md", "blob", "blob-readme"}, {"report.json", "blob", "blob-report"}},
+	}
+	blobs := map[string]string{
+		"blob-readme": "# README",
+		"blob-report": `{"repository":"owner/repo"}`,
+	}
+	var recursive func(sha, base string) []testTreeEntry
+	recursive = func(sha, base string) []testTreeEntry {
+		entries := []testTreeEntry{}
+		for _, e := range trees[sha] {
+			p := strings.TrimPrefix(base+"/"+e.Path, "/")
+			entries = append(entries, testTreeEntry{p, e.Type, e.SHA})
+			if e.Type == "tree" {
+				entries = append(entries, recursive(e.SHA, p)...)
+			}
+		}
+		return entries
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		p := strings.TrimPrefix(r.URL.Path, "/repos/owner/central/git/")
+		switch {
+		case p == "ref/heads/main":
+			_, _ = fmt.Fprint(w, `{"ref":"refs/heads/main","object":{"sha":"commit-main","type":"commit"}}`)
+		case p == "commits/commit-main":
+			_, _ = fmt.Fprint(w, `{"sha":"commit-main","tree":{"sha":"tree-root"},"committer":{"date":"2021-01-02T03:04:05Z"}}`)
+		case strings.HasPrefix(p, "trees/"):
+			sha := strings.TrimPrefix(p, "trees/")
+			entries, ok := trees[sha]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			tr := false
+			if r.URL.Query().Get("recursive") != "" {
+				if truncated {
+					tr = true
+				} else {
+					entries = recursive(sha, "")
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"sha": sha, "tree": entries, "truncated": tr})
+		case strings.HasPrefix(p, "blobs/"):
+			b, ok := blobs[strings.TrimPrefix(p, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = fmt.Fprint(w, b)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestGh(t *testing.T, u string) *gh.Gh {
+	t.Helper()
+	testutil.SetEnv(t, "GITHUB_TOKEN", "xxx")
+	testutil.SetEnv(t, "GITHUB_API_URL", u)
+	g, err := gh.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
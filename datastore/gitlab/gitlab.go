@@ -0,0 +1,228 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/report"
+)
+
+const (
+	defaultURL      = "https://gitlab.com"
+	DefaultPackage  = "octocov"
+	defaultVersion  = "latest"
+	perPage         = 100
+	requestTimeout  = 30 * time.Second
+	maxResponseSize = 100 << 20
+)
+
+// Gitlab stores reports to the GitLab generic package registry.
+// Reports are keyed by project ( package version = ref ) and repository ( package file name ).
+type Gitlab struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	project string
+	pkg     string
+}
+
+func New(project, pkg string) (*Gitlab, error) {
+	baseURL := os.Getenv("GITLAB_URL")
+	if baseURL == "" {
+		baseURL = os.Getenv("CI_SERVER_URL")
+	}
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" && os.Getenv("CI_JOB_TOKEN") == "" {
+		return nil, errors.New("env GITLAB_TOKEN is not set")
+	}
+	if pkg == "" {
+		pkg = DefaultPackage
+	}
+	return &Gitlab{
+		client:  &http.Client{Timeout: requestTimeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		project: project,
+		pkg:     pkg,
+	}, nil
+}
+
+func (g *Gitlab) Store(ctx context.Context, r *report.Report) error {
+	v := internal.RefKey(r.Ref)
+	if v == "" {
+		v = defaultVersion
+	}
+	u := g.genericURL(v, internal.RepositoryFileName(r.Repository))
+	_, err := g.do(ctx, http.MethodPut, u, r.Bytes())
+	return err
+}
+
+// StoreByRef stores the report. Reports are always keyed by ref ( package version )
+func (g *Gitlab) StoreByRef(ctx context.Context, r *report.Report) error {
+	return g.Store(ctx, r)
+}
+
+type glPackage struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type glPackageFile struct {
+	ID       int    `json:"id"`
+	FileName string `json:"file_name"`
+}
+
+func (g *Gitlab) FS() (fs.FS, error) {
+	ctx := context.Background()
+	fsys := fstest.MapFS{}
+	latest := map[string]*report.Report{}
+	pkgs, err := g.listPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pkgs {
+		files, err := g.listPackageFiles(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if !strings.HasSuffix(f.FileName, ".json") {
+				continue
+			}
+			b, err := g.do(ctx, http.MethodGet, g.genericURL(p.Version, f.FileName), nil)
+			if err != nil {
+				return nil, err
+			}
+			r := &report.Report{}
+			if err := json.Unmarshal(b, r); err != nil {
+				continue
+			}
+			if r.Repository == "" {
+				continue
+			}
+			fsys[internal.RefReportPath(r.Repository, r.Ref)] = &fstest.MapFile{
+				Data:    b,
+				Mode:    fs.ModePerm,
+				ModTime: r.Timestamp,
+			}
+			l, ok := latest[r.Repository]
+			if ok && !r.Timestamp.After(l.Timestamp) {
+				continue
+			}
+			latest[r.Repository] = r
+			fsys[fmt.Sprintf("%s/report.json", r.Repository)] = &fstest.MapFile{
+				Data:    b,
+				Mode:    fs.ModePerm,
+				ModTime: r.Timestamp,
+			}
+		}
+	}
+	return &fsys, nil
+}
+
+func (g *Gitlab) listPackages(ctx context.Context) ([]glPackage, error) {
+	pkgs := []glPackage{}
+	page := 1
+	for {
+		q := url.Values{}
+		q.Set("package_type", "generic")
+		q.Set("package_name", g.pkg)
+		q.Set("per_page", fmt.Sprintf("%d", perPage))
+		q.Set("page", fmt.Sprintf("%d", page))
+		b, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/packages?%s", g.projectURL(), q.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		l := []glPackage{}
+		if err := json.Unmarshal(b, &l); err != nil {
+			return nil, err
+		}
+		for _, p := range l {
+			// package_name is a fuzzy search
+			if p.Name == g.pkg {
+				pkgs = append(pkgs, p)
+			}
+		}
+		if len(l) < perPage {
+			break
+		}
+		page += 1
+	}
+	return pkgs, nil
+}
+
+func (g *Gitlab) listPackageFiles(ctx context.Context, id int) ([]glPackageFile, error) {
+	files := []glPackageFile{}
+	page := 1
+	for {
+		b, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/packages/%d/package_files?per_page=%d&page=%d", g.projectURL(), id, perPage, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		l := []glPackageFile{}
+		if err := json.Unmarshal(b, &l); err != nil {
+			return nil, err
+		}
+		files = append(files, l...)
+		if len(l) < perPage {
+			break
+		}
+		page += 1
+	}
+	return files, nil
+}
+
+func (g *Gitlab) projectURL() string {
+	return fmt.Sprintf("%s/api/v4/projects/%s", g.baseURL, url.PathEscape(g.project))
+}
+
+func (g *Gitlab) genericURL(version, fileName string) string {
+	return fmt.Sprintf("%s/packages/generic/%s/%s/%s", g.projectURL(), url.PathEscape(g.pkg), url.PathEscape(version), url.PathEscape(fileName))
+}
+
+func (g *Gitlab) do(ctx context.Context, method, u string, body []byte) ([]byte, error) {
+	var br io.Reader
+	if body != nil {
+		br = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, br)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	} else {
+		req.Header.Set("JOB-TOKEN", os.Getenv("CI_JOB_TOKEN"))
+	}
+	res, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("failed to request GitLab API (%s %s): %w", method, req.URL.Path, &internal.StatusError{StatusCode: res.StatusCode, Status: res.Status, Body: string(b)})
+	}
+	return b, nil
+}
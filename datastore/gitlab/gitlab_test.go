@@ -0,0 +1,180 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/internal/testutil"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStoreAndFS(t *testing.T) {
+	ts := newTestServer(t)
+	defer ts.Close()
+	testutil.SetEnv(t, "GITLAB_URL", ts.URL)
+	testutil.SetEnv(t, "GITLAB_TOKEN", "xxx")
+	g, err := New("123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	reports := []*report.Report{
+		{Repository: "owner/repo", Ref: "refs/heads/main", Timestamp: now.Add(-2 * time.Hour), Coverage: &coverage.Coverage{Total: 10, Covered: 5}},
+		{Repository: "owner/repo", Ref: "refs/tags/v1.0.0", Timestamp: now.Add(-1 * time.Hour), Coverage: &coverage.Coverage{Total: 10, Covered: 6}},
+		{Repository: "owner/other", Ref: "refs/heads/main", Timestamp: now, Coverage: &coverage.Coverage{Total: 10, Covered: 7}},
+		// overwrite the report of the same ref
+		{Repository: "owner/repo", Ref: "refs/heads/main", Timestamp: now, Coverage: &coverage.Coverage{Total: 10, Covered: 8}},
+	}
+	for _, r := range reports {
+		if err := g.StoreByRef(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := ts.files.Get("main", "owner_repo.json"); !ok {
+		t.Errorf("got %v\nwant %v", ts.files.Names("main"), "main/owner_repo.json")
+	}
+
+	fsys, err := g.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path        string
+		wantCovered int
+	}{
+		{"owner/repo/report.json", 8},
+		{"owner/repo/refs/main/report.json", 8},
+		{"owner/repo/refs/v1.0.0/report.json", 6},
+		{"owner/other/report.json", 7},
+		{"owner/other/refs/main/report.json", 7},
+	}
+	for _, tt := range tests {
+		b, err := fs.ReadFile(fsys, tt.path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		r := &report.Report{}
+		if err := json.Unmarshal(b, r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Coverage.Covered != tt.wantCovered {
+			t.Errorf("%s: got %v\nwant %v", tt.path, r.Coverage.Covered, tt.wantCovered)
+		}
+	}
+	// the package with the similar name is not read
+	if _, err := fs.ReadFile(fsys, "owner/ignored/report.json"); err == nil {
+		t.Error("the report in the other package should not be read")
+	}
+}
+
+func TestStoreError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+	testutil.SetEnv(t, "GITLAB_URL", ts.URL)
+	testutil.SetEnv(t, "GITLAB_TOKEN", "xxx")
+	g, err := New("123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Store(context.Background(), &report.Report{Repository: "owner/repo", Ref: "refs/heads/main"}); err == nil {
+		t.Error("should be error")
+	}
+	if _, err := g.FS(); err == nil {
+		t.Error("should be error")
+	}
+}
+
+type testServer struct {
+	*httptest.Server
+	files *testutil.PackageFiles
+}
+
+// newTestServer returns the fake GitLab generic package registry of the project 123 ( the package "octocov" and the ignored package "octocov-ignored" )
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	s := &testServer{files: testutil.NewPackageFiles()}
+	ignored := []byte(`{"repository":"owner/ignored","ref":"refs/heads/main"}`)
+	genericPrefix := "/api/v4/projects/123/packages/generic/"
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "xxx" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		versions := s.files.Versions()
+		switch {
+		case strings.HasPrefix(r.URL.Path, genericPrefix):
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, genericPrefix), "/")
+			if len(parts) != 3 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			pkg, version, name := parts[0], parts[1], parts[2]
+			switch {
+			case r.Method == http.MethodPut && pkg == DefaultPackage:
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				s.files.Put(version, name, b)
+				w.WriteHeader(http.StatusCreated)
+			case r.Method == http.MethodGet && pkg == DefaultPackage:
+				b, ok := s.files.Get(version, name)
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(b)
+			case r.Method == http.MethodGet && pkg == "octocov-ignored":
+				_, _ = w.Write(ignored)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/123/packages":
+			if r.URL.Query().Get("package_type") != "generic" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			pkgs := []glPackage{{ID: 0, Name: "octocov-ignored", Version: "main"}}
+			for i, v := range versions {
+				pkgs = append(pkgs, glPackage{ID: i + 1, Name: DefaultPackage, Version: v})
+			}
+			b, _ := json.Marshal(pkgs)
+			_, _ = w.Write(b)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/package_files"):
+			id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v4/projects/123/packages/"), "/package_files"))
+			if err != nil || id > len(versions) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			files := []glPackageFile{}
+			if id == 0 {
+				files = append(files, glPackageFile{ID: 1, FileName: "owner_ignored.json"})
+			} else {
+				for i, n := range s.files.Names(versions[id-1]) {
+					files = append(files, glPackageFile{ID: i + 1, FileName: n})
+				}
+			}
+			b, _ := json.Marshal(files)
+			_, _ = w.Write(b)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"message":"%s not found"}`, r.URL.Path)))
+		}
+	}))
+	return s
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 	"github.com/k1LoW/osfs"
 )
@@ -34,6 +35,24 @@ func (l *Local) Store(ctx context.Context, r *report.Report) error {
 	return os.WriteFile(filepath.Join(l.root, path), r.Bytes(), os.ModePerm)
 }
 
+// StoreByRef stores the report keyed by the ref of the report
+func (l *Local) StoreByRef(ctx context.Context, r *report.Report) error {
+	return l.storeByRef(internal.RefReportPath(r.Repository, r.Ref), r.Bytes())
+}
+
+// StorePatchCoverage stores the patch coverage keyed by the ref of the patch coverage
+func (l *Local) StorePatchCoverage(ctx context.Context, pc *report.PatchCoverage) error {
+	return l.storeByRef(internal.RefPatchCoveragePath(pc.Repository, pc.Ref), pc.Bytes())
+}
+
+func (l *Local) storeByRef(path string, content []byte) error {
+	p := filepath.Join(l.root, path)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil { // #nosec
+		return err
+	}
+	return os.WriteFile(p, content, os.ModePerm)
+}
+
 func (l *Local) FS() (fs.FS, error) {
 	return osfs.New().Sub(strings.TrimPrefix(l.root, "/"))
 }
@@ -0,0 +1,128 @@
+package mackerel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/report"
+)
+
+const (
+	DefaultMetricPrefix = "octocov"
+	defaultAPIBase      = "https://api.mackerelio.com"
+	requestTimeout      = 30 * time.Second
+	maxResponseSize     = 1 << 20
+)
+
+var invalidMetricNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Mackerel is a write-only datastore that posts the metrics of the report as the service metrics of Mackerel
+type Mackerel struct {
+	client  *http.Client
+	apiBase string
+	apiKey  string
+	service string
+	prefix  string
+}
+
+func New(service, prefix string) (*Mackerel, error) {
+	apiKey := os.Getenv("MACKEREL_APIKEY")
+	if apiKey == "" {
+		return nil, errors.New("env MACKEREL_APIKEY is not set")
+	}
+	apiBase := os.Getenv("MACKEREL_APIBASE")
+	if apiBase == "" {
+		apiBase = defaultAPIBase
+	}
+	if service == "" {
+		return nil, errors.New("service name is not set")
+	}
+	if prefix == "" {
+		prefix = DefaultMetricPrefix
+	}
+	if invalidMetricNameRe.MatchString(prefix) {
+		return nil, fmt.Errorf("invalid metric prefix: %s", prefix)
+	}
+	return &Mackerel{
+		client:  &http.Client{Timeout: requestTimeout},
+		apiBase: strings.TrimSuffix(apiBase, "/"),
+		apiKey:  apiKey,
+		service: service,
+		prefix:  prefix,
+	}, nil
+}
+
+type metricValue struct {
+	Name  string  `json:"name"`
+	Time  int64   `json:"time,omitempty"`
+	Value float64 `json:"value"`
+}
+
+func (m *Mackerel) Store(ctx context.Context, r *report.Report) error {
+	metrics := m.metrics(r)
+	if len(metrics) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/api/v0/services/%s/tsdb", m.apiBase, url.PathEscape(m.service))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("failed to post service metrics to Mackerel (%s): %w", m.service, &internal.StatusError{StatusCode: res.StatusCode, Status: res.Status, Body: string(body)})
+	}
+	return nil
+}
+
+func (m *Mackerel) FS() (fs.FS, error) {
+	return nil, errors.New("mackerel:// datastore is write-only")
+}
+
+// metrics returns the measured metrics of the report ( coverage [%], code to test ratio and test execution time [sec] ) at the timestamp of the report
+func (m *Mackerel) metrics(r *report.Report) []metricValue {
+	var t int64
+	if !r.Timestamp.IsZero() {
+		// the zero time is a negative epoch, so the time is omitted
+		t = r.Timestamp.Unix()
+	}
+	metrics := []metricValue{}
+	if r.IsMeasuredCoverage() {
+		metrics = append(metrics, metricValue{Name: fmt.Sprintf("%s.coverage", m.prefix), Time: t, Value: r.CoveragePercent()})
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		metrics = append(metrics, metricValue{Name: fmt.Sprintf("%s.code_to_test_ratio", m.prefix), Time: t, Value: r.CodeToTestRatioRatio()})
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		metrics = append(metrics, metricValue{Name: fmt.Sprintf("%s.test_execution_time", m.prefix), Time: t, Value: time.Duration(*r.TestExecutionTime).Seconds()})
+	}
+	return metrics
+}
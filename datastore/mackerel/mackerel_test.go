@@ -0,0 +1,133 @@
+package mackerel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/internal/testutil"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/ratio"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStore(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	d := float64(90 * time.Second)
+	tests := []struct {
+		r    *report.Report
+		want []metricValue
+	}{
+		{
+			&report.Report{
+				Repository:        "owner/repo",
+				Timestamp:         ts,
+				Coverage:          &coverage.Coverage{Total: 200, Covered: 150},
+				CodeToTestRatio:   &ratio.Ratio{Code: 100, Test: 50},
+				TestExecutionTime: &d,
+			},
+			[]metricValue{
+				{Name: "octocov.owner-repo.coverage", Time: ts.Unix(), Value: 75},
+				{Name: "octocov.owner-repo.code_to_test_ratio", Time: ts.Unix(), Value: 0.5},
+				{Name: "octocov.owner-repo.test_execution_time", Time: ts.Unix(), Value: 90},
+			},
+		},
+		{
+			&report.Report{
+				Repository: "owner/repo",
+				Timestamp:  ts,
+				Coverage:   &coverage.Coverage{Total: 4, Covered: 1},
+			},
+			[]metricValue{
+				{Name: "octocov.owner-repo.coverage", Time: ts.Unix(), Value: 25},
+			},
+		},
+		{
+			&report.Report{
+				Repository: "owner/repo",
+				Coverage:   &coverage.Coverage{Total: 4, Covered: 1},
+			},
+			[]metricValue{
+				{Name: "octocov.owner-repo.coverage", Value: 25},
+			},
+		},
+		{
+			&report.Report{
+				Repository: "owner/repo",
+				Timestamp:  ts,
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		var got []metricValue
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/v0/services/my-service/tsdb" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Header.Get("X-Api-Key") != "xxx" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if err := json.Unmarshal(b, &got); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"success":true}`))
+		}))
+		testutil.SetEnv(t, "MACKEREL_APIKEY", "xxx")
+		testutil.SetEnv(t, "MACKEREL_APIBASE", s.URL)
+		m, err := New("my-service", "octocov.owner-repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Store(context.Background(), tt.r); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+		s.Close()
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		apiKey  string
+		service string
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{"xxx", "my-service", "", DefaultMetricPrefix, false},
+		{"xxx", "my-service", "octocov.owner-repo", "octocov.owner-repo", false},
+		{"xxx", "my-service", "owner/repo", "", true},
+		{"xxx", "", "", "", true},
+		{"", "my-service", "", "", true},
+	}
+	for _, tt := range tests {
+		testutil.SetEnv(t, "MACKEREL_APIKEY", tt.apiKey)
+		m, err := New(tt.service, tt.prefix)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if m.prefix != tt.want {
+			t.Errorf("got %v\nwant %v", m.prefix, tt.want)
+		}
+	}
+}
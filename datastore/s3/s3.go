@@ -5,12 +5,15 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/jszwec/s3fs"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 )
 
@@ -29,13 +32,25 @@ func New(client s3iface.S3API, bucket, prefix string) (*S3, error) {
 }
 
 func (s *S3) Store(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
-	content := r.String()
+	return s.store(ctx, fmt.Sprintf("%s/report.json", r.Repository), r.Bytes())
+}
+
+// StoreByRef stores the report keyed by the ref of the report
+func (s *S3) StoreByRef(ctx context.Context, r *report.Report) error {
+	return s.store(ctx, internal.RefReportPath(r.Repository, r.Ref), r.Bytes())
+}
+
+// StorePatchCoverage stores the patch coverage keyed by the ref of the patch coverage
+func (s *S3) StorePatchCoverage(ctx context.Context, pc *report.PatchCoverage) error {
+	return s.store(ctx, internal.RefPatchCoveragePath(pc.Repository, pc.Ref), pc.Bytes())
+}
+
+func (s *S3) store(ctx context.Context, path string, content []byte) error {
 	key := filepath.Join(s.prefix, path)
 	_, err := s.client.PutObject(&s3.PutObjectInput{
 		Bucket:        &s.bucket,
 		Key:           &key,
-		Body:          bytes.NewReader([]byte(content)),
+		Body:          bytes.NewReader(content),
 		ContentLength: aws.Int64(int64(len(content))),
 	})
 	if err != nil {
@@ -44,6 +59,19 @@ func (s *S3) Store(ctx context.Context, r *report.Report) error {
 	return nil
 }
 
+// SignedURL returns the presigned URL of the object at the path relative to the prefix
+func (s *S3) SignedURL(ctx context.Context, p string, ttl time.Duration) (string, error) {
+	// the key of the object is always slash-separated
+	key := path.Join(s.prefix, p)
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	})
+	req.SetContext(ctx)
+	return req.Presign(ttl)
+}
+
+// FS returns the objects under the prefix. s3fs lists the objects page by page ( by the marker ) and reads the content of an object when it is opened.
 func (s *S3) FS() (fs.FS, error) {
 	return fs.Sub(s3fs.New(s.client, s.bucket), s.prefix)
 }
@@ -0,0 +1,94 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+	"github.com/lestrrat-go/backoff/v2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+const DefaultRange = "A1"
+
+// Sheets is a write-only datastore that appends a row of the report to Google Sheets
+type Sheets struct {
+	service       *sheets.Service
+	spreadsheetID string
+	rng           string
+}
+
+func New(service *sheets.Service, spreadsheetID, rng string) (*Sheets, error) {
+	if spreadsheetID == "" {
+		return nil, errors.New("spreadsheet id is not set")
+	}
+	if rng == "" {
+		rng = DefaultRange
+	}
+	return &Sheets{
+		service:       service,
+		spreadsheetID: spreadsheetID,
+		rng:           rng,
+	}, nil
+}
+
+func (s *Sheets) Store(ctx context.Context, r *report.Report) error {
+	vr := &sheets.ValueRange{
+		Values: [][]interface{}{Row(r)},
+	}
+	// Sheets API has per-minute quotas
+	p := backoff.Exponential(
+		backoff.WithMinInterval(time.Second),
+		backoff.WithMaxInterval(30*time.Second),
+		backoff.WithJitterFactor(0.05),
+		backoff.WithMaxRetries(5),
+	)
+	b := p.Start(ctx)
+	var err error
+	for backoff.Continue(b) {
+		_, err = s.service.Spreadsheets.Values.Append(s.spreadsheetID, s.rng, vr).ValueInputOption("USER_ENTERED").InsertDataOption("INSERT_ROWS").Context(ctx).Do()
+		if err == nil || !retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Sheets) FS() (fs.FS, error) {
+	return nil, errors.New("sheets:// datastore is write-only")
+}
+
+// Row returns the row of the report ( repository, timestamp, coverage, code to test ratio, test execution time ).
+// Unmeasured metrics are empty.
+func Row(r *report.Report) []interface{} {
+	row := []interface{}{r.Repository, r.Timestamp.UTC().Format(time.RFC3339)}
+	if r.IsMeasuredCoverage() {
+		row = append(row, fmt.Sprintf("%.1f", r.CoveragePercent()))
+	} else {
+		row = append(row, "")
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		row = append(row, fmt.Sprintf("%.1f", r.CodeToTestRatioRatio()))
+	} else {
+		row = append(row, "")
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		row = append(row, fmt.Sprintf("%.1f", time.Duration(*r.TestExecutionTime).Seconds()))
+	} else {
+		row = append(row, "")
+	}
+	return row
+}
+
+func retryable(err error) bool {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return false
+	}
+	return gerr.Code == http.StatusTooManyRequests || gerr.Code >= http.StatusInternalServerError
+}
@@ -0,0 +1,93 @@
+package sheets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestRow(t *testing.T) {
+	tet := float64(90 * time.Second)
+	tests := []struct {
+		r    *report.Report
+		want []interface{}
+	}{
+		{
+			&report.Report{
+				Repository: "owner/repo",
+				Timestamp:  time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+				Coverage:   &coverage.Coverage{Total: 200, Covered: 150},
+			},
+			[]interface{}{"owner/repo", "2021-08-01T00:00:00Z", "75.0", "", ""},
+		},
+		{
+			&report.Report{
+				Repository:        "owner/repo",
+				Timestamp:         time.Date(2021, 8, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+				TestExecutionTime: &tet,
+			},
+			[]interface{}{"owner/repo", "2021-08-01T00:00:00Z", "", "", "90.0"},
+		},
+	}
+	for _, tt := range tests {
+		got := Row(tt.r)
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	requests := 0
+	var got sheets.ValueRange
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !strings.Contains(r.URL.Path, "/v4/spreadsheets/sheet-id/values/Sheet1!A:E:append") {
+			t.Errorf("invalid path: %s", r.URL.Path)
+		}
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"quota exceeded"}}`))
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+	service, err := sheets.NewService(ctx, option.WithEndpoint(ts.URL), option.WithoutAuthentication(), option.WithHTTPClient(ts.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(service, "sheet-id", "Sheet1!A:E")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &report.Report{Repository: "owner/repo"}
+	if err := s.Store(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if want := 2; requests != want {
+		t.Errorf("got %v\nwant %v", requests, want)
+	}
+	if len(got.Values) != 1 || got.Values[0][0] != "owner/repo" {
+		t.Errorf("got %v", got.Values)
+	}
+}
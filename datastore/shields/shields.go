@@ -0,0 +1,124 @@
+package shields
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+const (
+	Format          = "Shields endpoint"
+	requestTimeout  = 30 * time.Second
+	maxResponseSize = 1 << 20
+	// precision of the synthesized coverage ( 0.01% )
+	summaryTotal = 10000
+)
+
+// Shields is a read-only datastore that synthesizes a summary-only report from a shields.io endpoint badge JSON
+type Shields struct {
+	repository string
+	endpoint   string
+}
+
+func New(repository, endpoint string) (*Shields, error) {
+	if _, _, err := gh.SplitRepository(repository); err != nil {
+		return nil, err
+	}
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, err
+	}
+	return &Shields{
+		repository: repository,
+		endpoint:   endpoint,
+	}, nil
+}
+
+func (s *Shields) Store(ctx context.Context, r *report.Report) error {
+	return errors.New("shields:// datastore is read-only")
+}
+
+type endpointBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+}
+
+func (s *Shields) FS() (fs.FS, error) {
+	ctx := context.Background()
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := gh.HTTPClientForDownload(u, requestTimeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the shields endpoint (%s): %s", s.endpoint, res.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	badge := &endpointBadge{}
+	if err := json.Unmarshal(b, badge); err != nil {
+		return nil, fmt.Errorf("invalid shields endpoint JSON (%s): %w", s.endpoint, err)
+	}
+	cover, err := parseMessage(badge.Message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shields endpoint JSON (%s): %w", s.endpoint, err)
+	}
+	now := time.Now().UTC()
+	r := &report.Report{
+		Repository: s.repository,
+		Coverage: &coverage.Coverage{
+			Type:    coverage.TypeLOC,
+			Format:  Format,
+			Total:   summaryTotal,
+			Covered: int(math.Round(cover * summaryTotal / 100)),
+			Files:   coverage.FileCoverages{},
+		},
+		SummaryOnly: true,
+		Timestamp:   now,
+	}
+	return &fstest.MapFS{
+		fmt.Sprintf("%s/report.json", s.repository): &fstest.MapFile{
+			Data:    r.Bytes(),
+			Mode:    fs.ModePerm,
+			ModTime: now,
+		},
+	}, nil
+}
+
+// parseMessage parses the coverage percentage of the badge message ( e.g. `85%`, `85.3 %` )
+func parseMessage(m string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m), "%")), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coverage message: %s", m)
+	}
+	if v < 0 || v > 100 {
+		return 0, fmt.Errorf("invalid coverage message: %s", m)
+	}
+	return v, nil
+}
@@ -0,0 +1,77 @@
+package shields
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestFS(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"schemaVersion":1,"label":"coverage","message":"85.3%","color":"green"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	s, err := New("owner/repo", ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys, err := s.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.Open("owner/repo/report.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &report.Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Repository, "owner/repo"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := r.CoveragePercent(), 85.3; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if !r.SummaryOnly {
+		t.Error("want summary only")
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"85%", 85.0, false},
+		{"85.3 %", 85.3, false},
+		{"100", 100.0, false},
+		{"unknown", 0, true},
+		{"120%", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseMessage(tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+	"time"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+)
+
+// timestampLayout is fixed-width so that timestamps can be sorted as strings
+const timestampLayout = "2006-01-02T15:04:05.000000000Z"
+
+// wait for the lock of the database file held by other processes
+const busyTimeout = 30 * time.Second
+
+const schema = `CREATE TABLE IF NOT EXISTS reports (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  owner TEXT NOT NULL,
+  repo TEXT NOT NULL,
+  ref TEXT NOT NULL,
+  "commit" TEXT NOT NULL,
+  coverage_total INTEGER,
+  coverage_covered INTEGER,
+  code_to_test_ratio_code INTEGER,
+  code_to_test_ratio_test INTEGER,
+  test_execution_time REAL,
+  timestamp TEXT NOT NULL,
+  raw TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS reports_owner_repo_timestamp ON reports (owner, repo, timestamp);`
+
+type SQLite struct {
+	db *sql.DB
+	mu sync.Mutex
+}
+
+func New(path string) (*SQLite, error) {
+	// _txlock=immediate takes the write lock at the beginning of transactions to avoid deadlocks between processes
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=%d&_txlock=immediate", path, busyTimeout.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLite{
+		db: db,
+	}, nil
+}
+
+func (s *SQLite) Store(ctx context.Context, r *report.Report) error {
+	owner, repo, err := gh.SplitRepository(r.Repository)
+	if err != nil {
+		return err
+	}
+	var (
+		coverageTotal, coverageCovered sql.NullInt64
+		ratioCode, ratioTest           sql.NullInt64
+		testExecutionTime              sql.NullFloat64
+	)
+	if r.Coverage != nil {
+		coverageTotal = sql.NullInt64{Int64: int64(r.Coverage.Total), Valid: true}
+		coverageCovered = sql.NullInt64{Int64: int64(r.Coverage.Covered), Valid: true}
+	}
+	if r.CodeToTestRatio != nil {
+		ratioCode = sql.NullInt64{Int64: int64(r.CodeToTestRatio.Code), Valid: true}
+		ratioTest = sql.NullInt64{Int64: int64(r.CodeToTestRatio.Test), Valid: true}
+	}
+	if r.TestExecutionTime != nil {
+		testExecutionTime = sql.NullFloat64{Float64: *r.TestExecutionTime, Valid: true}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO reports (owner, repo, ref, "commit", coverage_total, coverage_covered, code_to_test_ratio_code, code_to_test_ratio_test, test_execution_time, timestamp, raw)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		owner, repo, r.Ref, r.Commit, coverageTotal, coverageCovered, ratioCode, ratioTest, testExecutionTime, r.Timestamp.UTC().Format(timestampLayout), r.String()); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// FS returns the latest report of each repository
+func (s *SQLite) FS() (fs.FS, error) {
+	fsys := fstest.MapFS{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows, err := s.db.Query(`SELECT r.owner, r.repo, r.timestamp, r.raw FROM reports AS r
+WHERE r.id = (
+    SELECT l.id FROM reports AS l WHERE l.owner = r.owner AND l.repo = r.repo ORDER BY l.timestamp DESC, l.id DESC LIMIT 1
+)
+ORDER BY r.owner, r.repo`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var owner, repo, ts, raw string
+		if err := rows.Scan(&owner, &repo, &ts, &raw); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(timestampLayout, ts)
+		if err != nil {
+			return nil, err
+		}
+		path := fmt.Sprintf("%s/%s/report.json", owner, repo)
+		fsys[path] = &fstest.MapFile{
+			Data:    []byte(raw),
+			Mode:    fs.ModePerm,
+			ModTime: t,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &fsys, nil
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
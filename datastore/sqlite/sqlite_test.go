@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStoreAndFS(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "reports.db")
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	base := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	rs := []*report.Report{
+		{Repository: "owner/repo", Ref: "refs/heads/main", Commit: "a", Timestamp: base, Coverage: &coverage.Coverage{Total: 100, Covered: 50}},
+		{Repository: "owner/repo", Ref: "refs/heads/main", Commit: "c", Timestamp: base.Add(2 * time.Hour), Coverage: &coverage.Coverage{Total: 100, Covered: 70}},
+		{Repository: "owner/repo", Ref: "refs/heads/main", Commit: "b", Timestamp: base.Add(time.Hour), Coverage: &coverage.Coverage{Total: 100, Covered: 60}},
+		{Repository: "owner/other", Ref: "refs/heads/main", Commit: "d", Timestamp: base},
+	}
+	for _, r := range rs {
+		if err := s.Store(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fsys, err := s.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path       string
+		wantCommit string
+	}{
+		{"owner/repo/report.json", "c"},
+		{"owner/other/report.json", "d"},
+	}
+	for _, tt := range tests {
+		b, err := fs.ReadFile(fsys, tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &report.Report{}
+		if err := json.Unmarshal(b, got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Commit != tt.wantCommit {
+			t.Errorf("got %v\nwant %v", got.Commit, tt.wantCommit)
+		}
+	}
+}
+
+func TestStoreConcurrently(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "reports.db")
+	// simulate multiple processes writing to the same database file
+	n := 4
+	ss := []*SQLite{}
+	for i := 0; i < n; i++ {
+		s, err := New(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		ss = append(ss, s)
+	}
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, n*10)
+	for i, s := range ss {
+		wg.Add(1)
+		go func(i int, s *SQLite) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				r := &report.Report{Repository: fmt.Sprintf("owner/repo%d", i), Ref: "refs/heads/main", Commit: fmt.Sprintf("%d", j), Timestamp: time.Now()}
+				if err := s.Store(ctx, r); err != nil {
+					errs <- err
+				}
+			}
+		}(i, s)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	var c int
+	if err := ss[0].db.QueryRow("SELECT COUNT(*) FROM reports").Scan(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c != n*10 {
+		t.Errorf("got %v\nwant %v", c, n*10)
+	}
+}
@@ -0,0 +1,93 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/k1LoW/octocov/internal"
+	"github.com/k1LoW/octocov/report"
+)
+
+type StoreResult struct {
+	Datastore string
+	Err       error
+}
+
+// StoreAll stores the report to the datastores concurrently.
+// If concurrency is less than or equal to 0, the report is stored to all datastores at once.
+// It waits for all datastores and returns the results in the order of datastores.
+// If byRef is true, the report is also stored keyed by ref to the datastores that support it.
+// Storing is retried on transient errors according to retry ( no retry when nil ).
+func StoreAll(ctx context.Context, datastores []string, configRoot string, r *report.Report, concurrency int, byRef bool, retry *internal.RetryPolicy) []*StoreResult {
+	return storeAll(datastores, concurrency, func(u string) error {
+		return store(ctx, u, configRoot, r, byRef, retry)
+	})
+}
+
+// StorePatchCoverageAll stores the patch coverage to the datastores concurrently in the same way as StoreAll.
+// The datastores that do not support storing the patch coverage ( PatchStorer ) result in errPatchNotSupported.
+func StorePatchCoverageAll(ctx context.Context, datastores []string, configRoot string, pc *report.PatchCoverage, concurrency int, retry *internal.RetryPolicy) []*StoreResult {
+	return storeAll(datastores, concurrency, func(u string) error {
+		d, err := New(ctx, u, configRoot)
+		if err != nil {
+			return err
+		}
+		ps, ok := d.(PatchStorer)
+		if !ok {
+			return errPatchNotSupported
+		}
+		return retry.Do(ctx, func() error {
+			return ps.StorePatchCoverage(ctx, pc)
+		})
+	})
+}
+
+var errPatchNotSupported = errors.New("the datastore does not support storing the patch coverage")
+
+func storeAll(datastores []string, concurrency int, fn func(u string) error) []*StoreResult {
+	if concurrency <= 0 || concurrency > len(datastores) {
+		concurrency = len(datastores)
+	}
+	results := make([]*StoreResult, len(datastores))
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+	for i, u := range datastores {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			results[i] = &StoreResult{
+				Datastore: u,
+				Err:       fn(u),
+			}
+		}(i, u)
+	}
+	wg.Wait()
+	return results
+}
+
+func store(ctx context.Context, u, configRoot string, r *report.Report, byRef bool, retry *internal.RetryPolicy) error {
+	d, err := New(ctx, u, configRoot)
+	if err != nil {
+		return err
+	}
+	if err := retry.Do(ctx, func() error {
+		return d.Store(ctx, r)
+	}); err != nil {
+		return err
+	}
+	if !byRef {
+		return nil
+	}
+	rs, ok := d.(RefStorer)
+	if !ok {
+		return nil
+	}
+	return retry.Do(ctx, func() error {
+		return rs.StoreByRef(ctx, r)
+	})
+}
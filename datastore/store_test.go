@@ -0,0 +1,81 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStoreAll(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"a", "b"} {
+		if err := os.MkdirAll(filepath.Join(root, d, "owner", "repo"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &report.Report{Repository: "owner/repo", Ref: "refs/tags/v1.2.0"}
+	datastores := []string{"local://a", "local://notexist", "local://b"}
+	for _, concurrency := range []int{0, 1, 2} {
+		got := StoreAll(context.Background(), datastores, root, r, concurrency, false, nil)
+		if len(got) != len(datastores) {
+			t.Fatalf("got %v\nwant %v", len(got), len(datastores))
+		}
+		for i, res := range got {
+			if res.Datastore != datastores[i] {
+				t.Errorf("got %v\nwant %v", res.Datastore, datastores[i])
+			}
+			wantErr := datastores[i] == "local://notexist"
+			if (res.Err != nil) != wantErr {
+				t.Errorf("got %v\nwantErr %v", res.Err, wantErr)
+			}
+		}
+		for _, d := range []string{"a", "b"} {
+			if _, err := os.Stat(filepath.Join(root, d, "owner", "repo", "report.json")); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+func TestStoreAllByRef(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "owner", "repo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := &report.Report{Repository: "owner/repo", Ref: "refs/tags/v1.2.0"}
+	for _, res := range StoreAll(context.Background(), []string{"local://."}, root, r, 0, true, nil) {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+	for _, p := range []string{"owner/repo/report.json", "owner/repo/refs/v1.2.0/report.json"} {
+		if _, err := os.Stat(filepath.Join(root, p)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestStorePatchCoverageAll(t *testing.T) {
+	root := t.TempDir()
+	pc := &report.PatchCoverage{Repository: "owner/repo", Ref: "refs/pull/12/merge", Total: 2, Covered: 1}
+	datastores := []string{"local://.", "sqlite://report.db"}
+	got := StorePatchCoverageAll(context.Background(), datastores, root, pc, 0, nil)
+	if got[0].Err != nil {
+		t.Fatal(got[0].Err)
+	}
+	if !errors.Is(got[1].Err, errPatchNotSupported) {
+		t.Errorf("got %v\nwant %v", got[1].Err, errPatchNotSupported)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "owner/repo/refs/pull-12-merge/patch.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"covered":1`; !strings.Contains(string(b), want) {
+		t.Errorf("got %s\nwant %s", string(b), want)
+	}
+}
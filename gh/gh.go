@@ -19,9 +19,11 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	ghttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/google/go-github/v35/github"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/lestrrat-go/backoff/v2"
 )
 
@@ -126,6 +128,95 @@ func (g *Gh) PushContent(ctx context.Context, owner, repo, branch, content, cp,
 	return nil
 }
 
+type TreeBlob struct {
+	// path relative to the directory
+	Path string
+	SHA  string
+}
+
+// GetTreeBlobs returns the blobs under the directory of the branch and the committer time of the head commit.
+// It returns no blobs when the directory does not exist.
+func (g *Gh) GetTreeBlobs(ctx context.Context, owner, repo, branch, dir string) ([]*TreeBlob, time.Time, error) {
+	srv := g.client.Git
+	dRef, _, err := srv.GetRef(ctx, owner, repo, path.Join("heads", branch))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	commit, _, err := srv.GetCommit(ctx, owner, repo, dRef.GetObject().GetSHA())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	mtime := commit.GetCommitter().GetDate()
+	sha := commit.GetTree().GetSHA()
+	for _, name := range strings.Split(strings.Trim(path.Clean(filepath.ToSlash(dir)), "/"), "/") {
+		if name == "" || name == "." {
+			continue
+		}
+		t, _, err := srv.GetTree(ctx, owner, repo, sha, false)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		sha = ""
+		for _, e := range t.Entries {
+			if e.GetType() == "tree" && e.GetPath() == name {
+				sha = e.GetSHA()
+				break
+			}
+		}
+		if sha == "" {
+			return []*TreeBlob{}, mtime, nil
+		}
+	}
+	blobs, err := g.treeBlobs(ctx, owner, repo, sha, "")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return blobs, mtime, nil
+}
+
+func (g *Gh) treeBlobs(ctx context.Context, owner, repo, sha, base string) ([]*TreeBlob, error) {
+	srv := g.client.Git
+	t, _, err := srv.GetTree(ctx, owner, repo, sha, true)
+	if err != nil {
+		return nil, err
+	}
+	recursive := !t.GetTruncated()
+	if !recursive {
+		// the recursive listing is limited, so walk the subtrees one by one
+		t, _, err = srv.GetTree(ctx, owner, repo, sha, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	blobs := []*TreeBlob{}
+	for _, e := range t.Entries {
+		p := path.Join(base, e.GetPath())
+		switch e.GetType() {
+		case "blob":
+			blobs = append(blobs, &TreeBlob{Path: p, SHA: e.GetSHA()})
+		case "tree":
+			if recursive {
+				continue
+			}
+			sub, err := g.treeBlobs(ctx, owner, repo, e.GetSHA(), p)
+			if err != nil {
+				return nil, err
+			}
+			blobs = append(blobs, sub...)
+		}
+	}
+	return blobs, nil
+}
+
+// GetBlob returns the content of the blob
+func (g *Gh) GetBlob(ctx context.Context, owner, repo, sha string) ([]byte, error) {
+	b, _, err := g.client.Git.GetBlobRaw(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
 func (g *Gh) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
 	r, _, err := g.client.Repositories.Get(ctx, owner, repo)
 	if err != nil {
@@ -187,14 +278,14 @@ func (g *Gh) DetectCurrentJobID(ctx context.Context, owner, repo string) (int64,
 	)
 	b := p.Start(ctx)
 	for backoff.Continue(b) {
-		jobs, _, err := g.client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{})
+		jobs, err := g.listWorkflowJobs(ctx, owner, repo, runID)
 		if err != nil {
 			return 0, err
 		}
-		if len(jobs.Jobs) == 1 {
-			return jobs.Jobs[0].GetID(), nil
+		if len(jobs) == 1 {
+			return jobs[0].GetID(), nil
 		}
-		for _, j := range jobs.Jobs {
+		for _, j := range jobs {
 			if j.GetName() == os.Getenv("GTIHUB_JOB") {
 				return j.GetID(), nil
 			}
@@ -240,6 +331,16 @@ func (g *Gh) DetectCurrentPullRequestNumber(ctx context.Context, owner, repo str
 type PullRequestFile struct {
 	Filename string
 	BlobURL  string
+	// added, removed, modified, renamed, copied, changed or unchanged
+	Status           string
+	PreviousFilename string
+	// unified diff of the file ( empty for binary or too large files )
+	Patch string
+}
+
+// IsAdded reports whether the file is newly added in the pull request
+func (f *PullRequestFile) IsAdded() bool {
+	return f.Status == "added"
 }
 
 func (g *Gh) GetPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]*PullRequestFile, error) {
@@ -258,8 +359,11 @@ func (g *Gh) GetPullRequestFiles(ctx context.Context, owner, repo string, number
 		}
 		for _, f := range commitFiles {
 			files = append(files, &PullRequestFile{
-				Filename: f.GetFilename(),
-				BlobURL:  f.GetBlobURL(),
+				Filename:         f.GetFilename(),
+				BlobURL:          f.GetBlobURL(),
+				Status:           f.GetStatus(),
+				PreviousFilename: f.GetPreviousFilename(),
+				Patch:            f.GetPatch(),
 			})
 		}
 		page += 1
@@ -325,11 +429,11 @@ func (g *Gh) GetStepsByName(ctx context.Context, owner, repo string, name string
 L:
 	for backoff.Continue(b) {
 		max = 0
-		jobs, _, err := g.client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{})
+		jobs, err := g.listWorkflowJobs(ctx, owner, repo, runID)
 		if err != nil {
 			return nil, err
 		}
-		for _, j := range jobs.Jobs {
+		for _, j := range jobs {
 			log.Printf("search job: %d", j.GetID())
 			l := len(j.Steps)
 			for i, s := range j.Steps {
@@ -358,37 +462,312 @@ L:
 	return steps, nil
 }
 
+// GetStepsByPatterns returns the completed steps of the current workflow run whose names match include patterns and do not match exclude patterns.
+// All the completed steps are included when include is empty.
+func (g *Gh) GetStepsByPatterns(ctx context.Context, owner, repo string, include, exclude []string) ([]Step, error) {
+	if os.Getenv("GITHUB_RUN_ID") == "" {
+		return nil, fmt.Errorf("env %s is not set", "GITHUB_RUN_ID")
+	}
+	runID, err := strconv.ParseInt(os.Getenv("GITHUB_RUN_ID"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := g.listWorkflowJobs(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+	steps := []Step{}
+	for _, j := range jobs {
+		for _, s := range j.Steps {
+			// skip running steps ( e.g. the step running octocov )
+			if s.StartedAt == nil || s.CompletedAt == nil {
+				continue
+			}
+			steps = append(steps, Step{
+				Name:        s.GetName(),
+				StartedAt:   s.GetStartedAt().Time,
+				CompletedAt: s.GetCompletedAt().Time,
+			})
+		}
+	}
+	if len(include) == 0 {
+		// only exclude patterns are set
+		include = []string{"*"}
+	}
+	steps, err = FilterSteps(steps, include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("could not get step times: %s", strings.Join(include, ", "))
+	}
+	return steps, nil
+}
+
+// listWorkflowJobs returns all the jobs of the workflow run ( a run may have more jobs than a page, e.g. by a large matrix )
+func (g *Gh) listWorkflowJobs(ctx context.Context, owner, repo string, runID int64) ([]*github.WorkflowJob, error) {
+	jobs := []*github.WorkflowJob{}
+	opts := &github.ListWorkflowJobsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		l, res, err := g.client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, opts)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, l.Jobs...)
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return jobs, nil
+}
+
+// FilterSteps returns steps whose names match any of include patterns and none of exclude patterns.
+// In patterns, `*` matches any sequence of characters ( including `/` ) and `?` matches any single character.
+func FilterSteps(steps []Step, include, exclude []string) ([]Step, error) {
+	in, err := compileStepPatterns(include)
+	if err != nil {
+		return nil, err
+	}
+	ex, err := compileStepPatterns(exclude)
+	if err != nil {
+		return nil, err
+	}
+	filtered := []Step{}
+	for _, s := range steps {
+		if !matchAny(in, s.Name) || matchAny(ex, s.Name) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered, nil
+}
+
+func compileStepPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := []*regexp.Regexp{}
+	for _, p := range patterns {
+		var b strings.Builder
+		b.WriteString("^")
+		for _, c := range p {
+			switch c {
+			case '*':
+				b.WriteString(".*")
+			case '?':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		b.WriteString("$")
+		re, err := regexp.Compile(b.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid step name pattern: %s", p)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func matchAny(res []*regexp.Regexp, name string) bool {
+	for _, re := range res {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
 const commentSig = "<!-- octocov -->"
 
-func (g *Gh) PutComment(ctx context.Context, owner, repo string, n int, comment string) error {
-	if err := g.deleteCurrentIssueComment(ctx, owner, repo, n); err != nil {
+// PutComment posts the comment to the pull request.
+// When update is true, it edits the latest comment by octocov in place ( and deletes the older ones ), and posts a new comment only if none exists.
+// Otherwise, it deletes the current comments by octocov and posts a new comment.
+func (g *Gh) PutComment(ctx context.Context, owner, repo string, n int, comment string, update bool) error {
+	c := strings.Join([]string{comment, commentSig}, "\n")
+	current, err := g.currentIssueComments(ctx, owner, repo, n)
+	if err != nil {
 		return err
 	}
-	c := strings.Join([]string{comment, commentSig}, "\n")
+	if update && len(current) > 0 {
+		latest := current[len(current)-1]
+		for _, old := range current[:len(current)-1] {
+			if _, err := g.client.Issues.DeleteComment(ctx, owner, repo, old.GetID()); err != nil {
+				return err
+			}
+		}
+		if _, _, err := g.client.Issues.EditComment(ctx, owner, repo, latest.GetID(), &github.IssueComment{Body: &c}); err != nil {
+			return err
+		}
+		return nil
+	}
+	for _, old := range current {
+		if _, err := g.client.Issues.DeleteComment(ctx, owner, repo, old.GetID()); err != nil {
+			return err
+		}
+	}
 	if _, _, err := g.client.Issues.CreateComment(ctx, owner, repo, n, &github.IssueComment{Body: &c}); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (g *Gh) deleteCurrentIssueComment(ctx context.Context, owner, repo string, n int) error {
-	opts := &github.IssueListCommentsOptions{}
-	comments, _, err := g.client.Issues.ListComments(ctx, owner, repo, n, opts)
+// currentIssueComments returns the comments by octocov on the pull request in the order of creation
+func (g *Gh) currentIssueComments(ctx context.Context, owner, repo string, n int) ([]*github.IssueComment, error) {
+	current := []*github.IssueComment{}
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, res, err := g.client.Issues.ListComments(ctx, owner, repo, n, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			if strings.Contains(c.GetBody(), commentSig) {
+				current = append(current, c)
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+	return current, nil
+}
+
+// checkAnnotationsMax is the max number of the annotations per request of the Checks API
+const checkAnnotationsMax = 50
+
+const checkAnnotationLevelWarning = "warning"
+
+type CheckAnnotation struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Title     string
+	Message   string
+}
+
+// CreateCheckRun creates the completed check run with the annotations on the head commit of the pull request.
+// The annotations are sent in batches because the Checks API accepts up to 50 annotations per request.
+func (g *Gh) CreateCheckRun(ctx context.Context, owner, repo string, n int, name, title, summary, conclusion string, annotations []*CheckAnnotation) error {
+	pr, _, err := g.client.PullRequests.Get(ctx, owner, repo, n)
 	if err != nil {
 		return err
 	}
-	for _, c := range comments {
-		if strings.Contains(*c.Body, commentSig) {
-			_, err = g.client.Issues.DeleteComment(ctx, owner, repo, *c.ID)
+	batches := [][]*github.CheckRunAnnotation{}
+	batch := []*github.CheckRunAnnotation{}
+	for _, a := range annotations {
+		batch = append(batch, &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       github.Int(a.StartLine),
+			EndLine:         github.Int(a.EndLine),
+			AnnotationLevel: github.String(checkAnnotationLevelWarning),
+			Title:           github.String(a.Title),
+			Message:         github.String(a.Message),
+		})
+		if len(batch) == checkAnnotationsMax {
+			batches = append(batches, batch)
+			batch = []*github.CheckRunAnnotation{}
+		}
+	}
+	if len(batch) > 0 || len(batches) == 0 {
+		batches = append(batches, batch)
+	}
+	var id int64
+	for i, b := range batches {
+		output := &github.CheckRunOutput{
+			Title:       github.String(title),
+			Summary:     github.String(summary),
+			Annotations: b,
+		}
+		// the check run is completed by the last request
+		status := "in_progress"
+		var c *string
+		var completedAt *github.Timestamp
+		if i == len(batches)-1 {
+			status = "completed"
+			c = github.String(conclusion)
+			completedAt = &github.Timestamp{Time: time.Now()}
+		}
+		if i == 0 {
+			run, _, err := g.client.Checks.CreateCheckRun(ctx, owner, repo, github.CreateCheckRunOptions{
+				Name:        name,
+				HeadSHA:     pr.GetHead().GetSHA(),
+				Status:      github.String(status),
+				Conclusion:  c,
+				CompletedAt: completedAt,
+				Output:      output,
+			})
 			if err != nil {
 				return err
 			}
+			id = run.GetID()
+			continue
+		}
+		if _, _, err := g.client.Checks.UpdateCheckRun(ctx, owner, repo, id, github.UpdateCheckRunOptions{
+			Name:        name,
+			Status:      github.String(status),
+			Conclusion:  c,
+			CompletedAt: completedAt,
+			Output:      output,
+		}); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func PushUsingLocalGit(ctx context.Context, gitRoot string, addPaths []string, message string) error {
+const (
+	ReviewEventApprove        = "APPROVE"
+	ReviewEventComment        = "COMMENT"
+	ReviewEventRequestChanges = "REQUEST_CHANGES"
+)
+
+// PutReview submits a review with the event to the pull request and dismisses the previous reviews by octocov.
+// When the event is rejected ( e.g. a bot can not approve or request changes on its own pull request ), the review is submitted as COMMENT.
+// It returns the event of the submitted review.
+func (g *Gh) PutReview(ctx context.Context, owner, repo string, n int, comment, event string) (string, error) {
+	if err := g.dismissCurrentReviews(ctx, owner, repo, n); err != nil {
+		return "", err
+	}
+	c := strings.Join([]string{comment, commentSig}, "\n")
+	_, _, err := g.client.PullRequests.CreateReview(ctx, owner, repo, n, &github.PullRequestReviewRequest{Body: &c, Event: &event})
+	if err == nil {
+		return event, nil
+	}
+	var eres *github.ErrorResponse
+	if event == ReviewEventComment || !errors.As(err, &eres) || eres.Response == nil || eres.Response.StatusCode != http.StatusUnprocessableEntity {
+		return "", err
+	}
+	fallback := ReviewEventComment
+	if _, _, err := g.client.PullRequests.CreateReview(ctx, owner, repo, n, &github.PullRequestReviewRequest{Body: &c, Event: &fallback}); err != nil {
+		return "", err
+	}
+	return fallback, nil
+}
+
+func (g *Gh) dismissCurrentReviews(ctx context.Context, owner, repo string, n int) error {
+	reviews, _, err := g.client.PullRequests.ListReviews(ctx, owner, repo, n, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return err
+	}
+	m := "Superseded by a newer review of octocov"
+	for _, r := range reviews {
+		if !strings.Contains(r.GetBody(), commentSig) {
+			continue
+		}
+		// only approvals and change requests can be dismissed
+		if r.GetState() != "APPROVED" && r.GetState() != "CHANGES_REQUESTED" {
+			continue
+		}
+		if _, _, err := g.client.PullRequests.DismissReview(ctx, owner, repo, n, r.GetID(), &github.PullRequestReviewDismissalRequest{Message: &m}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PushUsingLocalGit commits the files and pushes the commit. Pushing is retried on transient errors according to retry ( no retry when nil ).
+func PushUsingLocalGit(ctx context.Context, gitRoot string, addPaths []string, message string, retry *internal.RetryPolicy) error {
 	r, err := git.PlainOpen(gitRoot)
 	if err != nil {
 		return err
@@ -439,11 +818,13 @@ func PushUsingLocalGit(ctx context.Context, gitRoot string, addPaths []string, m
 		return err
 	}
 
-	if err := r.PushContext(ctx, &git.PushOptions{
-		Auth: &ghttp.BasicAuth{
-			Username: "octocov",
-			Password: os.Getenv("GITHUB_TOKEN"),
-		},
+	if err := retry.Do(ctx, func() error {
+		return r.PushContext(ctx, &git.PushOptions{
+			Auth: &ghttp.BasicAuth{
+				Username: "octocov",
+				Password: os.Getenv("GITHUB_TOKEN"),
+			},
+		})
 	}); err != nil {
 		return err
 	}
@@ -451,10 +832,51 @@ func PushUsingLocalGit(ctx context.Context, gitRoot string, addPaths []string, m
 	return nil
 }
 
+// SyncLocalGit fetches the remote ( origin ) and resets the current branch hard to the head of the remote branch.
+// It is used before regenerating the files to push, so that the commit is not pushed onto a stale tree.
+func SyncLocalGit(ctx context.Context, gitRoot string, retry *internal.RetryPolicy) error {
+	r, err := git.PlainOpen(gitRoot)
+	if err != nil {
+		return err
+	}
+	head, err := r.Head()
+	if err != nil {
+		return err
+	}
+	if !head.Name().IsBranch() {
+		return fmt.Errorf("HEAD is not a branch: %s", head.Name())
+	}
+	opts := &git.FetchOptions{RemoteName: git.DefaultRemoteName}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		opts.Auth = &ghttp.BasicAuth{
+			Username: "octocov",
+			Password: token,
+		}
+	}
+	if err := retry.Do(ctx, func() error {
+		if err := r.FetchContext(ctx, opts); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	remote, err := r.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, head.Name().Short()), true)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the remote branch of %s: %w", head.Name().Short(), err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	return w.Reset(&git.ResetOptions{Mode: git.HardReset, Commit: remote.Hash()})
+}
+
 type GitHubEvent struct {
 	Name    string
 	Number  int
 	State   string
+	Author  string
 	Payload interface{}
 }
 
@@ -477,10 +899,16 @@ func DecodeGitHubEvent() (*GitHubEvent, error) {
 		PullRequest struct {
 			Number int    `json:"number,omitempty"`
 			State  string `json:"state,omitempty"`
+			User   struct {
+				Login string `json:"login,omitempty"`
+			} `json:"user,omitempty"`
 		} `json:"pull_request,omitempty"`
 		Issue struct {
 			Number int    `json:"number,omitempty"`
 			State  string `json:"state,omitempty"`
+			User   struct {
+				Login string `json:"login,omitempty"`
+			} `json:"user,omitempty"`
 		} `json:"issue,omitempty"`
 	}{}
 	if err := json.Unmarshal(b, &s); err != nil {
@@ -490,9 +918,11 @@ func DecodeGitHubEvent() (*GitHubEvent, error) {
 	case s.PullRequest.Number > 0:
 		i.Number = s.PullRequest.Number
 		i.State = s.PullRequest.State
+		i.Author = s.PullRequest.User.Login
 	case s.Issue.Number > 0:
 		i.Number = s.Issue.Number
 		i.State = s.Issue.State
+		i.Author = s.Issue.User.Login
 	}
 
 	var payload interface{}
@@ -527,14 +957,8 @@ func (rt roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
 }
 
 func httpClient(token string) *http.Client {
-	t := &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: 5 * time.Second,
-		}).Dial,
-		TLSHandshakeTimeout: 5 * time.Second,
-	}
 	rt := roundTripper{
-		transport:   t,
+		transport:   newTransport(),
 		accessToken: token,
 	}
 	return &http.Client{
@@ -542,3 +966,49 @@ func httpClient(token string) *http.Client {
 		Transport: rt,
 	}
 }
+
+// HTTPClientForDownload returns the HTTP client for downloading the file of the URL.
+// If the URL is on GitHub and env GITHUB_TOKEN is set, the client sends the token.
+func HTTPClientForDownload(u *url.URL, timeout time.Duration) *http.Client {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" || !isGitHubHost(u.Hostname()) {
+		return &http.Client{
+			Timeout:   timeout,
+			Transport: newTransport(),
+		}
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: roundTripper{
+			transport:   newTransport(),
+			accessToken: token,
+		},
+	}
+}
+
+func isGitHubHost(host string) bool {
+	hosts := []string{"github.com", "api.github.com", "raw.githubusercontent.com"}
+	for _, env := range []string{"GITHUB_SERVER_URL", "GITHUB_API_URL"} {
+		if v := os.Getenv(env); v != "" {
+			if u, err := url.Parse(v); err == nil {
+				hosts = append(hosts, u.Hostname())
+			}
+		}
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout: 5 * time.Second,
+		}).Dial,
+		TLSHandshakeTimeout: 5 * time.Second,
+	}
+}
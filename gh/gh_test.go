@@ -0,0 +1,318 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v35/github"
+)
+
+func TestFilterSteps(t *testing.T) {
+	steps := []Step{
+		{Name: "Set up job"},
+		{Name: "Run actions/checkout@v2"},
+		{Name: "Run go test ./..."},
+		{Name: "Run integration test"},
+		{Name: "Run test of docs"},
+	}
+	tests := []struct {
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{[]string{"*test*"}, nil, []string{"Run go test ./...", "Run integration test", "Run test of docs"}},
+		{[]string{"*test*"}, []string{"*docs"}, []string{"Run go test ./...", "Run integration test"}},
+		{[]string{"Run go test ./..."}, nil, []string{"Run go test ./..."}},
+		{[]string{"Run ??tegration test", "Set up *"}, nil, []string{"Set up job", "Run integration test"}},
+		{[]string{"*checkout*"}, []string{"*"}, []string{}},
+		{nil, nil, []string{}},
+	}
+	for _, tt := range tests {
+		filtered, err := FilterSteps(steps, tt.include, tt.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, s := range filtered {
+			got = append(got, s.Name)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestPutComment(t *testing.T) {
+	tests := []struct {
+		comments    string
+		update      bool
+		wantDeleted []string
+		wantEdited  []string
+		wantCreated int
+	}{
+		{`[]`, true, []string{}, []string{}, 1},
+		{`[{"id": 10, "body": "old\n<!-- octocov -->"}, {"id": 11, "body": "other"}, {"id": 12, "body": "latest\n<!-- octocov -->"}]`, true, []string{"10"}, []string{"12"}, 0},
+		{`[{"id": 10, "body": "old\n<!-- octocov -->"}, {"id": 11, "body": "other"}, {"id": 12, "body": "latest\n<!-- octocov -->"}]`, false, []string{"10", "12"}, []string{}, 1},
+	}
+	for _, tt := range tests {
+		deleted := []string{}
+		edited := []string{}
+		created := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/issues/1/comments":
+				_, _ = w.Write([]byte(tt.comments))
+			case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/comments/"):
+				deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/issues/comments/"))
+				w.WriteHeader(http.StatusNoContent)
+			case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/comments/"):
+				edited = append(edited, strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/issues/comments/"))
+				_, _ = w.Write([]byte(`{"id": 12}`))
+			case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/1/comments":
+				created++
+				_, _ = w.Write([]byte(`{"id": 13}`))
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		u, _ := url.Parse(ts.URL + "/")
+		c := github.NewClient(nil)
+		c.BaseURL = u
+		g := &Gh{client: c}
+		err := g.PutComment(context.Background(), "owner", "repo", 1, "report", tt.update)
+		ts.Close()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if diff := cmp.Diff(deleted, tt.wantDeleted, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+		if diff := cmp.Diff(edited, tt.wantEdited, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+		if created != tt.wantCreated {
+			t.Errorf("got %v\nwant %v", created, tt.wantCreated)
+		}
+	}
+}
+
+func TestCreateCheckRun(t *testing.T) {
+	tests := []struct {
+		annotations int
+		wantBatches []int
+	}{
+		{0, []int{0}},
+		{50, []int{50}},
+		{120, []int{50, 50, 20}},
+	}
+	for _, tt := range tests {
+		got := []int{}
+		statuses := []string{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/pulls/1":
+				_, _ = w.Write([]byte(`{"number": 1, "head": {"sha": "abcdef"}}`))
+			case (r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/check-runs") || (r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/check-runs/5"):
+				req := &github.CreateCheckRunOptions{}
+				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+					t.Error(err)
+					return
+				}
+				if r.Method == http.MethodPost && req.HeadSHA != "abcdef" {
+					t.Errorf("got %v\nwant %v", req.HeadSHA, "abcdef")
+				}
+				got = append(got, len(req.Output.Annotations))
+				statuses = append(statuses, req.GetStatus())
+				_, _ = w.Write([]byte(`{"id": 5}`))
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		u, _ := url.Parse(ts.URL + "/")
+		c := github.NewClient(nil)
+		c.BaseURL = u
+		g := &Gh{client: c}
+		annotations := []*CheckAnnotation{}
+		for i := 0; i < tt.annotations; i++ {
+			annotations = append(annotations, &CheckAnnotation{Path: "main.go", StartLine: i + 1, EndLine: i + 1, Title: "Uncovered lines", Message: "not covered"})
+		}
+		err := g.CreateCheckRun(context.Background(), "owner", "repo", 1, "octocov", "title", "summary", "neutral", annotations)
+		ts.Close()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if diff := cmp.Diff(got, tt.wantBatches, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+		if statuses[len(statuses)-1] != "completed" {
+			t.Errorf("got %v\nwant %v", statuses, "completed")
+		}
+	}
+}
+
+func TestPutReview(t *testing.T) {
+	tests := []struct {
+		event      string
+		rejectSelf bool
+		want       string
+	}{
+		{ReviewEventComment, true, ReviewEventComment},
+		{ReviewEventRequestChanges, false, ReviewEventRequestChanges},
+		{ReviewEventRequestChanges, true, ReviewEventComment},
+		{ReviewEventApprove, true, ReviewEventComment},
+	}
+	for _, tt := range tests {
+		dismissed := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/pulls/1/reviews":
+				_, _ = w.Write([]byte(`[{"id": 10, "body": "old\n<!-- octocov -->", "state": "CHANGES_REQUESTED"}, {"id": 11, "body": "other", "state": "CHANGES_REQUESTED"}]`))
+			case r.Method == http.MethodPut && r.URL.Path == "/repos/owner/repo/pulls/1/reviews/10/dismissals":
+				dismissed++
+				_, _ = w.Write([]byte(`{"id": 10}`))
+			case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/pulls/1/reviews":
+				req := &github.PullRequestReviewRequest{}
+				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+					t.Error(err)
+					return
+				}
+				if tt.rejectSelf && req.GetEvent() != ReviewEventComment {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_, _ = w.Write([]byte(`{"message": "Can not request changes on your own pull request"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"id": 12}`))
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		u, _ := url.Parse(ts.URL + "/")
+		c := github.NewClient(nil)
+		c.BaseURL = u
+		g := &Gh{client: c}
+		got, err := g.PutReview(context.Background(), "owner", "repo", 1, "report", tt.event)
+		ts.Close()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if dismissed != 1 {
+			t.Errorf("got %v\nwant %v", dismissed, 1)
+		}
+	}
+}
+
+func TestSyncLocalGit(t *testing.T) {
+	if token, ok := os.LookupEnv("GITHUB_TOKEN"); ok {
+		_ = os.Unsetenv("GITHUB_TOKEN")
+		t.Cleanup(func() {
+			_ = os.Setenv("GITHUB_TOKEN", token)
+		})
+	}
+	remote := t.TempDir()
+	if _, err := git.PlainInit(remote, true); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(dir, file string) plumbing.Hash {
+		t.Helper()
+		r, err := git.PlainOpen(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := r.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Add(file); err != nil {
+			t.Fatal(err)
+		}
+		h, err := w.Commit(file, &git.CommitOptions{Author: &object.Signature{Name: "octocov", Email: "octocov@example.com", When: time.Now()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	push := func(dir string) {
+		t.Helper()
+		r, err := git.PlainOpen(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Push(&git.PushOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := t.TempDir()
+	ra, err := git.PlainInit(a, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ra.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{remote}}); err != nil {
+		t.Fatal(err)
+	}
+	commit(a, "a.txt")
+	push(a)
+
+	b := t.TempDir()
+	if _, err := git.PlainClone(b, false, &git.CloneOptions{URL: remote}); err != nil {
+		t.Fatal(err)
+	}
+	want := commit(b, "b.txt")
+	push(b)
+
+	// a is stale, and has the local change of the generated file
+	if err := os.WriteFile(filepath.Join(a, "a.txt"), []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncLocalGit(context.Background(), a, nil); err != nil {
+		t.Fatal(err)
+	}
+	head, err := ra.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := head.Hash(); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	got, err := os.ReadFile(filepath.Join(a, "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "a.txt" {
+		t.Errorf("got %v\nwant %v", string(got), "a.txt")
+	}
+	if _, err := os.Stat(filepath.Join(a, "b.txt")); err != nil {
+		t.Error(err)
+	}
+}
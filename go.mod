@@ -5,6 +5,8 @@ go 1.16
 require (
 	cloud.google.com/go/bigquery v1.8.0
 	cloud.google.com/go/storage v1.16.0
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.3.0
+	github.com/Azure/azure-sdk-for-go/sdk/storage/azblob v1.0.0
 	github.com/Microsoft/go-winio v0.4.17 // indirect
 	github.com/antonmedv/expr v1.8.9
 	github.com/aws/aws-sdk-go v1.40.11
@@ -17,6 +19,7 @@ require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/google/go-cmp v0.5.6
 	github.com/google/go-github/v35 v35.1.0
+	github.com/google/uuid v1.2.0 // indirect
 	github.com/hhatto/gocloc v0.4.1
 	github.com/jszwec/s3fs v0.3.1
 	github.com/k1LoW/duration v1.1.0
@@ -24,12 +27,14 @@ require (
 	github.com/k1LoW/osfs v0.1.1
 	github.com/lestrrat-go/backoff/v2 v2.0.8
 	github.com/lucasb-eyer/go-colorful v1.0.3
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/mauri870/gcsfs v0.0.0-20210217184550-8b539458430a
 	github.com/oklog/ulid/v2 v2.0.2
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/spf13/cobra v1.2.1
 	golang.org/x/exp v0.0.0-20200224162631-6cc2880d07d6
 	golang.org/x/image v0.0.0-20190802002840-cff245a6509b
+	golang.org/x/oauth2 v0.0.0-20210628180205-a41e5a781914
 	golang.org/x/tools v0.1.5
 	google.golang.org/api v0.52.0
 )
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LookupField returns the value of the dot-separated path in the decoded JSON. Array elements are specified by index ( e.g. `files.0.name` )
+func LookupField(v interface{}, path string) (interface{}, error) {
+	cur := v
+	for _, k := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, "$"), "."), ".") {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			n, ok := c[k]
+			if !ok {
+				return nil, fmt.Errorf("%s is not found", path)
+			}
+			cur = n
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("%s is not found", path)
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("%s is not found", path)
+		}
+	}
+	return cur, nil
+}
+
+// LookupNumber returns the number of the dot-separated path in the decoded JSON. Numeric strings are also accepted
+func LookupNumber(v interface{}, path string) (float64, error) {
+	f, err := LookupField(v, path)
+	if err != nil {
+		return 0, err
+	}
+	switch n := f.(type) {
+	case float64:
+		return n, nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	}
+	return 0, fmt.Errorf("%s is not a number", path)
+}
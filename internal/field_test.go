@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestLookupField(t *testing.T) {
+	v := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []interface{}{"x", map[string]interface{}{"c": 1.0}},
+		},
+	}
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr bool
+	}{
+		{"a.b.0", "x", false},
+		{"a.b.1.c", 1.0, false},
+		{"a.b.2", nil, true},
+		{"a.c", nil, true},
+		{"a.b.0.d", nil, true},
+		{"$.a.b.0", "x", false},
+		{".a.b.1.c", 1.0, false},
+	}
+	for _, tt := range tests {
+		got, err := LookupField(v, tt.path)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestLookupNumber(t *testing.T) {
+	v := map[string]interface{}{
+		"n": 1.5,
+		"s": "2.5",
+		"x": "many",
+	}
+	tests := []struct {
+		path    string
+		want    float64
+		wantErr bool
+	}{
+		{"n", 1.5, false},
+		{"s", 2.5, false},
+		{"x", 0, true},
+		{"y", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := LookupNumber(v, tt.path)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
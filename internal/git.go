@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GetCommitTime returns the committer time of the commit. If commit is empty, HEAD is used.
+func GetCommitTime(gitRoot, commit string) (time.Time, error) {
+	c, err := commitObject(gitRoot, commit)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.Committer.When.UTC(), nil
+}
+
+// GetCommitAuthor returns the author name of the commit. If commit is empty, HEAD is used.
+func GetCommitAuthor(gitRoot, commit string) (string, error) {
+	c, err := commitObject(gitRoot, commit)
+	if err != nil {
+		return "", err
+	}
+	return c.Author.Name, nil
+}
+
+// ValidateGitRoot checks that path is the root of a git repository ( `.git` may be a file of a submodule or a worktree )
+func ValidateGitRoot(path string) error {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return fmt.Errorf("%s is not the root of a git repository", path)
+	}
+	if _, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true}); err != nil {
+		return fmt.Errorf("%s is not a git repository: %w", path, err)
+	}
+	return nil
+}
+
+func commitObject(gitRoot, commit string) (*object.Commit, error) {
+	r, err := git.PlainOpen(gitRoot)
+	if err != nil {
+		return nil, err
+	}
+	h := plumbing.NewHash(commit)
+	if commit == "" {
+		ref, err := r.Head()
+		if err != nil {
+			return nil, err
+		}
+		h = ref.Hash()
+	}
+	return r.CommitObject(h)
+}
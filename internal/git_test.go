@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGetCommitTime(t *testing.T) {
+	want := time.Date(2021, 7, 1, 12, 34, 56, 0, time.UTC)
+	dir, h := initRepository(t, &object.Signature{Name: "octocov", Email: "octocov@example.com", When: want})
+
+	tests := []struct {
+		commit  string
+		wantErr bool
+	}{
+		{h, false},
+		{"", false},
+		{"0123456789012345678901234567890123456789", true},
+	}
+	for _, tt := range tests {
+		got, err := GetCommitTime(dir, tt.commit)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if !got.Equal(want) {
+			t.Errorf("got %v\nwant %v", got, want)
+		}
+	}
+}
+
+func TestGetCommitAuthor(t *testing.T) {
+	dir, h := initRepository(t, &object.Signature{Name: "alice", Email: "alice@example.com", When: time.Now()})
+	tests := []struct {
+		commit  string
+		want    string
+		wantErr bool
+	}{
+		{h, "alice", false},
+		{"", "alice", false},
+		{"0123456789012345678901234567890123456789", "", true},
+	}
+	for _, tt := range tests {
+		got, err := GetCommitAuthor(dir, tt.commit)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func initRepository(t *testing.T, sig *object.Signature) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	h, err := w.Commit("initial commit", &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, h.String()
+}
+
+func TestValidateGitRoot(t *testing.T) {
+	sig := &object.Signature{Name: "alice", Email: "alice@example.com", When: time.Now()}
+	root, _ := initRepository(t, sig)
+	sub := filepath.Join(root, "sub")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// like a submodule, .git is a file that points to the git directory
+	linked := t.TempDir()
+	if err := os.WriteFile(filepath.Join(linked, ".git"), []byte(fmt.Sprintf("gitdir: %s\n", filepath.Join(root, ".git"))), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{root, false},
+		{sub, true},
+		{linked, false},
+		{t.TempDir(), true},
+	}
+	for _, tt := range tests {
+		if err := ValidateGitRoot(tt.path); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
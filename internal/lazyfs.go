@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"context"
+	"io/fs"
+	"testing/fstest"
+)
+
+// LazyFS is the fs.FS of the listed files ( e.g. the blobs of the datastores ) that reads the content of a file when it is opened
+type LazyFS struct {
+	index fstest.MapFS
+	read  func(ctx context.Context, name string) ([]byte, error)
+}
+
+// NewLazyFS returns the LazyFS of the index ( the files without the content ) and the function to read the content of the file of the index
+func NewLazyFS(index fstest.MapFS, read func(ctx context.Context, name string) ([]byte, error)) *LazyFS {
+	return &LazyFS{
+		index: index,
+		read:  read,
+	}
+}
+
+func (l *LazyFS) Open(name string) (fs.File, error) {
+	mf, ok := l.index[name]
+	if !ok {
+		// directories
+		return l.index.Open(name)
+	}
+	data, err := l.read(context.Background(), name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	fsys := fstest.MapFS{name: &fstest.MapFile{
+		Data:    data,
+		Mode:    mf.Mode,
+		ModTime: mf.ModTime,
+	}}
+	return fsys.Open(name)
+}
+
+func (l *LazyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return l.index.ReadDir(name)
+}
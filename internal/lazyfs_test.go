@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLazyFS(t *testing.T) {
+	contents := map[string]string{
+		"owner/repo/report.json":  `{"repository":"owner/repo"}`,
+		"owner/other/report.json": `{"repository":"owner/other"}`,
+	}
+	index := fstest.MapFS{}
+	for p := range contents {
+		index[p] = &fstest.MapFile{Mode: fs.ModePerm}
+	}
+	index["owner/broken/report.json"] = &fstest.MapFile{Mode: fs.ModePerm}
+	read := []string{}
+	fsys := NewLazyFS(index, func(ctx context.Context, name string) ([]byte, error) {
+		read = append(read, name)
+		c, ok := contents[name]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return []byte(c), nil
+	})
+
+	entries, err := fs.ReadDir(fsys, "owner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(entries), 3; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if len(read) != 0 {
+		t.Errorf("got %v\nwant no reads on listing", read)
+	}
+	b, err := fs.ReadFile(fsys, "owner/repo/report.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), contents["owner/repo/report.json"]; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := len(read), 1; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if _, err := fs.ReadFile(fsys, "owner/broken/report.json"); err == nil {
+		t.Error("want error")
+	}
+	if _, err := fsys.Open("owner/none/report.json"); err == nil {
+		t.Error("want error")
+	}
+}
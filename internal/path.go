@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -33,6 +34,27 @@ func GetRootPath(base string) (string, error) {
 	return "", fmt.Errorf("failed to traverse the Git root path: %s", base)
 }
 
+// ListFiles returns the sorted paths of all files under root except the .git directory
+func ListFiles(root string) ([]string, error) {
+	files := []string{}
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && strings.Contains(path, ".git/") {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && !strings.Contains(path, ".git/") {
+			files = append(files, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 func DetectPrefix(gitRoot, wd string, files, cfiles []string) string {
 	rcfiles := [][]string{}
 	for _, f := range cfiles {
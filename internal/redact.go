@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "*****"
+
+// secrets shorter than this are not registered to avoid masking unrelated output
+const secretMinLength = 4
+
+// SecretEnvKeys are the names of environment variables whose values are masked in output
+var SecretEnvKeys = []string{
+	"GITHUB_TOKEN",
+	"GITLAB_TOKEN",
+	"GITEA_TOKEN",
+	"CI_JOB_TOKEN",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"GOOGLE_APPLICATION_CREDENTIALS_JSON",
+	"AZURE_STORAGE_KEY",
+	"AZURE_STORAGE_CONNECTION_STRING",
+	"MACKEREL_APIKEY",
+}
+
+var urlCredentialRe = regexp.MustCompile(`(https?://)[^/\s:@]+(:[^/\s@]*)?@`)
+
+var (
+	secrets   = []string{}
+	secretsMu sync.RWMutex
+)
+
+// RegisterSecret registers the value to be masked by Redact. Each line of a multi-line value is also registered
+func RegisterSecret(v string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range append([]string{v}, strings.Split(v, "\n")...) {
+		s = strings.TrimSpace(s)
+		if len(s) < secretMinLength || contains(secrets, s) {
+			continue
+		}
+		secrets = append(secrets, s)
+	}
+	// mask longer secrets first
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+// RegisterSecretsFromEnv registers the values of SecretEnvKeys
+func RegisterSecretsFromEnv() {
+	for _, k := range SecretEnvKeys {
+		if v := os.Getenv(k); v != "" {
+			RegisterSecret(v)
+		}
+	}
+}
+
+// Redact masks the registered secrets and the credentials of URLs in s
+func Redact(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, v := range secrets {
+		s = strings.ReplaceAll(s, v, redacted)
+	}
+	return urlCredentialRe.ReplaceAllString(s, "${1}"+redacted+"@")
+}
+
+// RedactStderr replaces os.Stderr with a pipe that masks secrets line by line before writing to the original stderr.
+// The returned function restores os.Stderr and flushes the remaining output.
+func RedactStderr() (func(), error) {
+	orig := os.Stderr
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		br := bufio.NewReader(pr)
+		for {
+			line, err := br.ReadString('\n')
+			if line != "" {
+				_, _ = io.WriteString(orig, Redact(line))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	os.Stderr = pw
+	return func() {
+		os.Stderr = orig
+		_ = pw.Close()
+		<-done
+		_ = pr.Close()
+	}, nil
+}
+
+func contains(s []string, e string) bool {
+	for _, v := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var invalidRefKeyRe = regexp.MustCompile(`[^A-Za-z0-9._+-]+`)
+
+// RefKey returns the key of the ref for storing reports by ref ( e.g. refs/tags/v1.2.0 -> v1.2.0 )
+func RefKey(ref string) string {
+	k := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+	return strings.Trim(invalidRefKeyRe.ReplaceAllString(strings.TrimPrefix(k, "refs/"), "-"), "-.")
+}
+
+// RepositoryFileName returns the file name of the report of the repository for the datastores that can not have directories ( e.g. owner/repo -> owner_repo.json )
+func RepositoryFileName(repository string) string {
+	return fmt.Sprintf("%s.json", invalidRefKeyRe.ReplaceAllString(repository, "_"))
+}
+
+// RefReportPath returns the path of the report of the repository stored by ref
+func RefReportPath(repository, ref string) string {
+	return fmt.Sprintf("%s/refs/%s/report.json", repository, RefKey(ref))
+}
+
+// RefPatchCoveragePath returns the path of the patch coverage of the repository stored by ref
+func RefPatchCoveragePath(repository, ref string) string {
+	return fmt.Sprintf("%s/refs/%s/patch.json", repository, RefKey(ref))
+}
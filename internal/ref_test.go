@@ -0,0 +1,59 @@
+package internal
+
+import "testing"
+
+func TestRefKey(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"refs/heads/main", "main"},
+		{"refs/tags/v1.2.0", "v1.2.0"},
+		{"refs/heads/feat/new", "feat-new"},
+		{"refs/pull/8/head", "pull-8-head"},
+		{"", ""},
+		{"refs/heads/..", ""},
+	}
+	for _, tt := range tests {
+		got := RefKey(tt.ref)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestRepositoryFileName(t *testing.T) {
+	tests := []struct {
+		repository string
+		want       string
+	}{
+		{"owner/repo", "owner_repo.json"},
+		{"owner/repo/sub", "owner_repo_sub.json"},
+		{"owner/my.repo", "owner_my.repo.json"},
+	}
+	for _, tt := range tests {
+		got := RepositoryFileName(tt.repository)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestRefReportPath(t *testing.T) {
+	tests := []struct {
+		repository string
+		ref        string
+		want       string
+	}{
+		{"owner/repo", "refs/tags/v1.2.0", "owner/repo/refs/v1.2.0/report.json"},
+		{"owner/repo", "v1.2.0", "owner/repo/refs/v1.2.0/report.json"},
+		{"owner/repo", "refs/heads/feat/new", "owner/repo/refs/feat-new/report.json"},
+		{"owner/repo", "refs/pull/8/head", "owner/repo/refs/pull-8-head/report.json"},
+	}
+	for _, tt := range tests {
+		got := RefReportPath(tt.repository, tt.ref)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
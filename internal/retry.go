@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v35/github"
+	"github.com/lestrrat-go/backoff/v2"
+	"google.golang.org/api/googleapi"
+)
+
+const maxRetryInterval = 30 * time.Second
+
+// RetryPolicy is the policy to retry the operation with exponential backoff on transient errors
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Multiplier     float64
+}
+
+// Do runs fn until it succeeds, it fails with a non-transient error or the attempts reach MaxAttempts
+func (p *RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	if p == nil || p.MaxAttempts <= 1 {
+		return fn()
+	}
+	maxInterval := maxRetryInterval
+	if p.InitialBackoff > maxInterval {
+		maxInterval = p.InitialBackoff
+	}
+	b := backoff.Exponential(
+		backoff.WithMinInterval(p.InitialBackoff),
+		backoff.WithMaxInterval(maxInterval),
+		backoff.WithMultiplier(p.Multiplier),
+		backoff.WithJitterFactor(0.05),
+		backoff.WithMaxRetries(p.MaxAttempts-1),
+	).Start(ctx)
+	var err error
+	for backoff.Continue(b) {
+		err = fn()
+		if err == nil || !IsTransientError(err) {
+			return err
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// StatusError is the error of the HTTP response with the error status code
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// IsTransientError reports whether the error is transient ( timeouts, connection reset/refused, 5xx and 429 responses ) and the operation is worth retrying.
+// Errors such as authentication failures are not transient.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// the file does not exist yet ( e.g. the first run for the repository )
+	if errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	if code, ok := statusCode(err); ok {
+		return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	// the other network errors such as "no such host" are permanent
+	var nerr net.Error
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		return true
+	}
+	// AWS SDK wraps the original error ( e.g. the network error of RequestError ) without Unwrap
+	var oerr interface{ OrigErr() error }
+	if errors.As(err, &oerr) && oerr.OrigErr() != nil && oerr.OrigErr() != err {
+		return IsTransientError(oerr.OrigErr())
+	}
+	// go-git wraps the unexpected HTTP response without Unwrap
+	var uerr *plumbing.UnexpectedError
+	if errors.As(err, &uerr) && uerr.Err != nil {
+		return IsTransientError(uerr.Err)
+	}
+	return false
+}
+
+// statusCode returns the HTTP status code of the error of the API clients
+func statusCode(err error) (int, bool) {
+	var serr *StatusError
+	if errors.As(err, &serr) {
+		return serr.StatusCode, true
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code, true
+	}
+	var gherr *github.ErrorResponse
+	if errors.As(err, &gherr) && gherr.Response != nil {
+		return gherr.Response.StatusCode, true
+	}
+	// e.g. awserr.RequestFailure of AWS SDK and the HTTP error of go-git
+	var cerr interface{ StatusCode() int }
+	if errors.As(err, &cerr) {
+		return cerr.StatusCode(), true
+	}
+	var aerr *azcore.ResponseError
+	if errors.As(err, &aerr) {
+		return aerr.StatusCode, true
+	}
+	return 0, false
+}
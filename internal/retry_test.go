@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/google/go-github/v35/github"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("invalid datastore"), false},
+		{context.Canceled, false},
+		{&StatusError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}, true},
+		{fmt.Errorf("failed to request GitLab API: %w", &StatusError{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"}), false},
+		{&googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{&googleapi.Error{Code: http.StatusForbidden}, false},
+		{&github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError}}, true},
+		{azureResponseError(http.StatusServiceUnavailable), true},
+		{fmt.Errorf("download: %w", azureResponseError(http.StatusNotFound)), false},
+		{awserr.NewRequestFailure(awserr.New("InternalError", "internal error", nil), http.StatusInternalServerError, "id"), true},
+		{awserr.NewRequestFailure(awserr.New("AccessDenied", "access denied", nil), http.StatusForbidden, "id"), false},
+		{awserr.NewRequestFailure(awserr.New("SlowDown", "slow down", nil), http.StatusTooManyRequests, "id"), true},
+		{awserr.New("RequestError", "send request failed", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), true},
+		{fmt.Errorf("write: %w", syscall.ECONNRESET), true},
+		{&net.DNSError{Err: "no such host", IsTimeout: true}, true},
+		{&net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, false},
+		{&net.OpError{Op: "read", Net: "tcp", Err: &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}}, true},
+		{&fs.PathError{Op: "open", Path: "owner/repo/report.json", Err: fs.ErrNotExist}, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransientError(tt.err); got != tt.want {
+			t.Errorf("%v: got %v\nwant %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func azureResponseError(code int) *azcore.ResponseError {
+	return &azcore.ResponseError{
+		StatusCode: code,
+		RawResponse: &http.Response{
+			StatusCode: code,
+			Status:     http.StatusText(code),
+			Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "account.blob.core.windows.net"}},
+		},
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	transient := &StatusError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+	tests := []struct {
+		policy    *RetryPolicy
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{nil, []error{transient, nil}, 1, true},
+		{&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 2}, []error{transient, nil}, 2, false},
+		{&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 2}, []error{transient, transient, transient, nil}, 3, true},
+		{&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 2}, []error{errors.New("auth failed"), nil}, 1, true},
+	}
+	for _, tt := range tests {
+		calls := 0
+		err := tt.policy.Do(context.Background(), func() error {
+			err := tt.errs[calls]
+			calls++
+			return err
+		})
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("got %v\nwant %v", calls, tt.wantCalls)
+		}
+	}
+}
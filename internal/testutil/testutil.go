@@ -0,0 +1,87 @@
+// Package testutil provides the helpers shared by the tests of the datastores
+package testutil
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// SetEnv sets the environment variable during the test and restores it on cleanup ( t.Setenv is not available in Go 1.16 )
+func SetEnv(t *testing.T, key, value string) {
+	t.Helper()
+	orig, ok := os.LookupEnv(key)
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, orig)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// PackageFiles is the files of the versions of the generic package for the fake package registries ( GitLab, Gitea )
+type PackageFiles struct {
+	mu sync.Mutex
+	// version -> file name -> content
+	files map[string]map[string][]byte
+}
+
+func NewPackageFiles() *PackageFiles {
+	return &PackageFiles{files: map[string]map[string][]byte{}}
+}
+
+func (p *PackageFiles) Put(version, name string, b []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.files[version]; !ok {
+		p.files[version] = map[string][]byte{}
+	}
+	p.files[version][name] = b
+}
+
+func (p *PackageFiles) Get(version, name string) ([]byte, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, ok := p.files[version][name]
+	return b, ok
+}
+
+// Delete deletes the file and reports whether the file existed
+func (p *PackageFiles) Delete(version, name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.files[version][name]; !ok {
+		return false
+	}
+	delete(p.files[version], name)
+	return true
+}
+
+// Versions returns the versions in order
+func (p *PackageFiles) Versions() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	versions := []string{}
+	for v := range p.files {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Names returns the file names of the version in order
+func (p *PackageFiles) Names(version string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := []string{}
+	for n := range p.files[version] {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const (
+	defaultMethod  = http.MethodPost
+	requestTimeout = 30 * time.Second
+)
+
+// Webhook sends the report to the HTTP endpoint
+type Webhook struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+	// Body is the template ( text/template ) of the request body. default: report JSON
+	Body   string
+	client *http.Client
+}
+
+func NewWebhook(url, method string, headers map[string]string, body string) *Webhook {
+	if method == "" {
+		method = defaultMethod
+	}
+	return &Webhook{
+		URL:     url,
+		Method:  strings.ToUpper(method),
+		Headers: headers,
+		Body:    body,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Send sends the report ( and the diff report, if any ) to the webhook
+func (w *Webhook) Send(ctx context.Context, r *report.Report, d *report.DiffReport) error {
+	body, err := w.render(r, d)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, w.Method, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode >= http.StatusBadRequest {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("failed to send webhook (%s %s): %s: %s", w.Method, w.URL, res.Status, string(b))
+	}
+	return nil
+}
+
+func (w *Webhook) render(r *report.Report, d *report.DiffReport) ([]byte, error) {
+	if w.Body == "" {
+		return r.Bytes(), nil
+	}
+	tmpl, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		},
+	}).Parse(w.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifications.webhook.body: %w", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, map[string]interface{}{
+		"Report": r,
+		"Diff":   d,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid notifications.webhook.body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var (
+		gotMethod string
+		gotHeader string
+		gotBody   []byte
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotMethod = req.Method
+		gotHeader = req.Header.Get("X-Token")
+		gotBody, _ = io.ReadAll(req.Body)
+		if req.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	a := &report.Report{Repository: "owner/repo", Coverage: &coverage.Coverage{Total: 100, Covered: 40}}
+	b := &report.Report{Repository: "owner/repo", Coverage: &coverage.Coverage{Total: 100, Covered: 50}}
+	d := a.Compare(b)
+
+	tests := []struct {
+		path       string
+		method     string
+		body       string
+		diff       *report.DiffReport
+		wantMethod string
+		wantBody   string
+		wantErr    bool
+	}{
+		{"/", "", "", nil, http.MethodPost, string(b.Bytes()), false},
+		{"/", "put", `{"repo":"{{ .Report.Repository }}","coverage":{{ .Report.CoveragePercent }}}`, nil, http.MethodPut, `{"repo":"owner/repo","coverage":50}`, false},
+		{"/", "", `{{ .Diff.Coverage.Diff }}`, d, http.MethodPost, "10", false},
+		{"/", "", `{{ .Diff | json }}`, nil, http.MethodPost, "null", false},
+		{"/error", "", "", nil, http.MethodPost, string(b.Bytes()), true},
+		{"/", "", `{{ .Report`, nil, "", "", true},
+	}
+	for _, tt := range tests {
+		gotMethod, gotHeader, gotBody = "", "", nil
+		w := NewWebhook(ts.URL+tt.path, tt.method, map[string]string{"X-Token": "secret"}, tt.body)
+		err := w.Send(context.Background(), b, tt.diff)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+		if gotMethod != tt.wantMethod {
+			t.Errorf("got %v\nwant %v", gotMethod, tt.wantMethod)
+		}
+		if string(gotBody) != tt.wantBody {
+			t.Errorf("got %v\nwant %v", string(gotBody), tt.wantBody)
+		}
+		if tt.wantMethod != "" && gotHeader != "secret" {
+			t.Errorf("got %v\nwant %v", gotHeader, "secret")
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"image/color"
 	"io"
+	"strings"
 	"text/template"
 
 	"github.com/golang/freetype/truetype"
@@ -16,13 +17,26 @@ const defaultLabelColor = "#24292E"
 const defaultMessageColor = "#007EC6"
 const fontSize = 11
 const dpi = 72
+const logoSize = 14
+const logoPadding = 3
+
+// Styles of the badge ( https://shields.io/badges )
+const (
+	StyleFlat        = "flat"
+	StyleFlatSquare  = "flat-square"
+	StyleForTheBadge = "for-the-badge"
+)
 
 type Badge struct {
 	Label        string
 	Message      string
 	LabelColor   string
 	MessageColor string
-	drawer       *font.Drawer
+	// default: flat
+	Style string
+	// data URI of the logo on the left of the label ( see ResolveLogo )
+	Logo   string
+	drawer *font.Drawer
 }
 
 //go:embed badge.svg.tmpl
@@ -53,25 +67,106 @@ func New(l, m string) *Badge {
 	}
 }
 
+// layout is the geometry of the badge in the SVG coordinates ( the texts are in the 10x scale )
+type layout struct {
+	label         string
+	message       string
+	height        int
+	radius        int
+	fontSize      int
+	letterSpacing int
+	textY         int
+	shadowY       int
+	bold          bool
+	gradient      bool
+	shadow        bool
+	logoY         int
+	labelWidth    float64
+	messageWidth  float64
+	labelX        float64
+	messageX      float64
+}
+
+func (b *Badge) layout() (*layout, error) {
+	if err := ValidateStyle(b.Style); err != nil {
+		return nil, err
+	}
+	if b.Logo != "" && !logoDataURIRe.MatchString(b.Logo) {
+		return nil, fmt.Errorf("invalid badge logo: %s (base64 data URI of PNG/SVG)", b.Logo)
+	}
+	l := &layout{
+		label:   b.Label,
+		message: b.Message,
+	}
+	var lw, mw float64
+	switch b.Style {
+	case StyleForTheBadge:
+		// uppercase and letter-spaced texts on the taller square badge
+		l.label = strings.ToUpper(l.label)
+		l.message = strings.ToUpper(l.message)
+		lw = 9 + b.stringWidth(l.label) + letterSpacingWidth(l.label) + 9
+		mw = 9 + b.stringWidth(l.message) + letterSpacingWidth(l.message) + 9
+		l.height = 28
+		l.fontSize = 100
+		l.letterSpacing = 10
+		l.textY = 175
+		l.bold = true
+	default:
+		// https://github.com/badges/shields/tree/master/spec
+		lw = 6 + b.stringWidth(l.label) + 4
+		mw = 4 + b.stringWidth(l.message) + 6
+		l.height = 20
+		l.fontSize = 110
+		l.textY = 140
+		l.shadowY = 150
+		if b.Style != StyleFlatSquare {
+			l.radius = 3
+			l.gradient = true
+			l.shadow = true
+		}
+	}
+	// the logo is placed on the left of the label and the label is centered in the rest
+	lo := 0.0
+	if b.Logo != "" {
+		lo = logoSize + logoPadding
+		lw += lo
+		l.logoY = (l.height - logoSize) / 2
+	}
+	l.labelWidth = lw
+	l.messageWidth = mw
+	l.labelX = (lo + lw) * 10 / 2
+	l.messageX = (lw * 10) + (mw * 10 / 2)
+	return l, nil
+}
+
 func (b *Badge) Render(wr io.Writer) error {
+	l, err := b.layout()
+	if err != nil {
+		return err
+	}
 	tmpl := template.Must(template.New("badge").Parse(string(badgeTmpl)))
 
-	// https://github.com/badges/shields/tree/master/spec
-	lw := 6 + b.stringWidth(b.Label) + 4
-	mw := 4 + b.stringWidth(b.Message) + 6
-	lx := lw * 10 / 2
-	mx := (lw * 10) + (mw * 10 / 2)
-
 	d := map[string]interface{}{
-		"Label":        b.Label,
-		"Message":      b.Message,
-		"LabelColor":   b.LabelColor,
-		"MessageColor": b.MessageColor,
-		"Width":        lw + mw,
-		"LabelWidth":   lw,
-		"MessageWidth": mw,
-		"LabelX":       lx,
-		"MessageX":     mx,
+		"Label":         l.label,
+		"Message":       l.message,
+		"LabelColor":    b.LabelColor,
+		"MessageColor":  b.MessageColor,
+		"Logo":          b.Logo,
+		"LogoY":         l.logoY,
+		"Height":        l.height,
+		"Radius":        l.radius,
+		"FontSize":      l.fontSize,
+		"LetterSpacing": l.letterSpacing,
+		"TextY":         l.textY,
+		"ShadowY":       l.shadowY,
+		"Bold":          l.bold,
+		"Gradient":      l.gradient,
+		"Shadow":        l.shadow,
+		"Width":         l.labelWidth + l.messageWidth,
+		"LabelWidth":    l.labelWidth,
+		"MessageWidth":  l.messageWidth,
+		"LabelX":        l.labelX,
+		"MessageX":      l.messageX,
 	}
 	if err := tmpl.Execute(wr, d); err != nil {
 		return err
@@ -80,6 +175,20 @@ func (b *Badge) Render(wr io.Writer) error {
 	return nil
 }
 
+// ValidateStyle returns an error when the style is not supported ( "" is flat )
+func ValidateStyle(style string) error {
+	switch style {
+	case "", StyleFlat, StyleFlatSquare, StyleForTheBadge:
+		return nil
+	}
+	return fmt.Errorf("invalid badge style: %s (%s, %s or %s)", style, StyleFlat, StyleFlatSquare, StyleForTheBadge)
+}
+
+// letterSpacingWidth returns the width added by letter-spacing of for-the-badge ( 1px per character )
+func letterSpacingWidth(s string) float64 {
+	return float64(len([]rune(s)))
+}
+
 func (b *Badge) stringWidth(s string) float64 {
 	converted := []rune{}
 	for _, c := range s {
@@ -0,0 +1,106 @@
+package badge
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderStyle(t *testing.T) {
+	tests := []struct {
+		style   string
+		want    []string
+		notWant []string
+		wantErr bool
+	}{
+		{"", []string{`height="20"`, `rx="3"`, `fill="url(#s)"`, `fill-opacity=".3"`, ">coverage<"}, nil, false},
+		{StyleFlat, []string{`height="20"`, `rx="3"`, `fill="url(#s)"`}, nil, false},
+		{StyleFlatSquare, []string{`height="20"`, `rx="0"`, ">coverage<"}, []string{`fill="url(#s)"`, `fill-opacity=".3"`}, false},
+		{StyleForTheBadge, []string{`height="28"`, `rx="0"`, `letter-spacing="10"`, `font-weight="bold"`, ">COVERAGE<"}, []string{`fill="url(#s)"`, ">coverage<"}, false},
+		{"plastic", nil, nil, true},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		b.Style = tt.style
+		buf := new(bytes.Buffer)
+		if err := b.Render(buf); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.style, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want err", tt.style)
+			continue
+		}
+		got := buf.String()
+		for _, w := range tt.want {
+			if !strings.Contains(got, w) {
+				t.Errorf("%s: got %v\nwant %v", tt.style, got, w)
+			}
+		}
+		for _, w := range tt.notWant {
+			if strings.Contains(got, w) {
+				t.Errorf("%s: got %v\nnot want %v", tt.style, got, w)
+			}
+		}
+	}
+}
+
+func TestRenderLogo(t *testing.T) {
+	logo := "data:image/png;base64,iVBORw0KGgo="
+	tests := []struct {
+		style   string
+		logo    string
+		want    []string
+		wantErr bool
+	}{
+		{"", "", []string{`<!-- <image x="5" y="3" width="14" height="14" xlink:href=""/> -->`}, false},
+		{"", logo, []string{`<image x="5" y="3" width="14" height="14" xlink:href="` + logo + `"/>`}, false},
+		{StyleForTheBadge, logo, []string{`<image x="5" y="7" width="14" height="14" xlink:href="` + logo + `"/>`}, false},
+		{"", "go", nil, true},
+		{"", `data:image/png;base64,"/><script>`, nil, true},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		b.Style = tt.style
+		b.Logo = tt.logo
+		buf := new(bytes.Buffer)
+		if err := b.Render(buf); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.logo, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want err", tt.logo)
+			continue
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("got %v\nwant %v", buf.String(), w)
+			}
+		}
+	}
+}
+
+func TestRenderLogoWidth(t *testing.T) {
+	without := New("coverage", "80.0%")
+	with := New("coverage", "80.0%")
+	with.Logo = "data:image/png;base64,iVBORw0KGgo="
+	bw := new(bytes.Buffer)
+	if err := without.Render(bw); err != nil {
+		t.Fatal(err)
+	}
+	bl := new(bytes.Buffer)
+	if err := with.Render(bl); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(bw.String(), `<rect width="68" height="20" fill="#24292E"/>`) {
+		t.Errorf("got %v", bw.String())
+	}
+	// 14px of the logo and 3px of the padding
+	if !strings.Contains(bl.String(), `<rect width="85" height="20" fill="#24292E"/>`) {
+		t.Errorf("got %v", bl.String())
+	}
+}
@@ -0,0 +1,89 @@
+package badge
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// https://github.com/badges/shields/blob/7d452472defa0e0bd71d6443393e522e8457f856/badge-maker/lib/color.js#L8-L12
+const (
+	ColorBrightGreen = "#4C1"
+	ColorGreen       = "#97CA00"
+	ColorYellowGreen = "#A4A61D"
+	ColorYellow      = "#DFB317"
+	ColorOrange      = "#FE7D37"
+	ColorRed         = "#E05D44"
+	ColorBlue        = "#007EC6"
+	ColorGrey        = "#555"
+	ColorLightGrey   = "#9F9F9F"
+)
+
+var namedColors = map[string]string{
+	"brightgreen": ColorBrightGreen,
+	"green":       ColorGreen,
+	"yellowgreen": ColorYellowGreen,
+	"yellow":      ColorYellow,
+	"orange":      ColorOrange,
+	"red":         ColorRed,
+	"blue":        ColorBlue,
+	"grey":        ColorGrey,
+	"lightgrey":   ColorLightGrey,
+}
+
+var hexColorRe = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ParseColor parses the named color ( e.g. green ) or the hex color ( e.g. #97CA00 )
+func ParseColor(s string) (string, error) {
+	if c, ok := namedColors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	if hexColorRe.MatchString(s) {
+		return "#" + strings.TrimPrefix(s, "#"), nil
+	}
+	return "", fmt.Errorf("invalid color: %s", s)
+}
+
+// Threshold is the color of the values greater than or equal to Min
+type Threshold struct {
+	Min   float64
+	Color string
+}
+
+type Thresholds []Threshold
+
+// ParseThresholds parses thresholds in the `MIN:COLOR` form ( e.g. 80:green )
+func ParseThresholds(in []string) (Thresholds, error) {
+	ts := Thresholds{}
+	for _, s := range in {
+		i := strings.LastIndex(s, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid threshold: %s", s)
+		}
+		min, err := strconv.ParseFloat(strings.TrimSpace(s[:i]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold: %s", s)
+		}
+		c, err := ParseColor(strings.TrimSpace(s[i+1:]))
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, Threshold{Min: min, Color: c})
+	}
+	sort.SliceStable(ts, func(i, j int) bool {
+		return ts[i].Min > ts[j].Min
+	})
+	return ts, nil
+}
+
+// Color returns the color of the highest threshold that the value reaches. If none, it returns the default message color
+func (ts Thresholds) Color(v float64) string {
+	for _, t := range ts {
+		if v >= t.Min {
+			return t.Color
+		}
+	}
+	return defaultMessageColor
+}
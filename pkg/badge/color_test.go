@@ -0,0 +1,34 @@
+package badge
+
+import (
+	"testing"
+)
+
+func TestThresholds(t *testing.T) {
+	ts, err := ParseThresholds([]string{"0:red", "80:green", "60:#DFB317", "-1:abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{95, "#97CA00"},
+		{80, "#97CA00"},
+		{79.9, "#DFB317"},
+		{10, "#E05D44"},
+		{-0.5, "#abc"},
+		{-3, defaultMessageColor},
+	}
+	for _, tt := range tests {
+		if got := ts.Color(tt.in); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"80", "high:green", "80:greenish"} {
+		if _, err := ParseThresholds([]string{in}); err == nil {
+			t.Errorf("got nil\nwant error: %s", in)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package badge
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	logoRequestTimeout = 10 * time.Second
+	maxLogoSize        = 64 << 10
+)
+
+// SimpleIconsURL is the base URL of the icons of simple-icons ( https://simpleicons.org )
+var SimpleIconsURL = "https://cdn.simpleicons.org"
+
+var (
+	logoSlugRe    = regexp.MustCompile(`^[a-z0-9.+-]+$`)
+	logoDataURIRe = regexp.MustCompile(`^data:image/(png|svg\+xml);base64,[A-Za-z0-9+/]+=*$`)
+)
+
+// ValidateLogo returns an error when the logo is neither a base64 data URI of PNG/SVG nor a simple-icons slug ( "" is no logo )
+func ValidateLogo(logo string) error {
+	if logo == "" || logoDataURIRe.MatchString(logo) || logoSlugRe.MatchString(logo) {
+		return nil
+	}
+	return fmt.Errorf("invalid badge logo: %s (base64 data URI of PNG/SVG or simple-icons slug)", logo)
+}
+
+// ResolveLogo returns the data URI of the logo.
+// The simple-icons slug ( e.g. go ) is resolved by downloading the white icon, because the badge is rendered as an image and can not refer to external resources.
+func ResolveLogo(ctx context.Context, logo string) (string, error) {
+	if err := ValidateLogo(logo); err != nil {
+		return "", err
+	}
+	if logo == "" || strings.HasPrefix(logo, "data:") {
+		return logo, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, logoRequestTimeout)
+	defer cancel()
+	u := fmt.Sprintf("%s/%s/white", strings.TrimSuffix(SimpleIconsURL, "/"), logo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get the logo %s: %s", logo, res.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxLogoSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(b) > maxLogoSize {
+		return "", fmt.Errorf("failed to get the logo %s: too large", logo)
+	}
+	if !strings.Contains(string(b), "<svg") {
+		return "", fmt.Errorf("failed to get the logo %s: not SVG", logo)
+	}
+	return fmt.Sprintf("data:image/svg+xml;base64,%s", base64.StdEncoding.EncodeToString(b)), nil
+}
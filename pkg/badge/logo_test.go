@@ -0,0 +1,57 @@
+package badge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveLogo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/go/white":
+			_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`))
+		case "/html/white":
+			_, _ = w.Write([]byte(`<html></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	orig := SimpleIconsURL
+	SimpleIconsURL = ts.URL
+	t.Cleanup(func() {
+		SimpleIconsURL = orig
+	})
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"data:image/png;base64,iVBORw0KGgo=", "data:image/png;base64,iVBORw0KGgo=", false},
+		{"go", "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciPjwvc3ZnPg==", false},
+		{"notfound", "", true},
+		{"html", "", true},
+		{"Go Lang", "", true},
+		{"data:text/html;base64,PGh0bWw+", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveLogo(context.Background(), tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.in, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want err", tt.in)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
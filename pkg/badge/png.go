@@ -0,0 +1,219 @@
+package badge
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// pngScale is the scale of the PNG badge to the SVG badge for high-density displays
+const pngScale = 2
+
+// RenderPNG rasterizes the badge in the same layout as Render.
+// The logo of SVG is not supported.
+func (b *Badge) RenderPNG(wr io.Writer) error {
+	l, err := b.layout()
+	if err != nil {
+		return err
+	}
+	lc, err := hexToColor(b.LabelColor)
+	if err != nil {
+		return err
+	}
+	mc, err := hexToColor(b.MessageColor)
+	if err != nil {
+		return err
+	}
+	lw := int(math.Round(l.labelWidth * pngScale))
+	w := int(math.Round((l.labelWidth + l.messageWidth) * pngScale))
+	h := l.height * pngScale
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, image.Rect(0, 0, lw, h), image.NewUniform(lc), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(lw, 0, w, h), image.NewUniform(mc), image.Point{}, draw.Src)
+	if l.gradient {
+		// from #bbb to #000 at the opacity of .1
+		for y := 0; y < h; y++ {
+			v := uint8(0xbb * (1 - float64(y)/float64(h-1)))
+			draw.Draw(img, image.Rect(0, y, w, y+1), image.NewUniform(color.NRGBA{R: v, G: v, B: v, A: 0x1a}), image.Point{}, draw.Over)
+		}
+	}
+
+	if b.Logo != "" {
+		logo, err := decodePNGLogo(b.Logo)
+		if err != nil {
+			return err
+		}
+		r := image.Rect(5*pngScale, l.logoY*pngScale, (5+logoSize)*pngScale, (l.logoY+logoSize)*pngScale)
+		draw.ApproxBiLinear.Scale(img, r, logo, logo.Bounds(), draw.Over, nil)
+	}
+
+	ttf, err := truetype.Parse(noto)
+	if err != nil {
+		return err
+	}
+	face := truetype.NewFace(ttf, &truetype.Options{
+		Size:    float64(l.fontSize) / 10 * pngScale,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	})
+	spacing := float64(l.letterSpacing) / 10 * pngScale
+	texts := []struct {
+		s    string
+		x    float64
+		bold bool
+	}{
+		{l.label, l.labelX, false},
+		{l.message, l.messageX, l.bold},
+	}
+	for _, t := range texts {
+		cx := t.x / 10 * pngScale
+		if l.shadow {
+			drawText(img, face, t.s, cx, float64(l.shadowY)/10*pngScale, spacing, t.bold, color.NRGBA{R: 0x01, G: 0x01, B: 0x01, A: 0x4d})
+		}
+		drawText(img, face, t.s, cx, float64(l.textY)/10*pngScale, spacing, t.bold, color.White)
+	}
+
+	if l.radius > 0 {
+		roundCorners(img, float64(l.radius*pngScale))
+	}
+	return png.Encode(wr, img)
+}
+
+// drawText draws the text centered at cx on the baseline y.
+// The trend arrows ( ▲ and ▼ ) are drawn as triangles because the font does not have them.
+func drawText(img draw.Image, face font.Face, s string, cx, y, spacing float64, bold bool, c color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+	}
+	aw := float64(face.Metrics().Height) / 64 * 0.5
+	tw := 0.0
+	for _, r := range s {
+		if isArrow(r) {
+			tw += aw
+		} else {
+			tw += float64(d.MeasureString(string(r))) / 64
+		}
+		tw += spacing
+	}
+	offsets := []float64{0}
+	if bold {
+		offsets = append(offsets, 1)
+	}
+	for _, o := range offsets {
+		d.Dot = fixed.Point26_6{X: fixed.Int26_6((cx - tw/2 + o) * 64), Y: fixed.Int26_6(y * 64)}
+		for _, r := range s {
+			if isArrow(r) {
+				drawArrow(img, float64(d.Dot.X)/64, y, aw, r == '▲', c)
+				d.Dot.X += fixed.Int26_6(aw * 64)
+			} else {
+				d.DrawString(string(r))
+			}
+			d.Dot.X += fixed.Int26_6(spacing * 64)
+		}
+	}
+}
+
+func isArrow(r rune) bool {
+	return r == '▲' || r == '▼'
+}
+
+// drawArrow draws the equilateral triangle of the width w on the baseline y from x
+func drawArrow(img draw.Image, x, y, w float64, up bool, c color.Color) {
+	h := w * math.Sqrt(3) / 2
+	src := image.NewUniform(c)
+	for i := 0; i < int(math.Ceil(h)); i++ {
+		// the width of the row from the apex
+		rw := w * (float64(i) + 0.5) / h
+		ry := int(y - h + float64(i))
+		if !up {
+			rw = w * (h - float64(i) - 0.5) / h
+		}
+		x0 := int(math.Round(x + (w-rw)/2))
+		x1 := int(math.Round(x + (w+rw)/2))
+		draw.Draw(img, image.Rect(x0, ry, x1, ry+1), src, image.Point{}, draw.Over)
+	}
+}
+
+// roundCorners makes the outside of the rounded corners transparent with anti-aliasing
+func roundCorners(img *image.RGBA, r float64) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// the center of the corner circle nearest to the pixel
+			cx, cy := float64(x)+0.5, float64(y)+0.5
+			switch {
+			case cx < r:
+			case cx > float64(w)-r:
+				cx = float64(w) - cx
+			default:
+				continue
+			}
+			switch {
+			case cy < r:
+			case cy > float64(h)-r:
+				cy = float64(h) - cy
+			default:
+				continue
+			}
+			a := r - math.Hypot(r-cx, r-cy) + 0.5
+			if a >= 1 {
+				continue
+			}
+			if a < 0 {
+				a = 0
+			}
+			i := img.PixOffset(x, y)
+			for j := 0; j < 4; j++ {
+				img.Pix[i+j] = uint8(float64(img.Pix[i+j]) * a)
+			}
+		}
+	}
+}
+
+func decodePNGLogo(logo string) (image.Image, error) {
+	const prefix = "data:image/png;base64,"
+	if !strings.HasPrefix(logo, prefix) {
+		return nil, errors.New("the SVG logo is not supported in PNG badges")
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(logo, prefix))
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("invalid badge logo: %w", err)
+	}
+	return img, nil
+}
+
+// hexToColor converts the hex color ( #RGB or #RRGGBB ) to color.Color
+func hexToColor(s string) (color.Color, error) {
+	if !hexColorRe.MatchString(s) {
+		return nil, fmt.Errorf("invalid color: %s", s)
+	}
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %s", s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
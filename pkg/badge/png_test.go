@@ -0,0 +1,86 @@
+package badge
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestRenderPNG(t *testing.T) {
+	tests := []struct {
+		style       string
+		logo        string
+		wantHeight  int
+		wantCornerA uint32
+		wantErr     bool
+	}{
+		{StyleFlat, "", 40, 0, false},
+		{StyleFlatSquare, "", 40, 0xffff, false},
+		{StyleForTheBadge, "", 56, 0xffff, false},
+		{StyleFlat, "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAgAAAAICAYAAADED76LAAAAE0lEQVR4nGP4f53hPz7MMDIUAACWW7VBoC97+QAAAABJRU5ErkJggg==", 40, 0, false},
+		{StyleFlat, "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=", 0, 0, true},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "80.0%")
+		b.Style = tt.style
+		b.Logo = tt.logo
+		buf := new(bytes.Buffer)
+		if err := b.RenderPNG(buf); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.style, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want err", tt.style)
+			continue
+		}
+		l, err := b.layout()
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := img.Bounds().Dy(); got != tt.wantHeight {
+			t.Errorf("got %v\nwant %v", got, tt.wantHeight)
+		}
+		if got, want := img.Bounds().Dx(), int((l.labelWidth+l.messageWidth)*pngScale+0.5); got != want {
+			t.Errorf("got %v\nwant %v", got, want)
+		}
+		if _, _, _, a := img.At(0, 0).RGBA(); a != tt.wantCornerA {
+			t.Errorf("%s: got %v\nwant %v", tt.style, a, tt.wantCornerA)
+		}
+	}
+}
+
+func TestHexToColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    [3]uint8
+		wantErr bool
+	}{
+		{"#97CA00", [3]uint8{0x97, 0xca, 0x00}, false},
+		{"#4C1", [3]uint8{0x44, 0xcc, 0x11}, false},
+		{"24292E", [3]uint8{0x24, 0x29, 0x2e}, false},
+		{"green", [3]uint8{}, true},
+	}
+	for _, tt := range tests {
+		got, err := hexToColor(tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.in, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want err", tt.in)
+			continue
+		}
+		r, g, b, _ := got.RGBA()
+		if [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)} != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
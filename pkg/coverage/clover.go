@@ -12,6 +12,9 @@ var _ Processor = (*Clover)(nil)
 
 const CloverDefaultPath = "coverage.xml"
 
+// CloverPHPUnitDefaultPath is the default path of Clover XML report of PHPUnit ( --coverage-clover clover.xml )
+const CloverPHPUnitDefaultPath = "clover.xml"
+
 type Clover struct{}
 
 type CloverReport struct {
@@ -21,9 +24,10 @@ type CloverReport struct {
 }
 
 type CloverReportProject struct {
-	Timestamp string             `xml:"timestamp,attr"`
-	File      []CloverReportFile `xml:"file"`
-	Metrics   struct {
+	Timestamp string                `xml:"timestamp,attr"`
+	File      []CloverReportFile    `xml:"file"`
+	Package   []CloverReportPackage `xml:"package"`
+	Metrics   *struct {
 		Files               int `xml:"files,attr"`
 		Loc                 int `xml:"loc,attr"`
 		Ncloc               int `xml:"ncloc,attr"`
@@ -39,10 +43,16 @@ type CloverReportProject struct {
 	} `xml:"metrics"`
 }
 
+// CloverReportPackage is the package ( namespace ) of files. PHPUnit groups the files of namespaced code by the packages.
+type CloverReportPackage struct {
+	Name string             `xml:"name,attr"`
+	File []CloverReportFile `xml:"file"`
+}
+
 type CloverReportFile struct {
 	XMLName xml.Name `xml:"file"`
 	Name    string   `xml:"name,attr"`
-	Metrics struct {
+	Metrics *struct {
 		Loc                 int `xml:"loc,attr"`
 		Ncloc               int `xml:"ncloc,attr"`
 		Classes             int `xml:"classes,attr"`
@@ -109,10 +119,12 @@ func (c *Clover) ParseReport(path string) (*Coverage, string, error) {
 	cov := New()
 	cov.Type = TypeStmt
 	cov.Format = c.Name()
-	for _, f := range r.Project.File {
+	files := append([]CloverReportFile{}, r.Project.File...)
+	for _, p := range r.Project.Package {
+		files = append(files, p.File...)
+	}
+	for _, f := range files {
 		fcov := NewFileCoverage(f.Name)
-		fcov.Covered = f.Metrics.Coveredstatements
-		fcov.Total = f.Metrics.Statements
 		for _, l := range f.Line {
 			if l.Type != "stmt" {
 				continue
@@ -129,10 +141,27 @@ func (c *Clover) ParseReport(path string) (*Coverage, string, error) {
 				Count:     &c,
 			})
 		}
+		if f.Metrics != nil {
+			fcov.Covered = f.Metrics.Coveredstatements
+			fcov.Total = f.Metrics.Statements
+		} else {
+			// accumulate the statements of the file without <metrics>
+			for _, b := range fcov.Blocks {
+				fcov.Total += *b.NumStmt
+				if *b.Count > 0 {
+					fcov.Covered += *b.NumStmt
+				}
+			}
+		}
 		cov.Total += fcov.Total
 		cov.Covered += fcov.Covered
 		cov.Files = append(cov.Files, fcov)
 	}
+	if len(files) == 0 && r.Project.Metrics != nil {
+		// cov.Total is always the sum of the files, so the metrics of the project are used only for the report without files
+		cov.Total = r.Project.Metrics.Statements
+		cov.Covered = r.Project.Metrics.Coveredstatements
+	}
 
 	return cov, rp, nil
 }
@@ -143,7 +172,13 @@ func (c *Clover) detectReportPath(path string) (string, error) {
 		return "", err
 	}
 	if p.IsDir() {
-		path = filepath.Join(path, CloverDefaultPath)
+		// path/to/coverage.xml
+		np := filepath.Join(path, CloverDefaultPath)
+		if _, err := os.Stat(np); err != nil {
+			// path/to/clover.xml
+			np = filepath.Join(path, CloverPHPUnitDefaultPath)
+		}
+		path = np
 	}
 	if _, err := os.Stat(path); err != nil {
 		return "", err
@@ -1,6 +1,7 @@
 package coverage
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -49,6 +50,10 @@ func TestCloverParseAllFormat(t *testing.T) {
 		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
 		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), false},
 		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
 	}
 	for _, tt := range tests {
 		_, _, err := NewClover().ParseReport(tt.path)
@@ -57,3 +62,68 @@ func TestCloverParseAllFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestCloverPHPUnit(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "clover_phpunit")
+	got, rp, err := NewClover().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(path, "clover.xml"); rp != want {
+		t.Errorf("got %v\nwant %v", rp, want)
+	}
+	if want := 3; len(got.Files) != want {
+		t.Errorf("got %v\nwant %v", len(got.Files), want)
+	}
+	// the sum of the files, not the statements of the metrics of the project
+	if want := 7; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 5; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	tests := []struct {
+		file        string
+		wantTotal   int
+		wantCovered int
+	}{
+		{"src/Models/User.php", 4, 3},
+		{"src/Support/helpers.php", 2, 1},
+		{"src/bootstrap.php", 1, 1},
+	}
+	for _, tt := range tests {
+		fc, err := got.Files.FuzzyFindByFile(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fc.Total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", fc.Total, tt.wantTotal)
+		}
+		if fc.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", fc.Covered, tt.wantCovered)
+		}
+	}
+}
+
+func TestCloverProjectMetricsOnly(t *testing.T) {
+	dir := t.TempDir()
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<coverage generated="1640995200">
+  <project timestamp="1640995200">
+    <metrics files="0" loc="30" ncloc="24" classes="1" methods="2" coveredmethods="1" conditionals="0" coveredconditionals="0" statements="8" coveredstatements="5" elements="10" coveredelements="6"/>
+  </project>
+</coverage>`
+	if err := os.WriteFile(filepath.Join(dir, "clover.xml"), []byte(xml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := NewClover().ParseReport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 8; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 5; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+}
@@ -57,7 +57,7 @@ type CoberturaReportPackage struct {
 							Hits   int `xml:"hits,attr"`
 						} `xml:"line"`
 					} `xml:"lines"`
-				}
+				} `xml:"method"`
 			} `xml:"methods"`
 			Lines struct {
 				Line []struct {
@@ -99,6 +99,7 @@ func (c *Cobertura) ParseReport(path string) (*Coverage, string, error) {
 	cov.Format = c.Name()
 
 	flm := map[string]BlockCoverages{}
+	fnm := map[string]FunctionCoverages{}
 	for _, p := range r.Packages.Package {
 		for _, c := range p.Classes.Class {
 			n := c.Filename
@@ -106,6 +107,30 @@ func (c *Cobertura) ParseReport(path string) (*Coverage, string, error) {
 			if !ok {
 				f = BlockCoverages{}
 			}
+			for _, m := range c.Methods.Method {
+				if len(m.Lines.Line) == 0 {
+					continue
+				}
+				fn := &FunctionCoverage{
+					Name:      m.Name,
+					StartLine: m.Lines.Line[0].Number,
+					EndLine:   m.Lines.Line[0].Number,
+					Count:     m.Lines.Line[0].Hits,
+				}
+				for _, l := range m.Lines.Line {
+					if l.Number < fn.StartLine {
+						fn.StartLine = l.Number
+					}
+					if l.Number > fn.EndLine {
+						fn.EndLine = l.Number
+					}
+					fn.Total += 1
+					if l.Hits > 0 {
+						fn.Covered += 1
+					}
+				}
+				fnm[n] = append(fnm[n], fn)
+			}
 			for _, l := range c.Lines.Line {
 				sl := l.Number
 				el := l.Number
@@ -130,6 +155,9 @@ func (c *Cobertura) ParseReport(path string) (*Coverage, string, error) {
 			}
 		}
 		fcov.Blocks = blocks
+		if fns, ok := fnm[f]; ok {
+			fcov.Functions = fns
+		}
 		cov.Total += fcov.Total
 		cov.Covered += fcov.Covered
 		cov.Files = append(cov.Files, fcov)
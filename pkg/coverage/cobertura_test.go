@@ -3,6 +3,8 @@ package coverage
 import (
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestCobertura(t *testing.T) {
@@ -40,6 +42,24 @@ func TestCobertura(t *testing.T) {
 	}
 }
 
+func TestCoberturaFunctions(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "cobertura_methods", "coverage.xml")
+	got, _, err := NewCobertura().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Files) != 1 {
+		t.Fatalf("got %v\nwant %v", len(got.Files), 1)
+	}
+	want := FunctionCoverages{
+		&FunctionCoverage{Name: "add", StartLine: 3, EndLine: 4, Total: 2, Covered: 2, Count: 2},
+		&FunctionCoverage{Name: "sub", StartLine: 7, EndLine: 8, Total: 2, Covered: 1, Count: 1},
+	}
+	if diff := cmp.Diff(got.Files[0].Functions, want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
 func TestCoberturaParseAllFormat(t *testing.T) {
 	tests := []struct {
 		path    string
@@ -50,6 +70,10 @@ func TestCoberturaParseAllFormat(t *testing.T) {
 		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
 		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
 		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), false},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
 	}
 	for _, tt := range tests {
 		_, _, err := NewCobertura().ParseReport(tt.path)
@@ -3,8 +3,12 @@ package coverage
 import (
 	"errors"
 	"fmt"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 type Type string
@@ -23,11 +27,13 @@ type Coverage struct {
 }
 
 type FileCoverage struct {
-	File    string         `json:"file"`
-	Total   int            `json:"total"`
-	Covered int            `json:"covered"`
-	Blocks  BlockCoverages `json:"blocks,omitempty"`
-	cache   map[int]BlockCoverages
+	File      string            `json:"file"`
+	Total     int               `json:"total"`
+	Covered   int               `json:"covered"`
+	Blocks    BlockCoverages    `json:"blocks,omitempty"`
+	Functions FunctionCoverages `json:"functions,omitempty"`
+	cache     map[int]BlockCoverages
+	cacheMu   sync.Mutex
 }
 
 type FileCoverages []*FileCoverage
@@ -44,6 +50,17 @@ type BlockCoverage struct {
 
 type BlockCoverages []*BlockCoverage
 
+type FunctionCoverage struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Total     int    `json:"total"`
+	Covered   int    `json:"covered"`
+	Count     int    `json:"count"`
+}
+
+type FunctionCoverages []*FunctionCoverage
+
 type DiffCoverage struct {
 	A         float64           `json:"a"`
 	B         float64           `json:"b"`
@@ -54,16 +71,29 @@ type DiffCoverage struct {
 }
 
 type DiffFileCoverage struct {
-	File          string        `json:"file"`
-	A             float64       `json:"a"`
-	B             float64       `json:"b"`
-	Diff          float64       `json:"diff"`
-	FileCoverageA *FileCoverage `json:"-"`
-	FileCoverageB *FileCoverage `json:"-"`
+	File          string                `json:"file"`
+	A             float64               `json:"a"`
+	B             float64               `json:"b"`
+	Diff          float64               `json:"diff"`
+	Functions     DiffFunctionCoverages `json:"functions,omitempty"`
+	FileCoverageA *FileCoverage         `json:"-"`
+	FileCoverageB *FileCoverage         `json:"-"`
 }
 
 type DiffFileCoverages []*DiffFileCoverage
 
+type DiffFunctionCoverage struct {
+	File              string            `json:"file"`
+	Name              string            `json:"name"`
+	A                 float64           `json:"a"`
+	B                 float64           `json:"b"`
+	Diff              float64           `json:"diff"`
+	FunctionCoverageA *FunctionCoverage `json:"-"`
+	FunctionCoverageB *FunctionCoverage `json:"-"`
+}
+
+type DiffFunctionCoverages []*DiffFunctionCoverage
+
 type Processor interface {
 	Name() string
 	ParseReport(path string) (*Coverage, string, error)
@@ -91,6 +121,47 @@ func (c *Coverage) FlushBlockCoverages() {
 	}
 }
 
+// FilterByExtensions keeps only the files with the include extensions ( all when empty ) and without the exclude extensions, and recalculates the totals
+func (c *Coverage) FilterByExtensions(include, exclude []string) error {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	files := FileCoverages{}
+	total, covered := 0, 0
+	for _, f := range c.Files {
+		if len(include) > 0 && !matchExtension(include, f.File) {
+			continue
+		}
+		if matchExtension(exclude, f.File) {
+			continue
+		}
+		files = append(files, f)
+		total += f.Total
+		covered += f.Covered
+	}
+	if len(files) == 0 {
+		return errors.New("all files in the coverage report are filtered out by extensions")
+	}
+	c.Files = files
+	c.Total = total
+	c.Covered = covered
+	return nil
+}
+
+// matchExtension reports whether the file ends with any of the extensions ( multi-part extensions such as `.pb.go` are allowed )
+func matchExtension(exts []string, file string) bool {
+	file = strings.ToLower(file)
+	for _, e := range exts {
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		if strings.HasSuffix(file, strings.ToLower(e)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Coverage) Compare(c2 *Coverage) *DiffCoverage {
 	d := &DiffCoverage{
 		CoverageA: c,
@@ -133,20 +204,139 @@ func (c *Coverage) Compare(c2 *Coverage) *DiffCoverage {
 		}
 	}
 	for _, dfc := range m {
+		compareFile(dfc)
+		d.Files = append(d.Files, dfc)
+	}
+
+	return d
+}
+
+// FollowRename merges the file only in the previous coverage ( previous ) into the file only in the current coverage ( file ),
+// so that the renamed file is compared with the file before renaming. It reports whether the files are merged.
+func (d *DiffCoverage) FollowRename(previous, file string) bool {
+	var from, to *DiffFileCoverage
+	for _, dfc := range d.Files {
+		switch {
+		case from == nil && dfc.FileCoverageB == nil && MatchFile(dfc.File, previous):
+			from = dfc
+		case to == nil && dfc.FileCoverageA == nil && MatchFile(dfc.File, file):
+			to = dfc
+		}
+	}
+	if from == nil || to == nil {
+		return false
+	}
+	to.FileCoverageA = from.FileCoverageA
+	compareFile(to)
+	files := DiffFileCoverages{}
+	for _, dfc := range d.Files {
+		if dfc != from {
+			files = append(files, dfc)
+		}
+	}
+	d.Files = files
+	return true
+}
+
+func compareFile(dfc *DiffFileCoverage) {
+	var coverA, coverB float64
+	if dfc.FileCoverageA != nil && dfc.FileCoverageA.Total != 0 {
+		coverA = float64(dfc.FileCoverageA.Covered) / float64(dfc.FileCoverageA.Total) * 100
+	}
+	if dfc.FileCoverageB != nil && dfc.FileCoverageB.Total != 0 {
+		coverB = float64(dfc.FileCoverageB.Covered) / float64(dfc.FileCoverageB.Total) * 100
+	}
+	dfc.A = coverA
+	dfc.B = coverB
+	dfc.Diff = coverB - coverA
+	dfc.Functions = compareFunctions(dfc)
+}
+
+// RegressedFunctions returns the functions whose coverage decreased, the functions removed from the files that still exist,
+// and the new functions that are not covered at all
+func (d *DiffCoverage) RegressedFunctions() DiffFunctionCoverages {
+	regressed := DiffFunctionCoverages{}
+	for _, dfc := range d.Files {
+		for _, dfn := range dfc.Functions {
+			switch {
+			case dfn.FunctionCoverageA != nil && dfn.FunctionCoverageB != nil:
+				if dfn.Diff < 0 {
+					regressed = append(regressed, dfn)
+				}
+			case dfn.FunctionCoverageA != nil:
+				// the functions of the removed files are noted as the removed files
+				if dfc.FileCoverageB != nil {
+					regressed = append(regressed, dfn)
+				}
+			case dfn.FunctionCoverageB != nil:
+				if dfn.FunctionCoverageB.CoveragePercent() == 0 {
+					regressed = append(regressed, dfn)
+				}
+			}
+		}
+	}
+	sort.SliceStable(regressed, func(i, j int) bool {
+		if regressed[i].File != regressed[j].File {
+			return regressed[i].File < regressed[j].File
+		}
+		return regressed[i].Name < regressed[j].Name
+	})
+	return regressed
+}
+
+func compareFunctions(dfc *DiffFileCoverage) DiffFunctionCoverages {
+	var dfns DiffFunctionCoverages
+	m := map[string]*DiffFunctionCoverage{}
+	if dfc.FileCoverageA != nil {
+		for _, fn := range dfc.FileCoverageA.Functions {
+			dfn := &DiffFunctionCoverage{
+				File:              dfc.File,
+				Name:              fn.Name,
+				FunctionCoverageA: fn,
+			}
+			m[fn.Name] = dfn
+			dfns = append(dfns, dfn)
+		}
+	}
+	if dfc.FileCoverageB != nil {
+		for _, fn := range dfc.FileCoverageB.Functions {
+			dfn, ok := m[fn.Name]
+			if ok {
+				dfn.FunctionCoverageB = fn
+				continue
+			}
+			dfn = &DiffFunctionCoverage{
+				File:              dfc.File,
+				Name:              fn.Name,
+				FunctionCoverageB: fn,
+			}
+			m[fn.Name] = dfn
+			dfns = append(dfns, dfn)
+		}
+	}
+	for _, dfn := range dfns {
 		var coverA, coverB float64
-		if dfc.FileCoverageA != nil && dfc.FileCoverageA.Total != 0 {
-			coverA = float64(dfc.FileCoverageA.Covered) / float64(dfc.FileCoverageA.Total) * 100
+		if dfn.FunctionCoverageA != nil {
+			coverA = dfn.FunctionCoverageA.CoveragePercent()
 		}
-		if dfc.FileCoverageB != nil && dfc.FileCoverageB.Total != 0 {
-			coverB = float64(dfc.FileCoverageB.Covered) / float64(dfc.FileCoverageB.Total) * 100
+		if dfn.FunctionCoverageB != nil {
+			coverB = dfn.FunctionCoverageB.CoveragePercent()
 		}
-		dfc.A = coverA
-		dfc.B = coverB
-		dfc.Diff = coverB - coverA
-		d.Files = append(d.Files, dfc)
+		dfn.A = coverA
+		dfn.B = coverB
+		dfn.Diff = coverB - coverA
 	}
+	return dfns
+}
 
-	return d
+func (fn *FunctionCoverage) CoveragePercent() float64 {
+	if fn.Total == 0 {
+		if fn.Count > 0 {
+			return 100.0
+		}
+		return 0.0
+	}
+	return float64(fn.Covered) / float64(fn.Total) * 100
 }
 
 func (fcs FileCoverages) FindByFile(file string) (*FileCoverage, error) {
@@ -164,6 +354,12 @@ func (fcs FileCoverages) FuzzyFindByFile(file string) (*FileCoverage, error) {
 			return fc, nil
 		}
 	}
+	// the file is a path that has the file of the coverage report as its trailing part ( e.g. src/main/java/com/example/Foo.java of com/example/Foo.java )
+	for _, fc := range fcs {
+		if strings.HasSuffix(filepath.ToSlash(file), "/"+strings.TrimLeft(fc.File, "./")) {
+			return fc, nil
+		}
+	}
 	return nil, fmt.Errorf("file name not found: %s", file)
 }
 
@@ -203,6 +399,8 @@ func (fc *FileCoverage) FindBlocksByLine(n int) BlockCoverages {
 	if fc == nil {
 		return BlockCoverages{}
 	}
+	fc.cacheMu.Lock()
+	defer fc.cacheMu.Unlock()
 	if len(fc.cache) == 0 {
 		fc.cache = map[int]BlockCoverages{}
 		for _, b := range fc.Blocks {
@@ -223,6 +421,38 @@ func (fc *FileCoverage) FindBlocksByLine(n int) BlockCoverages {
 	}
 }
 
+type LineCoverage struct {
+	Line int `json:"line"`
+	// the max count of the blocks on the line
+	Count int `json:"count"`
+}
+
+// LineCoverages returns the hit count of each line in the blocks in line order
+func (fc *FileCoverage) LineCoverages() []*LineCoverage {
+	lcs := []*LineCoverage{}
+	if fc == nil {
+		return lcs
+	}
+	counts := map[int]int{}
+	for _, b := range fc.Blocks {
+		if b.StartLine == nil || b.EndLine == nil || b.Count == nil {
+			continue
+		}
+		for i := *b.StartLine; i <= *b.EndLine; i++ {
+			if c, ok := counts[i]; !ok || c < *b.Count {
+				counts[i] = *b.Count
+			}
+		}
+	}
+	for l, c := range counts {
+		lcs = append(lcs, &LineCoverage{Line: l, Count: c})
+	}
+	sort.Slice(lcs, func(i, j int) bool {
+		return lcs[i].Line < lcs[j].Line
+	})
+	return lcs
+}
+
 func (dfcs DiffFileCoverages) FuzzyFindByFile(file string) (*DiffFileCoverage, error) {
 	for _, dfc := range dfcs {
 		if strings.Contains(strings.TrimLeft(dfc.File, "./"), strings.TrimLeft(file, "./")) {
@@ -231,3 +461,283 @@ func (dfcs DiffFileCoverages) FuzzyFindByFile(file string) (*DiffFileCoverage, e
 	}
 	return nil, fmt.Errorf("file name not found: %s", file)
 }
+
+// MatchFile reports whether the path of the file in the coverage report is the file or ends with the file ( e.g. github.com/owner/repo/config/yaml.go of config/yaml.go )
+func MatchFile(path, file string) bool {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	file = strings.TrimPrefix(filepath.ToSlash(file), "./")
+	return path == file || strings.HasSuffix(path, "/"+file)
+}
+
+// TrimRoot makes the absolute file paths under the root relative to the root
+func (c *Coverage) TrimRoot(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	for _, fc := range c.Files {
+		if !filepath.IsAbs(fc.File) {
+			continue
+		}
+		rel, err := filepath.Rel(root, fc.File)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		fc.File = filepath.ToSlash(rel)
+	}
+	return nil
+}
+
+// Merge merges the other coverage ( e.g. the coverage of another test shard ) into the coverage.
+// The counts of the same blocks are summed, and the totals are recalculated from the merged blocks so that the shared lines are not counted twice.
+func (c *Coverage) Merge(other *Coverage) error {
+	if other == nil {
+		return nil
+	}
+	if c.Type == "" && c.Format == "" && len(c.Files) == 0 {
+		c.Type = other.Type
+		c.Format = other.Format
+	}
+	if c.Type != other.Type {
+		return fmt.Errorf("can not merge coverage of different types: %s and %s", c.Type, other.Type)
+	}
+	if c.Format != other.Format {
+		return fmt.Errorf("can not merge coverage of different formats: %s and %s", c.Format, other.Format)
+	}
+	files := map[string]*FileCoverage{}
+	for _, fc := range c.Files {
+		files[fc.File] = fc
+	}
+	for _, ofc := range other.Files {
+		fc, ok := files[ofc.File]
+		if !ok {
+			// copy the file so that merging the other coverages does not change the other coverage
+			fc := ofc.copy()
+			c.Files = append(c.Files, fc)
+			files[fc.File] = fc
+			continue
+		}
+		fc.merge(ofc)
+	}
+	c.Total = 0
+	c.Covered = 0
+	for _, fc := range c.Files {
+		c.Total += fc.Total
+		c.Covered += fc.Covered
+	}
+	return nil
+}
+
+func (fc *FileCoverage) copy() *FileCoverage {
+	c := NewFileCoverage(fc.File)
+	c.Total = fc.Total
+	c.Covered = fc.Covered
+	for _, b := range fc.Blocks {
+		c.Blocks = append(c.Blocks, b.copy())
+	}
+	for _, fn := range fc.Functions {
+		f := *fn
+		c.Functions = append(c.Functions, &f)
+	}
+	return c
+}
+
+func (b *BlockCoverage) copy() *BlockCoverage {
+	c := &BlockCoverage{Type: b.Type}
+	copyInt := func(v *int) *int {
+		if v == nil {
+			return nil
+		}
+		i := *v
+		return &i
+	}
+	c.StartLine = copyInt(b.StartLine)
+	c.StartCol = copyInt(b.StartCol)
+	c.EndLine = copyInt(b.EndLine)
+	c.EndCol = copyInt(b.EndCol)
+	c.NumStmt = copyInt(b.NumStmt)
+	c.Count = copyInt(b.Count)
+	return c
+}
+
+func (fc *FileCoverage) merge(other *FileCoverage) {
+	if len(fc.Blocks) == 0 || len(other.Blocks) == 0 {
+		// the lines are unknown, so the coverage of the file is the larger one
+		if fc.Total < other.Total {
+			fc.Total = other.Total
+		}
+		if fc.Covered < other.Covered {
+			fc.Covered = other.Covered
+		}
+		if fc.Covered > fc.Total {
+			fc.Covered = fc.Total
+		}
+		if len(fc.Blocks) == 0 {
+			for _, ob := range other.Blocks {
+				fc.Blocks = append(fc.Blocks, ob.copy())
+			}
+		}
+		fc.mergeFunctions(other)
+		return
+	}
+	// the blocks of the same range are matched in order of appearance ( a line may have multiple statements )
+	blocks := map[string]BlockCoverages{}
+	for _, b := range fc.Blocks {
+		k := b.key()
+		blocks[k] = append(blocks[k], b)
+	}
+	matched := map[string]int{}
+	for _, ob := range other.Blocks {
+		k := ob.key()
+		i := matched[k]
+		if i >= len(blocks[k]) {
+			fc.Blocks = append(fc.Blocks, ob.copy())
+			continue
+		}
+		matched[k] = i + 1
+		b := blocks[k][i]
+		c := 0
+		if b.Count != nil {
+			c = *b.Count
+		}
+		if ob.Count != nil {
+			c += *ob.Count
+		}
+		b.Count = &c
+	}
+	fc.cacheMu.Lock()
+	fc.cache = map[int]BlockCoverages{}
+	fc.cacheMu.Unlock()
+	fc.Total, fc.Covered = fc.Blocks.count(0, 0)
+	fc.mergeFunctions(other)
+}
+
+func (fc *FileCoverage) mergeFunctions(other *FileCoverage) {
+	for _, ofn := range other.Functions {
+		var fn *FunctionCoverage
+		for _, f := range fc.Functions {
+			if f.Name == ofn.Name {
+				fn = f
+				break
+			}
+		}
+		if fn == nil {
+			fn = &FunctionCoverage{
+				Name:      ofn.Name,
+				StartLine: ofn.StartLine,
+				EndLine:   ofn.EndLine,
+				Total:     ofn.Total,
+				Covered:   ofn.Covered,
+			}
+			fc.Functions = append(fc.Functions, fn)
+		} else if fn.Covered < ofn.Covered {
+			fn.Covered = ofn.Covered
+		}
+		fn.Count += ofn.Count
+		if len(fc.Blocks) > 0 && fn.StartLine > 0 {
+			if total, covered := fc.Blocks.count(fn.StartLine, fn.EndLine); total > 0 {
+				fn.Total, fn.Covered = total, covered
+			}
+		}
+	}
+}
+
+// count counts the total and the covered of the blocks in the lines ( all lines when end is 0 ).
+// Statements are counted by NumStmt, and lines of code are counted once even if the blocks overlap.
+func (bcs BlockCoverages) count(start, end int) (int, int) {
+	var total, covered int
+	lines := map[int]bool{}
+	for _, b := range bcs {
+		if b.StartLine == nil || b.EndLine == nil {
+			continue
+		}
+		if end > 0 && (*b.StartLine < start || *b.StartLine > end) {
+			continue
+		}
+		hit := b.Count != nil && *b.Count > 0
+		if b.Type == TypeLOC {
+			for l := *b.StartLine; l <= *b.EndLine; l++ {
+				lines[l] = lines[l] || hit
+			}
+			continue
+		}
+		ns := 1
+		if b.NumStmt != nil {
+			ns = *b.NumStmt
+		}
+		total += ns
+		if hit {
+			covered += ns
+		}
+	}
+	for _, hit := range lines {
+		total += 1
+		if hit {
+			covered += 1
+		}
+	}
+	return total, covered
+}
+
+func (b *BlockCoverage) key() string {
+	v := func(i *int) string {
+		if i == nil {
+			return "-"
+		}
+		return strconv.Itoa(*i)
+	}
+	return strings.Join([]string{string(b.Type), v(b.StartLine), v(b.StartCol), v(b.EndLine), v(b.EndCol)}, ":")
+}
+
+// PackageCoverage is the coverage of the files grouped by the directory
+type PackageCoverage struct {
+	Package string `json:"package"`
+	Total   int    `json:"total"`
+	Covered int    `json:"covered"`
+	Files   int    `json:"files"`
+}
+
+type PackageCoverages []*PackageCoverage
+
+func (pc *PackageCoverage) CoveragePercent() float64 {
+	if pc.Total == 0 {
+		return 0.0
+	}
+	return float64(pc.Covered) / float64(pc.Total) * 100
+}
+
+// PerPackage groups the files by the directory up to depth ( the whole directory when depth is 0 ) and measures the coverage of each group
+func (c *Coverage) PerPackage(depth int) PackageCoverages {
+	m := map[string]*PackageCoverage{}
+	pcs := PackageCoverages{}
+	for _, fc := range c.Files {
+		name := PackageName(fc.File, depth)
+		pc, ok := m[name]
+		if !ok {
+			pc = &PackageCoverage{Package: name}
+			m[name] = pc
+			pcs = append(pcs, pc)
+		}
+		pc.Total += fc.Total
+		pc.Covered += fc.Covered
+		pc.Files += 1
+	}
+	sort.Slice(pcs, func(i, j int) bool {
+		return pcs[i].Package < pcs[j].Package
+	})
+	return pcs
+}
+
+// PackageName returns the directory of the file up to depth ( e.g. `pkg/coverage` of `pkg/coverage/gocover.go` when depth is 2 ).
+// It returns the whole directory when depth is 0.
+func PackageName(file string, depth int) string {
+	dir := path.Dir(strings.TrimPrefix(filepath.ToSlash(file), "./"))
+	if dir == "." {
+		return "."
+	}
+	elems := strings.Split(dir, "/")
+	if depth > 0 && len(elems) > depth {
+		elems = elems[:depth]
+	}
+	return strings.Join(elems, "/")
+}
@@ -90,6 +90,116 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestRegressedFunctions(t *testing.T) {
+	a := &Coverage{
+		Files: FileCoverages{
+			&FileCoverage{File: "file_a.js", Functions: FunctionCoverages{
+				&FunctionCoverage{Name: "add", Total: 4, Covered: 4},
+				&FunctionCoverage{Name: "sub", Total: 4, Covered: 2},
+				&FunctionCoverage{Name: "removed", Total: 4, Covered: 4},
+			}},
+			&FileCoverage{File: "file_removed.js", Functions: FunctionCoverages{
+				&FunctionCoverage{Name: "div", Total: 4, Covered: 4},
+			}},
+		},
+	}
+	b := &Coverage{
+		Files: FileCoverages{
+			&FileCoverage{File: "file_a.js", Functions: FunctionCoverages{
+				&FunctionCoverage{Name: "add", Total: 4, Covered: 2},
+				&FunctionCoverage{Name: "sub", Total: 4, Covered: 3},
+				&FunctionCoverage{Name: "added", Total: 4, Covered: 0},
+				&FunctionCoverage{Name: "covered", Total: 4, Covered: 1},
+			}},
+		},
+	}
+	got := a.Compare(b).RegressedFunctions()
+	want := DiffFunctionCoverages{
+		&DiffFunctionCoverage{File: "file_a.js", Name: "add", A: 100.0, B: 50.0, Diff: -50.0},
+		&DiffFunctionCoverage{File: "file_a.js", Name: "added", A: 0.0, B: 0.0, Diff: 0.0},
+		&DiffFunctionCoverage{File: "file_a.js", Name: "removed", A: 100.0, B: 0.0, Diff: -100.0},
+	}
+	opts := []cmp.Option{
+		cmpopts.IgnoreFields(DiffFunctionCoverage{}, "FunctionCoverageA", "FunctionCoverageB"),
+	}
+	if diff := cmp.Diff(got, want, opts...); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestMatchFile(t *testing.T) {
+	tests := []struct {
+		path string
+		file string
+		want bool
+	}{
+		{"config/yaml.go", "config/yaml.go", true},
+		{"./config/yaml.go", "config/yaml.go", true},
+		{"github.com/owner/repo/config/yaml.go", "config/yaml.go", true},
+		{"github.com/owner/repo/config/yaml.go", "yaml.go", true},
+		{"github.com/owner/repo/config/yaml.go", "ml.go", false},
+		{"config/yaml_test.go", "config/yaml.go", false},
+		{"config/yaml.go", "github.com/owner/repo/config/yaml.go", false},
+		{".github/config.go", "config.go", true},
+		{".github/config.go", "github/config.go", false},
+	}
+	for _, tt := range tests {
+		if got := MatchFile(tt.path, tt.file); got != tt.want {
+			t.Errorf("%s %s: got %v\nwant %v", tt.path, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestFollowRename(t *testing.T) {
+	a := &Coverage{
+		Files: FileCoverages{
+			{File: "old.go", Total: 10, Covered: 5},
+			{File: "removed.go", Total: 10, Covered: 0},
+		},
+	}
+	b := &Coverage{
+		Files: FileCoverages{
+			{File: "new.go", Total: 10, Covered: 8},
+		},
+	}
+	tests := []struct {
+		previous string
+		file     string
+		want     bool
+		wantDiff float64
+	}{
+		{"old.go", "new.go", true, 30.0},
+		{"notfound.go", "new.go", false, 0},
+		{"old.go", "notfound.go", false, 0},
+	}
+	for _, tt := range tests {
+		d := a.Compare(b)
+		got := d.FollowRename(tt.previous, tt.file)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if !got {
+			if len(d.Files) != 3 {
+				t.Errorf("got %v\nwant %v", len(d.Files), 3)
+			}
+			continue
+		}
+		if len(d.Files) != 2 {
+			t.Errorf("got %v\nwant %v", len(d.Files), 2)
+		}
+		dfc, err := d.Files.FuzzyFindByFile(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dfc.FileCoverageA == nil || dfc.FileCoverageA.File != tt.previous {
+			t.Errorf("got %v\nwant %v", dfc.FileCoverageA, tt.previous)
+		}
+		if dfc.Diff != tt.wantDiff {
+			t.Errorf("got %v\nwant %v", dfc.Diff, tt.wantDiff)
+		}
+	}
+}
+
 func TestPathPrefix(t *testing.T) {
 	tests := []struct {
 		files FileCoverages
@@ -140,3 +250,259 @@ func TestPathPrefix(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterByExtensions(t *testing.T) {
+	tests := []struct {
+		include     []string
+		exclude     []string
+		wantFiles   int
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{nil, nil, 4, 140, 74, false},
+		{[]string{".go"}, nil, 2, 100, 54, false},
+		{[]string{"go", "TS"}, nil, 3, 120, 64, false},
+		{nil, []string{".json"}, 3, 120, 64, false},
+		{[]string{".go"}, []string{".pb.go"}, 1, 60, 39, false},
+		{[]string{".go"}, []string{".go"}, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		c := &Coverage{
+			Total:   140,
+			Covered: 74,
+			Files: FileCoverages{
+				&FileCoverage{File: "file_a.go", Total: 60, Covered: 39},
+				&FileCoverage{File: "file_b.pb.go", Total: 40, Covered: 15},
+				&FileCoverage{File: "web/index.ts", Total: 20, Covered: 10},
+				&FileCoverage{File: "gen/schema.json", Total: 20, Covered: 10},
+			},
+		}
+		if err := c.FilterByExtensions(tt.include, tt.exclude); err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Error("want err")
+			continue
+		}
+		if len(c.Files) != tt.wantFiles {
+			t.Errorf("got %v\nwant %v", len(c.Files), tt.wantFiles)
+		}
+		if c.Total != tt.wantTotal || c.Covered != tt.wantCovered {
+			t.Errorf("got %v/%v\nwant %v/%v", c.Covered, c.Total, tt.wantCovered, tt.wantTotal)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	i := func(v int) *int { return &v }
+	stmt := func(sl, el, ns, c int) *BlockCoverage {
+		return &BlockCoverage{Type: TypeStmt, StartLine: i(sl), StartCol: i(1), EndLine: i(el), EndCol: i(2), NumStmt: i(ns), Count: i(c)}
+	}
+	loc := func(l, c int) *BlockCoverage {
+		return &BlockCoverage{Type: TypeLOC, StartLine: i(l), EndLine: i(l), Count: i(c)}
+	}
+	tests := []struct {
+		a           *Coverage
+		b           *Coverage
+		wantTotal   int
+		wantCovered int
+		wantFiles   int
+		wantErr     bool
+	}{
+		{
+			&Coverage{Type: TypeStmt, Format: "gocover", Total: 3, Covered: 2, Files: FileCoverages{
+				{File: "a.go", Total: 3, Covered: 2, Blocks: BlockCoverages{stmt(1, 3, 2, 1), stmt(4, 5, 1, 0)}},
+			}},
+			&Coverage{Type: TypeStmt, Format: "gocover", Total: 4, Covered: 2, Files: FileCoverages{
+				{File: "a.go", Total: 3, Covered: 1, Blocks: BlockCoverages{stmt(1, 3, 2, 0), stmt(4, 5, 1, 3)}},
+				{File: "b.go", Total: 1, Covered: 1, Blocks: BlockCoverages{stmt(1, 1, 1, 1)}},
+			}},
+			4, 4, 2, false,
+		},
+		{
+			&Coverage{Type: TypeLOC, Format: "LCOV", Total: 2, Covered: 1, Files: FileCoverages{
+				{File: "a.js", Total: 2, Covered: 1, Blocks: BlockCoverages{loc(1, 1), loc(2, 0)}},
+			}},
+			&Coverage{Type: TypeLOC, Format: "LCOV", Total: 2, Covered: 1, Files: FileCoverages{
+				{File: "a.js", Total: 2, Covered: 1, Blocks: BlockCoverages{loc(2, 1), loc(3, 0)}},
+			}},
+			3, 2, 1, false,
+		},
+		{
+			&Coverage{Type: TypeLOC, Format: "LCOV", Total: 10, Covered: 5, Files: FileCoverages{
+				{File: "a.js", Total: 10, Covered: 5},
+			}},
+			&Coverage{Type: TypeLOC, Format: "LCOV", Total: 10, Covered: 7, Files: FileCoverages{
+				{File: "a.js", Total: 10, Covered: 7},
+			}},
+			10, 7, 1, false,
+		},
+		{
+			&Coverage{Type: TypeStmt, Format: "gocover"},
+			&Coverage{Type: TypeLOC, Format: "LCOV"},
+			0, 0, 0, true,
+		},
+		{
+			New(),
+			&Coverage{Type: TypeLOC, Format: "LCOV", Total: 2, Covered: 1, Files: FileCoverages{
+				{File: "a.js", Total: 2, Covered: 1, Blocks: BlockCoverages{loc(1, 1), loc(2, 0)}},
+			}},
+			2, 1, 1, false,
+		},
+	}
+	for _, tt := range tests {
+		err := tt.a.Merge(tt.b)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if tt.a.Total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", tt.a.Total, tt.wantTotal)
+		}
+		if tt.a.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", tt.a.Covered, tt.wantCovered)
+		}
+		if len(tt.a.Files) != tt.wantFiles {
+			t.Errorf("got %v\nwant %v", len(tt.a.Files), tt.wantFiles)
+		}
+	}
+}
+
+func TestMergeBlockCounts(t *testing.T) {
+	i := func(v int) *int { return &v }
+	a := &Coverage{Type: TypeStmt, Format: "Istanbul", Files: FileCoverages{
+		{File: "a.js", Blocks: BlockCoverages{
+			{Type: TypeStmt, StartLine: i(1), EndLine: i(1), NumStmt: i(1), Count: i(1)},
+			{Type: TypeStmt, StartLine: i(1), EndLine: i(1), NumStmt: i(1), Count: i(0)},
+		}},
+	}}
+	b := &Coverage{Type: TypeStmt, Format: "Istanbul", Files: FileCoverages{
+		{File: "a.js", Blocks: BlockCoverages{
+			{Type: TypeStmt, StartLine: i(1), EndLine: i(1), NumStmt: i(1), Count: i(2)},
+			{Type: TypeStmt, StartLine: i(1), EndLine: i(1), NumStmt: i(1), Count: i(0)},
+		}},
+	}}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	got := []int{}
+	for _, b := range a.Files[0].Blocks {
+		got = append(got, *b.Count)
+	}
+	if diff := cmp.Diff(got, []int{3, 0}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if a.Total != 2 || a.Covered != 1 {
+		t.Errorf("got %v/%v\nwant %v/%v", a.Covered, a.Total, 1, 2)
+	}
+
+	// the merged coverages are not changed by merging the others
+	merged := New()
+	for _, c := range []*Coverage{a, b} {
+		if err := merged.Merge(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := *a.Files[0].Blocks[0].Count; got != 3 {
+		t.Errorf("got %v\nwant %v", got, 3)
+	}
+	if got := *merged.Files[0].Blocks[0].Count; got != 5 {
+		t.Errorf("got %v\nwant %v", got, 5)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		file  string
+		depth int
+		want  string
+	}{
+		{"main.go", 1, "."},
+		{"./main.go", 1, "."},
+		{"config/yaml.go", 1, "config"},
+		{"pkg/coverage/gocover.go", 1, "pkg"},
+		{"pkg/coverage/gocover.go", 2, "pkg/coverage"},
+		{"pkg/coverage/gocover.go", 3, "pkg/coverage"},
+		{"pkg/coverage/gocover.go", 0, "pkg/coverage"},
+		{"github.com/owner/repo/pkg/a.go", 0, "github.com/owner/repo/pkg"},
+	}
+	for _, tt := range tests {
+		if got := PackageName(tt.file, tt.depth); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestPerPackage(t *testing.T) {
+	c := &Coverage{
+		Files: FileCoverages{
+			&FileCoverage{File: "main.go", Total: 10, Covered: 5},
+			&FileCoverage{File: "pkg/a/a.go", Total: 10, Covered: 10},
+			&FileCoverage{File: "pkg/a/b.go", Total: 10, Covered: 0},
+			&FileCoverage{File: "pkg/b/c.go", Total: 20, Covered: 15},
+		},
+	}
+	tests := []struct {
+		depth int
+		want  PackageCoverages
+	}{
+		{0, PackageCoverages{
+			{Package: ".", Total: 10, Covered: 5, Files: 1},
+			{Package: "pkg/a", Total: 20, Covered: 10, Files: 2},
+			{Package: "pkg/b", Total: 20, Covered: 15, Files: 1},
+		}},
+		{1, PackageCoverages{
+			{Package: ".", Total: 10, Covered: 5, Files: 1},
+			{Package: "pkg", Total: 40, Covered: 25, Files: 3},
+		}},
+	}
+	for _, tt := range tests {
+		got := c.PerPackage(tt.depth)
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestLineCoverages(t *testing.T) {
+	i := func(v int) *int { return &v }
+	tests := []struct {
+		fc   *FileCoverage
+		want []*LineCoverage
+	}{
+		{nil, []*LineCoverage{}},
+		{&FileCoverage{File: "a.go"}, []*LineCoverage{}},
+		{&FileCoverage{File: "a.go", Blocks: BlockCoverages{
+			{Type: TypeStmt, StartLine: i(5), EndLine: i(7), NumStmt: i(2), Count: i(0)},
+			{Type: TypeStmt, StartLine: i(2), EndLine: i(3), NumStmt: i(1), Count: i(4)},
+			{Type: TypeStmt, StartLine: i(7), EndLine: i(8), NumStmt: i(1), Count: i(1)},
+		}}, []*LineCoverage{
+			{Line: 2, Count: 4},
+			{Line: 3, Count: 4},
+			{Line: 5, Count: 0},
+			{Line: 6, Count: 0},
+			{Line: 7, Count: 1},
+			{Line: 8, Count: 1},
+		}},
+		{&FileCoverage{File: "a.py", Blocks: BlockCoverages{
+			{Type: TypeLOC, StartLine: i(1), EndLine: i(1), Count: i(1)},
+			{Type: TypeLOC, StartLine: i(3), EndLine: i(3), Count: i(0)},
+		}}, []*LineCoverage{
+			{Line: 1, Count: 1},
+			{Line: 3, Count: 0},
+		}},
+	}
+	for _, tt := range tests {
+		got := tt.fc.LineCoverages()
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package coverage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+var _ Processor = (*CoveragePy)(nil)
+
+const CoveragePyDefaultPath = "coverage.json"
+
+// CoveragePy is the parser of coverage.py JSON report ( `coverage json` ).
+// coverage.py XML report ( `coverage xml` ) is Cobertura format.
+type CoveragePy struct{}
+
+type CoveragePyReport struct {
+	Meta   *CoveragePyReportMeta             `json:"meta"`
+	Files  map[string]CoveragePyFileCoverage `json:"files"`
+	Totals *CoveragePySummary                `json:"totals"`
+}
+
+type CoveragePyReportMeta struct {
+	Version   string `json:"version"`
+	Timestamp string `json:"timestamp"`
+}
+
+type CoveragePyFileCoverage struct {
+	ExecutedLines []int              `json:"executed_lines"`
+	MissingLines  []int              `json:"missing_lines"`
+	Summary       *CoveragePySummary `json:"summary"`
+}
+
+type CoveragePySummary struct {
+	CoveredLines  int `json:"covered_lines"`
+	NumStatements int `json:"num_statements"`
+}
+
+func NewCoveragePy() *CoveragePy {
+	return &CoveragePy{}
+}
+
+func (c *CoveragePy) Name() string {
+	return "coverage.py"
+}
+
+func (c *CoveragePy) ParseReport(path string) (*Coverage, string, error) {
+	rp, err := c.detectReportPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return nil, "", err
+	}
+	r := CoveragePyReport{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, "", err
+	}
+	// coverage.py writes the top-level "meta"
+	if r.Meta == nil || r.Files == nil {
+		return nil, "", fmt.Errorf("%s is not coverage.py format", filepath.Clean(rp))
+	}
+
+	cov := New()
+	cov.Type = TypeLOC
+	cov.Format = c.Name()
+	for fn, f := range r.Files {
+		fcov := NewFileCoverage(fn)
+		for _, l := range f.ExecutedLines {
+			fcov.Blocks = append(fcov.Blocks, newCoveragePyBlock(l, 1))
+		}
+		for _, l := range f.MissingLines {
+			fcov.Blocks = append(fcov.Blocks, newCoveragePyBlock(l, 0))
+		}
+		sort.Slice(fcov.Blocks, func(i, j int) bool {
+			return *fcov.Blocks[i].StartLine < *fcov.Blocks[j].StartLine
+		})
+		if f.Summary != nil {
+			fcov.Total = f.Summary.NumStatements
+			fcov.Covered = f.Summary.CoveredLines
+		} else {
+			fcov.Total = len(f.ExecutedLines) + len(f.MissingLines)
+			fcov.Covered = len(f.ExecutedLines)
+		}
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+	sort.Slice(cov.Files, func(i, j int) bool {
+		return cov.Files[i].File < cov.Files[j].File
+	})
+	if r.Totals != nil {
+		cov.Total = r.Totals.NumStatements
+		cov.Covered = r.Totals.CoveredLines
+	}
+
+	return cov, rp, nil
+}
+
+func newCoveragePyBlock(line, count int) *BlockCoverage {
+	sl := line
+	el := line
+	return &BlockCoverage{
+		Type:      TypeLOC,
+		StartLine: &sl,
+		EndLine:   &el,
+		Count:     &count,
+	}
+}
+
+func (c *CoveragePy) detectReportPath(path string) (string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if p.IsDir() {
+		path = filepath.Join(path, CoveragePyDefaultPath)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
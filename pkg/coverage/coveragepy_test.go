@@ -0,0 +1,85 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCoveragePy(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "coveragepy")
+	got, _, err := NewCoveragePy().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 8; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 5; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	if want := 3; len(got.Files) != want {
+		t.Errorf("got %v\nwant %v", len(got.Files), want)
+	}
+	for _, f := range got.Files {
+		total := 0
+		covered := 0
+		for _, b := range f.Blocks {
+			total = total + 1
+			if *b.Count > 0 {
+				covered += 1
+			}
+		}
+		if got := f.Total; got != total {
+			t.Errorf("got %v\nwant %v", got, total)
+		}
+		if got := f.Covered; got != covered {
+			t.Errorf("got %v\nwant %v", got, covered)
+		}
+	}
+	fc, err := got.Files.FindByFile("app/calc.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := []int{}
+	for _, b := range fc.Blocks {
+		lines = append(lines, *b.StartLine)
+	}
+	if diff := cmp.Diff(lines, []int{1, 2, 3, 5, 6, 7}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestCoveragePyParseAllFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{filepath.Join(testdataDir(t), "gocover", "coverage.out"), true},
+		{filepath.Join(testdataDir(t), "lcov", "lcov.info"), true},
+		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
+		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), false},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
+	}
+	for _, tt := range tests {
+		_, _, err := NewCoveragePy().ParseReport(tt.path)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseReportCoveragePy(t *testing.T) {
+	got, _, err := ParseReport(filepath.Join(testdataDir(t), "coveragepy", "coverage.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := NewCoveragePy().Name(); got.Format != want {
+		t.Errorf("got %v\nwant %v", got.Format, want)
+	}
+}
@@ -0,0 +1,101 @@
+package coverage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var _ Processor = (*Exec)(nil)
+
+const ExecPrefix = "exec://"
+
+// Exec runs the user command and parses its standard output as the generic line-hits JSON.
+//
+//	{
+//	  "files": [
+//	    {
+//	      "file": "path/to/file.ext",
+//	      "lines": [
+//	        { "line": 1, "count": 3 },
+//	        { "line": 2, "count": 0 }
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// Each line in "lines" is an executable line and "count" is the number of hits.
+type Exec struct{}
+
+type ExecReport struct {
+	Files []ExecReportFile `json:"files"`
+}
+
+type ExecReportFile struct {
+	File  string           `json:"file"`
+	Lines []ExecReportLine `json:"lines"`
+}
+
+type ExecReportLine struct {
+	Line  int `json:"line"`
+	Count int `json:"count"`
+}
+
+func NewExec() *Exec {
+	return &Exec{}
+}
+
+func (e *Exec) Name() string {
+	return "Custom"
+}
+
+func (e *Exec) ParseReport(path string) (*Coverage, string, error) {
+	if !strings.HasPrefix(path, ExecPrefix) {
+		return nil, "", fmt.Errorf("%s is not %s command", path, ExecPrefix)
+	}
+	command := strings.TrimPrefix(path, ExecPrefix)
+	if command == "" {
+		return nil, "", errors.New("command is not set")
+	}
+	c := exec.Command("sh", "-c", command) // #nosec
+	c.Stderr = os.Stderr
+	b, err := c.Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to run %s: %w", command, err)
+	}
+	r := ExecReport{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, "", fmt.Errorf("can not parse output of %s: %w", command, err)
+	}
+	if r.Files == nil {
+		return nil, "", fmt.Errorf("can not parse output of %s: files is not set", command)
+	}
+	cov := New()
+	cov.Type = TypeLOC
+	cov.Format = e.Name()
+	for _, f := range r.Files {
+		fcov := NewFileCoverage(f.File)
+		for _, l := range f.Lines {
+			sl := l.Line
+			el := l.Line
+			c := l.Count
+			fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
+				Type:      TypeLOC,
+				StartLine: &sl,
+				EndLine:   &el,
+				Count:     &c,
+			})
+			fcov.Total += 1
+			if c > 0 {
+				fcov.Covered += 1
+			}
+		}
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+	return cov, path, nil
+}
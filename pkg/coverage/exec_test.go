@@ -0,0 +1,78 @@
+package coverage
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestExec(t *testing.T) {
+	out := `{"files":[{"file":"a.go","lines":[{"line":1,"count":1},{"line":2,"count":0}]},{"file":"b.go","lines":[{"line":1,"count":3}]}]}`
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{fmt.Sprintf("exec://echo '%s'", out), false},
+		{"exec://echo 'invalid'", true},
+		{"exec://exit 1", true},
+		{"exec://", true},
+		{filepath.Join(testdataDir(t), "gocover", "coverage.out"), true},
+	}
+	for _, tt := range tests {
+		got, _, err := NewExec().ParseReport(tt.path)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if want := 3; got.Total != want {
+			t.Errorf("got %v\nwant %v", got.Total, want)
+		}
+		if want := 2; got.Covered != want {
+			t.Errorf("got %v\nwant %v", got.Covered, want)
+		}
+		if want := 2; len(got.Files) != want {
+			t.Errorf("got %v\nwant %v", len(got.Files), want)
+		}
+	}
+}
+
+type fakeProcessor struct{}
+
+func (f *fakeProcessor) Name() string {
+	return "Fake"
+}
+
+func (f *fakeProcessor) ParseReport(path string) (*Coverage, string, error) {
+	cov := New()
+	cov.Format = f.Name()
+	return cov, path, nil
+}
+
+func TestRegisterParser(t *testing.T) {
+	orig := append([]registeredProcessor{}, processors...)
+	defer func() {
+		processors = orig
+	}()
+
+	if _, _, err := ParseReport(filepath.Join(testdataDir(t), "notexist")); err == nil {
+		t.Error("want error")
+	}
+	RegisterParser("fake", &fakeProcessor{})
+	got, _, err := ParseReport(filepath.Join(testdataDir(t), "notexist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Format != "Fake" {
+		t.Errorf("got %v\nwant %v", got.Format, "Fake")
+	}
+	n := len(Processors())
+	RegisterParser("fake", &fakeProcessor{})
+	if len(Processors()) != n {
+		t.Errorf("got %v\nwant %v", len(Processors()), n)
+	}
+}
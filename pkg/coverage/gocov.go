@@ -0,0 +1,195 @@
+package coverage
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+var _ Processor = (*Gocov)(nil)
+
+const GocovDefaultPath = "coverage.json"
+
+// Gocov is the parser of gocov JSON report ( `gocov test` or `gocov convert` )
+type Gocov struct{}
+
+type GocovReport struct {
+	Packages []*GocovPackage `json:"Packages"`
+}
+
+type GocovPackage struct {
+	// import path of the package
+	Name      string           `json:"Name"`
+	Functions []*GocovFunction `json:"Functions"`
+}
+
+type GocovFunction struct {
+	Name string `json:"Name"`
+	// absolute path of the file
+	File string `json:"File"`
+	// byte offsets in the file
+	Start      int               `json:"Start"`
+	End        int               `json:"End"`
+	Statements []*GocovStatement `json:"Statements"`
+}
+
+type GocovStatement struct {
+	// byte offsets in the file
+	Start   int `json:"Start"`
+	End     int `json:"End"`
+	Reached int `json:"Reached"`
+}
+
+func NewGocov() *Gocov {
+	return &Gocov{}
+}
+
+func (g *Gocov) Name() string {
+	return "gocov"
+}
+
+func (g *Gocov) ParseReport(path string) (*Coverage, string, error) {
+	rp, err := g.detectReportPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return nil, "", err
+	}
+	r := GocovReport{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, "", err
+	}
+	if r.Packages == nil {
+		return nil, "", fmt.Errorf("%s is not gocov format", filepath.Clean(rp))
+	}
+
+	cov := New()
+	cov.Type = TypeStmt
+	cov.Format = g.Name()
+	files := map[string]*FileCoverage{}
+	positions := map[string]*gocovPositions{}
+	for _, p := range r.Packages {
+		for _, fn := range p.Functions {
+			// the file is named by the import path of the package like Go coverage profile ( e.g. github.com/owner/repo/pkg/file.go )
+			name := fmt.Sprintf("%s/%s", p.Name, filepath.Base(fn.File))
+			fcov, ok := files[name]
+			if !ok {
+				fcov = NewFileCoverage(name)
+				files[name] = fcov
+			}
+			pos, ok := positions[fn.File]
+			if !ok {
+				pos = newGocovPositions(fn.File, name)
+				positions[fn.File] = pos
+			}
+			fnc := &FunctionCoverage{
+				Name: fn.Name,
+			}
+			fnc.StartLine, _ = pos.position(fn.Start)
+			fnc.EndLine, _ = pos.position(fn.End)
+			for _, s := range fn.Statements {
+				fcov.Total += 1
+				fnc.Total += 1
+				if s.Reached > 0 {
+					fcov.Covered += 1
+					fnc.Covered += 1
+				}
+				if fnc.Count < s.Reached {
+					fnc.Count = s.Reached
+				}
+				if !pos.ok() {
+					// the lines of the statements are unknown without the source file
+					continue
+				}
+				sl, sc := pos.position(s.Start)
+				el, ec := pos.position(s.End)
+				ns := 1
+				c := s.Reached
+				fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
+					Type:      TypeStmt,
+					StartLine: &sl,
+					StartCol:  &sc,
+					EndLine:   &el,
+					EndCol:    &ec,
+					NumStmt:   &ns,
+					Count:     &c,
+				})
+			}
+			if pos.ok() {
+				fcov.Functions = append(fcov.Functions, fnc)
+			}
+		}
+	}
+	for _, fcov := range files {
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+	sort.Slice(cov.Files, func(i, j int) bool {
+		return cov.Files[i].File < cov.Files[j].File
+	})
+
+	return cov, rp, nil
+}
+
+// gocovPositions converts the byte offsets in the source file to the lines and the columns
+type gocovPositions struct {
+	// byte offsets of the line starts
+	lines []int
+}
+
+// newGocovPositions reads the source file ( the absolute path in the report, or the import path under GOPATH )
+func newGocovPositions(file, name string) *gocovPositions {
+	b, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		b, err = os.ReadFile(filepath.Join(build.Default.GOPATH, "src", filepath.FromSlash(name)))
+		if err != nil {
+			return &gocovPositions{}
+		}
+	}
+	lines := []int{0}
+	for i, c := range b {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return &gocovPositions{lines: lines}
+}
+
+func (p *gocovPositions) ok() bool {
+	return len(p.lines) > 0
+}
+
+// position returns the 1-based line and column of the byte offset
+func (p *gocovPositions) position(offset int) (int, int) {
+	if !p.ok() {
+		return 0, 0
+	}
+	i := sort.Search(len(p.lines), func(i int) bool {
+		return p.lines[i] > offset
+	}) - 1
+	if i < 0 {
+		i = 0
+	}
+	return i + 1, offset - p.lines[i] + 1
+}
+
+func (g *Gocov) detectReportPath(path string) (string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if p.IsDir() {
+		path = filepath.Join(path, GocovDefaultPath)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
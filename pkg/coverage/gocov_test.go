@@ -0,0 +1,69 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGocov(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "gocov")
+	got, _, err := NewGocov().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 4; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 3; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	if want := 1; len(got.Files) != want {
+		t.Fatalf("got %v\nwant %v", len(got.Files), want)
+	}
+	fc := got.Files[0]
+	if want := "github.com/owner/repo/calc/calc.go"; fc.File != want {
+		t.Errorf("got %v\nwant %v", fc.File, want)
+	}
+	lines := [][]int{}
+	for _, b := range fc.Blocks {
+		lines = append(lines, []int{*b.StartLine, *b.EndLine, *b.Count})
+	}
+	if diff := cmp.Diff(lines, [][]int{{4, 4, 2}, {8, 10, 1}, {9, 9, 0}, {11, 11, 1}}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	fns := [][]int{}
+	for _, fn := range fc.Functions {
+		fns = append(fns, []int{fn.StartLine, fn.EndLine, fn.Total, fn.Covered})
+	}
+	if diff := cmp.Diff(fns, [][]int{{3, 5, 1, 1}, {7, 12, 3, 2}}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if _, err := got.Files.FuzzyFindByFile("calc/calc.go"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGocovParseAllFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{filepath.Join(testdataDir(t), "gocover", "coverage.out"), true},
+		{filepath.Join(testdataDir(t), "lcov", "lcov.info"), true},
+		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
+		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), false},
+	}
+	for _, tt := range tests {
+		_, _, err := NewGocov().ParseReport(tt.path)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
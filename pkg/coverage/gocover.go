@@ -1,8 +1,14 @@
 package coverage
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"golang.org/x/tools/cover"
 )
@@ -11,6 +17,13 @@ var _ Processor = (*Gocover)(nil)
 
 const GocoverDefaultPath = "coverage.out"
 
+const (
+	gocoverModeSet    = "set"
+	gocoverModeCount  = "count"
+	gocoverModeAtomic = "atomic"
+	gocoverModePrefix = "mode: "
+)
+
 type Gocover struct{}
 
 func NewGocover() *Gocover {
@@ -26,7 +39,7 @@ func (g *Gocover) ParseReport(path string) (*Coverage, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
-	profiles, err := cover.ParseProfiles(rp)
+	profiles, err := g.parseProfiles(rp)
 	if err != nil {
 		return nil, "", err
 	}
@@ -89,3 +102,144 @@ func (g *Gocover) countProfile(p *cover.Profile) (int, int) {
 	}
 	return total, covered
 }
+
+// parseProfiles parses the profile. The profile may be the concatenation of profiles ( e.g. `cat a.out b.out` )
+// produced with different covermodes. Counts of count and atomic modes are summed, and when a set mode profile is mixed,
+// all counts are normalized to set mode ( any hit is 1 ) before merging.
+func (g *Gocover) parseProfiles(path string) ([]*cover.Profile, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	type segment struct {
+		mode  string
+		lines []string
+	}
+	segments := []*segment{}
+	modes := map[string]struct{}{}
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, gocoverModePrefix) {
+			mode := strings.TrimPrefix(line, gocoverModePrefix)
+			switch mode {
+			case gocoverModeSet, gocoverModeCount, gocoverModeAtomic:
+			default:
+				return nil, fmt.Errorf("bad mode line: %v", line)
+			}
+			modes[mode] = struct{}{}
+			segments = append(segments, &segment{mode: mode})
+			continue
+		}
+		if len(segments) == 0 {
+			return nil, fmt.Errorf("bad mode line: %v", line)
+		}
+		if line == "" {
+			continue
+		}
+		segments[len(segments)-1].lines = append(segments[len(segments)-1].lines, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, errors.New("mode line not found")
+	}
+	if len(segments) == 1 {
+		return cover.ParseProfiles(path)
+	}
+
+	mode := gocoverModeCount
+	if _, ok := modes[gocoverModeSet]; ok {
+		mode = gocoverModeSet
+	}
+	if len(modes) > 1 {
+		ms := []string{}
+		for m := range modes {
+			ms = append(ms, m)
+		}
+		sort.Strings(ms)
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: mixing covermodes (%s) in %s: the counts are merged as %s mode\n", strings.Join(ms, ", "), path, mode)
+	}
+	merged := []string{gocoverModePrefix + mode}
+	for _, seg := range segments {
+		for _, l := range seg.lines {
+			if mode == gocoverModeSet {
+				normalized, err := normalizeSetCount(l)
+				if err != nil {
+					return nil, err
+				}
+				l = normalized
+			}
+			merged = append(merged, l)
+		}
+	}
+	tmp, err := os.CreateTemp("", "octocov-gocover")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.WriteString(strings.Join(merged, "\n") + "\n"); err != nil {
+		_ = tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	return cover.ParseProfiles(tmp.Name())
+}
+
+// GocoverMode returns the covermode of the first mode line of the Go coverage profile
+func GocoverMode(path string) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if s.Scan() && strings.HasPrefix(s.Text(), gocoverModePrefix) {
+		return strings.TrimPrefix(s.Text(), gocoverModePrefix), nil
+	}
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("mode line not found")
+}
+
+// ToSetMode normalizes the counts of the blocks and the functions to set mode ( any hit is 1 )
+func (c *Coverage) ToSetMode() {
+	for _, fc := range c.Files {
+		for _, b := range fc.Blocks {
+			if b.Count != nil && *b.Count > 0 {
+				one := 1
+				b.Count = &one
+			}
+		}
+		for _, fn := range fc.Functions {
+			if fn.Count > 0 {
+				fn.Count = 1
+			}
+		}
+	}
+}
+
+// normalizeSetCount replaces the count of the profile line ( the last field ) with 1 if it is hit, otherwise 0
+func normalizeSetCount(line string) (string, error) {
+	i := strings.LastIndex(line, " ")
+	if i < 0 {
+		return "", fmt.Errorf("line %q doesn't match expected format", line)
+	}
+	c, err := strconv.Atoi(line[i+1:])
+	if err != nil {
+		return "", fmt.Errorf("line %q doesn't match expected format: %w", line, err)
+	}
+	if c > 0 {
+		c = 1
+	}
+	return fmt.Sprintf("%s %d", line[:i], c), nil
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestGocover(t *testing.T) {
@@ -50,6 +52,10 @@ func TestGocoverParseAllFormat(t *testing.T) {
 		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
 		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
 		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
 	}
 	for _, tt := range tests {
 		_, _, err := NewGocover().ParseReport(tt.path)
@@ -71,3 +77,50 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestGocoverMergeModes(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantCovered int
+		wantCounts  []int
+	}{
+		{
+			"mode: count\na.go:1.1,2.1 1 2\na.go:3.1,4.1 1 0\nmode: atomic\na.go:1.1,2.1 1 3\na.go:3.1,4.1 1 0\n",
+			1,
+			[]int{5, 0},
+		},
+		{
+			"mode: set\na.go:1.1,2.1 1 1\na.go:3.1,4.1 1 0\nmode: count\na.go:1.1,2.1 1 7\na.go:3.1,4.1 1 4\n",
+			2,
+			[]int{1, 1},
+		},
+		{
+			"mode: count\na.go:1.1,2.1 1 2\na.go:3.1,4.1 1 0\n",
+			1,
+			[]int{2, 0},
+		},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), "coverage.out")
+		if err := os.WriteFile(p, []byte(tt.in), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := NewGocover().ParseReport(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Total != 2 {
+			t.Errorf("got %v\nwant %v", got.Total, 2)
+		}
+		if got.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", got.Covered, tt.wantCovered)
+		}
+		counts := []int{}
+		for _, b := range got.Files[0].Blocks {
+			counts = append(counts, *b.Count)
+		}
+		if diff := cmp.Diff(counts, tt.wantCounts, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package coverage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/goccy/go-json"
+)
+
+var _ Processor = (*Istanbul)(nil)
+
+var IstanbulDefaultPath = []string{"coverage", "coverage-final.json"}
+
+// IstanbulFormat is the format name of Istanbul ( nyc ) JSON report.
+// The file paths of the report are absolute, so they should be normalized by (*Coverage).TrimRoot.
+const IstanbulFormat = "Istanbul"
+
+type Istanbul struct{}
+
+// IstanbulReport is the JSON object keyed by the file path
+type IstanbulReport map[string]IstanbulFileCoverage
+
+type IstanbulFileCoverage struct {
+	Path         string                      `json:"path"`
+	StatementMap map[string]IstanbulLocation `json:"statementMap"`
+	S            map[string]int              `json:"s"`
+	FnMap        map[string]IstanbulFunction `json:"fnMap"`
+	F            map[string]int              `json:"f"`
+}
+
+type IstanbulFunction struct {
+	Name string           `json:"name"`
+	Loc  IstanbulLocation `json:"loc"`
+}
+
+type IstanbulLocation struct {
+	Start IstanbulPosition `json:"start"`
+	End   IstanbulPosition `json:"end"`
+}
+
+type IstanbulPosition struct {
+	Line   int  `json:"line"`
+	Column *int `json:"column"`
+}
+
+func NewIstanbul() *Istanbul {
+	return &Istanbul{}
+}
+
+func (i *Istanbul) Name() string {
+	return IstanbulFormat
+}
+
+func (i *Istanbul) ParseReport(path string) (*Coverage, string, error) {
+	rp, err := i.detectReportPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return nil, "", err
+	}
+	r := IstanbulReport{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, "", err
+	}
+	if len(r) == 0 {
+		return nil, "", fmt.Errorf("%s is not Istanbul format", filepath.Clean(rp))
+	}
+	for _, f := range r {
+		if f.StatementMap == nil || f.S == nil {
+			return nil, "", fmt.Errorf("%s is not Istanbul format", filepath.Clean(rp))
+		}
+	}
+
+	cov := New()
+	cov.Type = TypeStmt
+	cov.Format = i.Name()
+	for fn, f := range r {
+		if f.Path != "" {
+			fn = f.Path
+		}
+		fcov := NewFileCoverage(fn)
+		sids := []string{}
+		for id := range f.StatementMap {
+			sids = append(sids, id)
+		}
+		for _, id := range sortIstanbulIDs(sids) {
+			loc := f.StatementMap[id]
+			c := f.S[id]
+			// the statement is mapped to its start line
+			sl := loc.Start.Line
+			el := loc.Start.Line
+			ns := 1
+			fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
+				Type:      TypeStmt,
+				StartLine: &sl,
+				EndLine:   &el,
+				NumStmt:   &ns,
+				Count:     &c,
+			})
+			fcov.Total += 1
+			if c > 0 {
+				fcov.Covered += 1
+			}
+		}
+		fids := []string{}
+		for id := range f.FnMap {
+			fids = append(fids, id)
+		}
+		for _, id := range sortIstanbulIDs(fids) {
+			fm := f.FnMap[id]
+			fnc := &FunctionCoverage{
+				Name:      fm.Name,
+				StartLine: fm.Loc.Start.Line,
+				EndLine:   fm.Loc.End.Line,
+				Count:     f.F[id],
+			}
+			for _, b := range fcov.Blocks {
+				if *b.StartLine < fnc.StartLine || *b.StartLine > fnc.EndLine {
+					continue
+				}
+				fnc.Total += 1
+				if *b.Count > 0 {
+					fnc.Covered += 1
+				}
+			}
+			fcov.Functions = append(fcov.Functions, fnc)
+		}
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+	sort.Slice(cov.Files, func(i, j int) bool {
+		return cov.Files[i].File < cov.Files[j].File
+	})
+
+	return cov, rp, nil
+}
+
+// sortIstanbulIDs sorts the ids ( "0", "1", ... ) in numerical order
+func sortIstanbulIDs(ids []string) []string {
+	sort.Slice(ids, func(i, j int) bool {
+		a, erra := strconv.Atoi(ids[i])
+		b, errb := strconv.Atoi(ids[j])
+		if erra != nil || errb != nil {
+			return ids[i] < ids[j]
+		}
+		return a < b
+	})
+	return ids
+}
+
+func (i *Istanbul) detectReportPath(path string) (string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if p.IsDir() {
+		// path/to/coverage/coverage-final.json
+		np := filepath.Join(path, IstanbulDefaultPath[0], IstanbulDefaultPath[1])
+		if _, err := os.Stat(np); err != nil {
+			// path/to/coverage-final.json
+			np = filepath.Join(path, IstanbulDefaultPath[1])
+			if _, err := os.Stat(np); err != nil {
+				return "", err
+			}
+		}
+		path = np
+	}
+	return path, nil
+}
@@ -0,0 +1,90 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIstanbul(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "istanbul")
+	got, _, err := NewIstanbul().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 7; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 5; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	if want := 2; len(got.Files) != want {
+		t.Fatalf("got %v\nwant %v", len(got.Files), want)
+	}
+	for _, f := range got.Files {
+		total := 0
+		covered := 0
+		for _, b := range f.Blocks {
+			total = total + *b.NumStmt
+			if *b.Count > 0 {
+				covered += *b.NumStmt
+			}
+		}
+		if got := f.Total; got != total {
+			t.Errorf("got %v\nwant %v", got, total)
+		}
+		if got := f.Covered; got != covered {
+			t.Errorf("got %v\nwant %v", got, covered)
+		}
+	}
+	f := got.Files[0]
+	if want := "/home/user/app/src/index.js"; f.File != want {
+		t.Errorf("got %v\nwant %v", f.File, want)
+	}
+	if want := 2; len(f.Functions) != want {
+		t.Fatalf("got %v\nwant %v", len(f.Functions), want)
+	}
+	if fn := f.Functions[1]; fn.Name != "sub" || fn.Total != 1 || fn.Covered != 0 {
+		t.Errorf("got %v\nwant %v", fn, "sub ( total 1, covered 0 )")
+	}
+}
+
+func TestIstanbulParseAllFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{filepath.Join(testdataDir(t), "gocover", "coverage.out"), true},
+		{filepath.Join(testdataDir(t), "lcov", "lcov.info"), true},
+		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
+		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), false},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
+	}
+	for _, tt := range tests {
+		_, _, err := NewIstanbul().ParseReport(tt.path)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+func TestTrimRoot(t *testing.T) {
+	cov := New()
+	cov.Files = FileCoverages{
+		{File: "/home/user/app/src/index.js"},
+		{File: "/home/user/other/index.js"},
+		{File: "src/rel.js"},
+	}
+	if err := cov.TrimRoot("/home/user/app"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"src/index.js", "/home/user/other/index.js", "src/rel.js"}
+	for i, fc := range cov.Files {
+		if fc.File != want[i] {
+			t.Errorf("got %v\nwant %v", fc.File, want[i])
+		}
+	}
+}
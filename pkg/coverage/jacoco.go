@@ -0,0 +1,173 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var _ Processor = (*Jacoco)(nil)
+
+// JacocoDefaultPaths is the default paths of JaCoCo XML report ( Gradle, Maven and the report itself )
+var JacocoDefaultPaths = []string{
+	filepath.Join("build", "reports", "jacoco", "test", "jacocoTestReport.xml"),
+	filepath.Join("target", "site", "jacoco", "jacoco.xml"),
+	"jacocoTestReport.xml",
+	"jacoco.xml",
+}
+
+const jacocoCounterTypeLine = "LINE"
+
+type Jacoco struct{}
+
+type JacocoReport struct {
+	XMLName  xml.Name              `xml:"report"`
+	Name     string                `xml:"name,attr"`
+	Packages []JacocoReportPackage `xml:"package"`
+	Counters []JacocoReportCounter `xml:"counter"`
+	Groups   []JacocoReportGroup   `xml:"group"`
+	Sessions []JacocoReportSession `xml:"sessioninfo"`
+}
+
+// JacocoReportGroup is the group of packages ( e.g. the module of multi-module projects )
+type JacocoReportGroup struct {
+	Name     string                `xml:"name,attr"`
+	Packages []JacocoReportPackage `xml:"package"`
+	Groups   []JacocoReportGroup   `xml:"group"`
+}
+
+type JacocoReportSession struct {
+	ID    string `xml:"id,attr"`
+	Start int64  `xml:"start,attr"`
+	Dump  int64  `xml:"dump,attr"`
+}
+
+type JacocoReportPackage struct {
+	// slash-separated package name ( e.g. `com/example` )
+	Name        string                   `xml:"name,attr"`
+	Sourcefiles []JacocoReportSourcefile `xml:"sourcefile"`
+	Counters    []JacocoReportCounter    `xml:"counter"`
+}
+
+type JacocoReportSourcefile struct {
+	Name  string `xml:"name,attr"`
+	Lines []struct {
+		Nr int `xml:"nr,attr"`
+		// missed instructions
+		Mi int `xml:"mi,attr"`
+		// covered instructions
+		Ci int `xml:"ci,attr"`
+		// missed branches
+		Mb int `xml:"mb,attr"`
+		// covered branches
+		Cb int `xml:"cb,attr"`
+	} `xml:"line"`
+	Counters []JacocoReportCounter `xml:"counter"`
+}
+
+type JacocoReportCounter struct {
+	Type    string `xml:"type,attr"`
+	Missed  int    `xml:"missed,attr"`
+	Covered int    `xml:"covered,attr"`
+}
+
+func NewJacoco() *Jacoco {
+	return &Jacoco{}
+}
+
+func (j *Jacoco) Name() string {
+	return "JaCoCo"
+}
+
+func (j *Jacoco) ParseReport(path string) (*Coverage, string, error) {
+	rp, err := j.detectReportPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return nil, "", err
+	}
+	r := JacocoReport{}
+	if err := xml.Unmarshal(b, &r); err != nil {
+		return nil, "", err
+	}
+	if len(r.Counters) == 0 {
+		return nil, "", fmt.Errorf("%s is not JaCoCo format", filepath.Clean(rp))
+	}
+
+	cov := New()
+	cov.Type = TypeLOC
+	cov.Format = j.Name()
+	for _, p := range r.packages() {
+		for _, s := range p.Sourcefiles {
+			fcov := NewFileCoverage(jacocoFileName(p.Name, s.Name))
+			for _, c := range s.Counters {
+				if c.Type != jacocoCounterTypeLine {
+					continue
+				}
+				fcov.Total = c.Covered + c.Missed
+				fcov.Covered = c.Covered
+			}
+			for _, l := range s.Lines {
+				sl := l.Nr
+				el := l.Nr
+				c := l.Ci
+				fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
+					Type:      TypeLOC,
+					StartLine: &sl,
+					EndLine:   &el,
+					Count:     &c,
+				})
+			}
+			cov.Total += fcov.Total
+			cov.Covered += fcov.Covered
+			cov.Files = append(cov.Files, fcov)
+		}
+	}
+
+	return cov, rp, nil
+}
+
+// jacocoFileName joins the package name and the source file name.
+// The package name is the directory of the source file relative to the source root ( e.g. src/main/java ).
+func jacocoFileName(pkg, file string) string {
+	pkg = strings.Trim(strings.ReplaceAll(pkg, ".", "/"), "/")
+	if pkg == "" {
+		return file
+	}
+	return pkg + "/" + file
+}
+
+// packages returns the packages of the report including the packages in the groups
+func (r *JacocoReport) packages() []JacocoReportPackage {
+	packages := append([]JacocoReportPackage{}, r.Packages...)
+	var walk func(groups []JacocoReportGroup)
+	walk = func(groups []JacocoReportGroup) {
+		for _, g := range groups {
+			packages = append(packages, g.Packages...)
+			walk(g.Groups)
+		}
+	}
+	walk(r.Groups)
+	return packages
+}
+
+func (j *Jacoco) detectReportPath(path string) (string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !p.IsDir() {
+		return path, nil
+	}
+	for _, dp := range JacocoDefaultPaths {
+		np := filepath.Join(path, dp)
+		if _, err := os.Stat(np); err == nil {
+			return np, nil
+		}
+	}
+	return "", fmt.Errorf("JaCoCo report not found: %s", path)
+}
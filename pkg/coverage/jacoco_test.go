@@ -0,0 +1,85 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestJacoco(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "jacoco")
+	jacoco := NewJacoco()
+	got, _, err := jacoco.ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 7; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 4; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	for _, f := range got.Files {
+		total := 0
+		covered := 0
+		for _, b := range f.Blocks {
+			total = total + 1
+			if *b.Count > 0 {
+				covered += 1
+			}
+		}
+		if got := f.Total; got != total {
+			t.Errorf("got %v\nwant %v", got, total)
+		}
+		if got := f.Covered; got != covered {
+			t.Errorf("got %v\nwant %v", got, covered)
+		}
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"Calculator.java", "com/example/Calculator.java"},
+		{"com/example/util/Strings.java", "com/example/util/Strings.java"},
+		{"src/main/java/com/example/Calculator.java", "com/example/Calculator.java"},
+		{"./src/main/java/com/example/util/Strings.java", "com/example/util/Strings.java"},
+	}
+	for _, tt := range tests {
+		fc, err := got.Files.FuzzyFindByFile(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fc.File != tt.want {
+			t.Errorf("got %v\nwant %v", fc.File, tt.want)
+		}
+	}
+	if _, err := got.Files.FuzzyFindByFile("src/main/java/com/example/Missing.java"); err == nil {
+		t.Error("want error")
+	}
+	if _, err := got.Files.FindByFile("src/main/java/com/example/Calculator.java"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestJacocoParseAllFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{filepath.Join(testdataDir(t), "gocover", "coverage.out"), true},
+		{filepath.Join(testdataDir(t), "lcov", "lcov.info"), true},
+		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
+		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), false},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
+	}
+	for _, tt := range tests {
+		_, _, err := NewJacoco().ParseReport(tt.path)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
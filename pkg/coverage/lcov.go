@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -46,6 +47,7 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 	cov.Format = l.Name()
 	parsed := false
 	blocks := BlockCoverages{}
+	fns := FunctionCoverages{}
 	for scanner.Scan() {
 		l := scanner.Text()
 		if l == "end_of_record" {
@@ -56,6 +58,9 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 			fcov.Total += total
 			fcov.Covered += covered
 			fcov.Blocks = blocks
+			if len(fns) > 0 {
+				fcov.Functions = measureFunctions(fns, blocks)
+			}
 			cov.Total += total
 			cov.Covered += covered
 			cov.Files = append(cov.Files, fcov)
@@ -63,15 +68,52 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 			covered = 0
 			parsed = true
 			blocks = BlockCoverages{}
+			fns = FunctionCoverages{}
 			continue
 		}
-		splitted := strings.Split(l, ":")
+		splitted := strings.SplitN(l, ":", 2)
 		if len(splitted) != 2 {
 			continue
 		}
 		switch splitted[0] {
 		case "SF":
 			fileName = splitted[1]
+		case "FN":
+			// FN:<start line>,<function name> or FN:<start line>,<end line>,<function name>
+			nums := strings.SplitN(splitted[1], ",", 3)
+			if len(nums) < 2 {
+				return nil, "", fmt.Errorf("can not parse: %s", l)
+			}
+			sl, err := strconv.Atoi(nums[0])
+			if err != nil {
+				return nil, "", err
+			}
+			fn := &FunctionCoverage{StartLine: sl}
+			if len(nums) == 3 {
+				if el, err := strconv.Atoi(nums[1]); err == nil {
+					fn.EndLine = el
+					fn.Name = nums[2]
+				} else {
+					fn.Name = strings.Join(nums[1:], ",")
+				}
+			} else {
+				fn.Name = nums[1]
+			}
+			fns = append(fns, fn)
+		case "FNDA":
+			nums := strings.SplitN(splitted[1], ",", 2)
+			if len(nums) != 2 {
+				return nil, "", fmt.Errorf("can not parse: %s", l)
+			}
+			count, err := strconv.Atoi(nums[0])
+			if err != nil {
+				return nil, "", err
+			}
+			for _, fn := range fns {
+				if fn.Name == nums[1] {
+					fn.Count += count
+				}
+			}
 		case "DA":
 			total += 1
 			nums := strings.Split(splitted[1], ",")
@@ -105,6 +147,38 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 	return cov, rp, nil
 }
 
+// measureFunctions counts lines of each function using line blocks.
+// If the end line of a function is unknown, the function is assumed to end just before the next function starts.
+func measureFunctions(fns FunctionCoverages, blocks BlockCoverages) FunctionCoverages {
+	sort.SliceStable(fns, func(i, j int) bool {
+		return fns[i].StartLine < fns[j].StartLine
+	})
+	last := 0
+	for _, b := range blocks {
+		if *b.EndLine > last {
+			last = *b.EndLine
+		}
+	}
+	for i, fn := range fns {
+		if fn.EndLine == 0 {
+			fn.EndLine = last
+			if i+1 < len(fns) && fns[i+1].StartLine > fn.StartLine {
+				fn.EndLine = fns[i+1].StartLine - 1
+			}
+		}
+		for _, b := range blocks {
+			if *b.StartLine < fn.StartLine || *b.StartLine > fn.EndLine {
+				continue
+			}
+			fn.Total += 1
+			if *b.Count > 0 {
+				fn.Covered += 1
+			}
+		}
+	}
+	return fns
+}
+
 func (s *Lcov) detectReportPath(path string) (string, error) {
 	p, err := os.Stat(path)
 	if err != nil {
@@ -3,6 +3,8 @@ package coverage
 import (
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestLcov(t *testing.T) {
@@ -53,6 +55,10 @@ func TestLcovParseAllFormat(t *testing.T) {
 		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), true},
 		{filepath.Join(testdataDir(t), "clover", "coverage.xml"), true},
 		{filepath.Join(testdataDir(t), "cobertura", "coverage.xml"), true},
+		{filepath.Join(testdataDir(t), "jacoco", "jacocoTestReport.xml"), true},
+		{filepath.Join(testdataDir(t), "istanbul", "coverage", "coverage-final.json"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), true},
+		{filepath.Join(testdataDir(t), "gocov", "coverage.json"), true},
 	}
 	for _, tt := range tests {
 		_, _, err := NewLcov().ParseReport(tt.path)
@@ -61,3 +67,22 @@ func TestLcovParseAllFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestLcovFunctions(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "lcov_functions", "lcov.info")
+	got, _, err := NewLcov().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Files) != 1 {
+		t.Fatalf("got %v\nwant %v", len(got.Files), 1)
+	}
+	want := FunctionCoverages{
+		&FunctionCoverage{Name: "add", StartLine: 1, EndLine: 4, Total: 2, Covered: 2, Count: 3},
+		&FunctionCoverage{Name: "sub", StartLine: 5, EndLine: 8, Total: 2, Covered: 1, Count: 1},
+		&FunctionCoverage{Name: "mul", StartLine: 9, EndLine: 10, Total: 1, Covered: 0, Count: 0},
+	}
+	if diff := cmp.Diff(got.Files[0].Functions, want); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
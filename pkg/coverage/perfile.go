@@ -0,0 +1,137 @@
+package coverage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/k1LoW/octocov/internal"
+)
+
+var _ Processor = (*PerFile)(nil)
+
+const PerFilePrefix = "perfile://"
+
+// PerFile reads a directory of JSON files, each of which has the coverage of one source file.
+// The fields are looked up by dot-separated paths ( e.g. `summary.lines.covered` ).
+type PerFile struct {
+	fileField    string
+	coveredField string
+	totalField   string
+}
+
+func NewPerFile(fileField, coveredField, totalField string) *PerFile {
+	if fileField == "" {
+		fileField = "file"
+	}
+	if coveredField == "" {
+		coveredField = "covered"
+	}
+	if totalField == "" {
+		totalField = "total"
+	}
+	return &PerFile{
+		fileField:    fileField,
+		coveredField: coveredField,
+		totalField:   totalField,
+	}
+}
+
+func (p *PerFile) Name() string {
+	return "Per-file JSON"
+}
+
+func (p *PerFile) ParseReport(path string) (*Coverage, string, error) {
+	if !strings.HasPrefix(path, PerFilePrefix) {
+		return nil, "", fmt.Errorf("%s is not %s directory", path, PerFilePrefix)
+	}
+	dir := strings.TrimPrefix(path, PerFilePrefix)
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	if !fi.IsDir() {
+		return nil, "", fmt.Errorf("%s is not directory", dir)
+	}
+	cov := New()
+	cov.Type = TypeLOC
+	cov.Format = p.Name()
+	if err := filepath.WalkDir(dir, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+		b, err := os.ReadFile(filepath.Clean(fp))
+		if err != nil {
+			return err
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return fmt.Errorf("can not parse %s: %w", fp, err)
+		}
+		fcov, err := p.fileCoverage(v)
+		if err != nil {
+			return fmt.Errorf("can not parse %s: %w", fp, err)
+		}
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+	if len(cov.Files) == 0 {
+		return nil, "", fmt.Errorf("per-file JSON not found: %s", dir)
+	}
+	sort.Slice(cov.Files, func(i, j int) bool {
+		return cov.Files[i].File < cov.Files[j].File
+	})
+	return cov, dir, nil
+}
+
+func (p *PerFile) fileCoverage(v interface{}) (*FileCoverage, error) {
+	fv, err := internal.LookupField(v, p.fileField)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := fv.(string)
+	if !ok || file == "" {
+		return nil, fmt.Errorf("%s is not a string", p.fileField)
+	}
+	covered, err := lookupInt(v, p.coveredField)
+	if err != nil {
+		return nil, err
+	}
+	total, err := lookupInt(v, p.totalField)
+	if err != nil {
+		return nil, err
+	}
+	if covered > total {
+		return nil, fmt.Errorf("%s (%d) is greater than %s (%d)", p.coveredField, covered, p.totalField, total)
+	}
+	fcov := NewFileCoverage(file)
+	fcov.Covered = covered
+	fcov.Total = total
+	return fcov, nil
+}
+
+func lookupInt(v interface{}, path string) (int, error) {
+	f, err := internal.LookupField(v, path)
+	if err != nil {
+		return 0, err
+	}
+	switch n := f.(type) {
+	case float64:
+		return int(n), nil
+	case string:
+		return strconv.Atoi(n)
+	}
+	return 0, fmt.Errorf("%s is not a number", path)
+}
@@ -0,0 +1,62 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPerFile(t *testing.T) {
+	dir := filepath.Join(testdataDir(t), "perfile")
+	tests := []struct {
+		path        string
+		p           *PerFile
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{PerFilePrefix + dir, NewPerFile("source.path", "summary.lines.covered", "summary.lines.total"), 15, 9, false},
+		{PerFilePrefix + dir, NewPerFile("", "", ""), 0, 0, true},
+		{PerFilePrefix + dir, NewPerFile("source.path", "summary.lines.total", "summary.lines.covered"), 0, 0, true},
+		{dir, NewPerFile("source.path", "summary.lines.covered", "summary.lines.total"), 0, 0, true},
+		{PerFilePrefix + filepath.Join(testdataDir(t), "gocover"), NewPerFile("", "", ""), 0, 0, true},
+	}
+	for _, tt := range tests {
+		got, _, err := tt.p.ParseReport(tt.path)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got.Total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", got.Total, tt.wantTotal)
+		}
+		if got.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", got.Covered, tt.wantCovered)
+		}
+		if want := "sub/lib.go"; got.Files[1].File != want {
+			t.Errorf("got %v\nwant %v", got.Files[1].File, want)
+		}
+	}
+}
+
+func TestParseReportWithPerFile(t *testing.T) {
+	path := PerFilePrefix + filepath.Join(testdataDir(t), "perfile")
+	if _, _, err := ParseReport(path); err == nil {
+		t.Error("the registered per-file parser should not parse the fields")
+	}
+	got, _, err := ParseReport(path, NewPerFile("source.path", "summary.lines.covered", "summary.lines.total"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 15; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	// the parser passed does not replace the registered parser
+	if _, _, err := ParseReport(path); err == nil {
+		t.Error("the registered per-file parser should not be changed")
+	}
+}
@@ -13,6 +13,9 @@ import (
 
 type Printer struct {
 	fc *FileCoverage
+	// print only the lines not fully covered and the context lines around them
+	uncoveredOnly bool
+	context       int
 }
 
 func NewPrinter(fc *FileCoverage) *Printer {
@@ -21,6 +24,12 @@ func NewPrinter(fc *FileCoverage) *Printer {
 	}
 }
 
+// SetUncoveredOnly sets the printer to print only the lines that have uncovered blocks and `context` lines around them
+func (p *Printer) SetUncoveredOnly(context int) {
+	p.uncoveredOnly = true
+	p.context = context
+}
+
 func (p *Printer) Print(src io.Reader, dest io.Writer) error {
 	r2 := new(bytes.Buffer)
 	r1 := io.TeeReader(src, r2)
@@ -40,21 +49,60 @@ func (p *Printer) Print(src io.Reader, dest io.Writer) error {
 	}
 	w2 := len(strconv.Itoa(c2))
 
+	lines := []string{}
 	scanner := bufio.NewScanner(r2)
-	n := 1
-	cl := color.New(color.FgYellow)
-	cl.EnableColor()
 	for scanner.Scan() {
-		c, out := paintLine(n, w2, scanner.Text(), p.fc.FindBlocksByLine(n))
-		_, _ = fmt.Fprintf(dest, "%s %s %s\n", cl.Sprint(fmt.Sprintf(fmt.Sprintf("%%%dd", w), n)), c, out)
-		n += 1
+		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
 		return err
 	}
+	printed := p.linesToPrint(len(lines))
+
+	cl := color.New(color.FgYellow)
+	cl.EnableColor()
+	prev := 0
+	for i, in := range lines {
+		n := i + 1
+		if printed != nil && !printed[n] {
+			continue
+		}
+		if printed != nil && prev > 0 && n > prev+1 {
+			_, _ = fmt.Fprintln(dest, "--")
+		}
+		c, out := paintLine(n, w2, in, p.fc.FindBlocksByLine(n))
+		_, _ = fmt.Fprintf(dest, "%s %s %s\n", cl.Sprint(fmt.Sprintf(fmt.Sprintf("%%%dd", w), n)), c, out)
+		prev = n
+	}
 	return nil
 }
 
+// linesToPrint returns the line numbers to print in the uncovered only mode. It returns nil when all lines are printed
+func (p *Printer) linesToPrint(c int) map[int]bool {
+	if !p.uncoveredOnly {
+		return nil
+	}
+	printed := map[int]bool{}
+	for n := 1; n <= c; n++ {
+		uncovered := false
+		for _, b := range p.fc.FindBlocksByLine(n) {
+			if *b.Count == 0 {
+				uncovered = true
+				break
+			}
+		}
+		if !uncovered {
+			continue
+		}
+		for i := n - p.context; i <= n+p.context; i++ {
+			if i >= 1 && i <= c {
+				printed[i] = true
+			}
+		}
+	}
+	return printed
+}
+
 func countLines(r io.Reader) (int, error) {
 	buf := make([]byte, 1024)
 	count := 0
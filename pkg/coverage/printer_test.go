@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestPrint(t *testing.T) {
@@ -73,6 +75,62 @@ func IsOK(in string) error {
 	}
 }
 
+func TestPrintUncoveredOnly(t *testing.T) {
+	code := `package coverage
+
+import "fmt"
+
+func IsOK(in string) error {
+	if in != "ok" {
+		return fmt.Errorf("error: %s", in)
+	}
+	return nil
+}
+
+func IsNG(in string) error {
+	return nil
+}
+`
+	blocks := BlockCoverages{
+		newBlockCoverage(TypeLOC, 6, -1, 6, -1, -1, 1),
+		newBlockCoverage(TypeLOC, 7, -1, 7, -1, -1, 0),
+		newBlockCoverage(TypeLOC, 9, -1, 9, -1, -1, 1),
+		newBlockCoverage(TypeLOC, 13, -1, 13, -1, -1, 0),
+	}
+	tests := []struct {
+		context int
+		want    []string
+	}{
+		{0, []string{" 7", "--", "13"}},
+		{1, []string{" 6", " 7", " 8", "--", "12", "13", "14"}},
+		{3, []string{" 4", " 5", " 6", " 7", " 8", " 9", "10", "11", "12", "13", "14"}},
+	}
+	for _, tt := range tests {
+		fc := &FileCoverage{
+			Blocks: blocks,
+			cache:  map[int]BlockCoverages{},
+		}
+		p := NewPrinter(fc)
+		p.SetUncoveredOnly(tt.context)
+		dest := new(bytes.Buffer)
+		if err := p.Print(strings.NewReader(code), dest); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, l := range strings.Split(strings.TrimSuffix(dest.String(), "\n"), "\n") {
+			if l == "--" {
+				got = append(got, l)
+				continue
+			}
+			// line number in yellow
+			got = append(got, strings.TrimPrefix(strings.SplitN(l, "\x1b[0m", 2)[0], "\x1b[33m"))
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("context %d: %s", tt.context, diff)
+		}
+	}
+}
+
 func newBlockCoverage(t Type, sl, sc, el, ec, ns, c int) *BlockCoverage {
 	bc := &BlockCoverage{
 		Type:      t,
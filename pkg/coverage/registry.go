@@ -0,0 +1,90 @@
+package coverage
+
+import (
+	"fmt"
+	"sync"
+)
+
+type registeredProcessor struct {
+	name string
+	p    Processor
+}
+
+var (
+	processorsMu sync.RWMutex
+	processors   = []registeredProcessor{}
+)
+
+func init() {
+	RegisterParser("gocover", NewGocover())
+	RegisterParser("gocov", NewGocov())
+	RegisterParser("lcov", NewLcov())
+	// coverage.py and Istanbul are tried before SimpleCov because SimpleCov parser accepts any JSON object of objects
+	RegisterParser("coveragepy", NewCoveragePy())
+	RegisterParser("istanbul", NewIstanbul())
+	RegisterParser("simplecov", NewSimplecov())
+	RegisterParser("clover", NewClover())
+	RegisterParser("cobertura", NewCobertura())
+	RegisterParser("jacoco", NewJacoco())
+	RegisterParser("custom", NewExec())
+	RegisterParser("perfile", NewPerFile("", "", ""))
+}
+
+// RegisterParser registers the coverage report parser ( Processor ) with the name.
+// Registered parsers are tried in the order of registration when detecting the format of the coverage report.
+// If a parser with the same name is already registered, it is replaced.
+func RegisterParser(name string, p Processor) {
+	processorsMu.Lock()
+	defer processorsMu.Unlock()
+	for i, rp := range processors {
+		if rp.name == name {
+			processors[i].p = p
+			return
+		}
+	}
+	processors = append(processors, registeredProcessor{name: name, p: p})
+}
+
+// Processors returns registered coverage report parsers in the order of registration
+func Processors() []Processor {
+	processorsMu.RLock()
+	defer processorsMu.RUnlock()
+	ps := []Processor{}
+	for _, rp := range processors {
+		ps = append(ps, rp.p)
+	}
+	return ps
+}
+
+// Parser returns the registered coverage report parser of the name
+func Parser(name string) (Processor, bool) {
+	processorsMu.RLock()
+	defer processorsMu.RUnlock()
+	for _, rp := range processors {
+		if rp.name == name {
+			return rp.p, true
+		}
+	}
+	return nil, false
+}
+
+// ParseReport parses the coverage report using the registered parsers.
+// The parsers ps ( e.g. the parser configured by the config ) are tried first, and the registered parsers with the same name are skipped.
+func ParseReport(path string, ps ...Processor) (*Coverage, string, error) {
+	names := map[string]struct{}{}
+	for _, p := range ps {
+		names[p.Name()] = struct{}{}
+	}
+	for _, p := range Processors() {
+		if _, ok := names[p.Name()]; ok {
+			continue
+		}
+		ps = append(ps, p)
+	}
+	for _, p := range ps {
+		if cov, rp, err := p.ParseReport(path); err == nil {
+			return cov, rp, nil
+		}
+	}
+	return nil, "", fmt.Errorf("coverage report not found: %s", path)
+}
@@ -20,10 +20,26 @@ type SimplecovCoverage struct {
 	Coverage map[string]SimplecovFileCoverage
 }
 
+// SimplecovFileCoverage accepts both `{"lines": [...]}` ( SimpleCov >= 0.18 ) and the flat array of the hit counts ( SimpleCov < 0.18 )
 type SimplecovFileCoverage struct {
 	Lines []interface{}
 }
 
+func (fc *SimplecovFileCoverage) UnmarshalJSON(b []byte) error {
+	lines := []interface{}{}
+	if err := json.Unmarshal(b, &lines); err == nil {
+		fc.Lines = lines
+		return nil
+	}
+	type alias SimplecovFileCoverage
+	a := alias{}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err
+	}
+	*fc = SimplecovFileCoverage(a)
+	return nil
+}
+
 func NewSimplecov() *Simplecov {
 	return &Simplecov{}
 }
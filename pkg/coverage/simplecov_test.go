@@ -3,6 +3,8 @@ package coverage
 import (
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestSimplecov(t *testing.T) {
@@ -39,3 +41,28 @@ func TestSimplecov(t *testing.T) {
 		}
 	}
 }
+
+func TestSimplecovLegacy(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "simplecov_legacy")
+	got, _, err := NewSimplecov().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 6; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 4; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	fc, err := got.Files.FindByFile("/app/lib/foo.rb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := []int{}
+	for _, b := range fc.Blocks {
+		lines = append(lines, *b.StartLine)
+	}
+	if diff := cmp.Diff(lines, []int{1, 2, 4, 5}, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
@@ -0,0 +1,169 @@
+package loc
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/k1LoW/octocov/pkg/ratio"
+)
+
+type LOC struct {
+	Code      int            `json:"code"`
+	Languages map[string]int `json:"languages,omitempty"`
+}
+
+type DiffLOC struct {
+	A    int  `json:"a"`
+	B    int  `json:"b"`
+	Diff int  `json:"diff"`
+	LOCA *LOC `json:"-"`
+	LOCB *LOC `json:"-"`
+}
+
+func New() *LOC {
+	return &LOC{
+		Languages: map[string]int{},
+	}
+}
+
+func (l *LOC) Compare(l2 *LOC) *DiffLOC {
+	d := &DiffLOC{
+		LOCA: l,
+		LOCB: l2,
+	}
+	if l != nil {
+		d.A = l.Code
+	}
+	if l2 != nil {
+		d.B = l2.Code
+	}
+	d.Diff = d.B - d.A
+	return d
+}
+
+func (l *LOC) String() string {
+	return Format(l.Code)
+}
+
+// Measure counts the lines of code of the files under root by language. Files matched by .gitignore or exclude are skipped
+func Measure(root string, exclude []string) (*LOC, error) {
+	l := New()
+	ps, err := readGitignore(root)
+	if err != nil {
+		return nil, err
+	}
+	m := gitignore.NewMatcher(ps)
+	if err := ratio.Walk(root, func(path string, fi os.FileInfo) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if m.Match(strings.Split(filepath.ToSlash(rel), "/"), fi.IsDir()) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		for _, p := range exclude {
+			match, err := doublestar.PathMatch(p, filepath.ToSlash(rel))
+			if err != nil {
+				return err
+			}
+			if match {
+				return nil
+			}
+		}
+		lang, c, ok := ratio.CountCode(path)
+		if !ok {
+			return nil
+		}
+		log.Printf("loc: %s,%d", path, c)
+		l.Code += c
+		l.Languages[lang] += c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if l.Code == 0 {
+		return nil, fmt.Errorf("could not count code: %s", root)
+	}
+	return l, nil
+}
+
+// Format formats the lines of code in a short form ( e.g. 12.3k )
+func Format(n int) string {
+	switch {
+	case n >= 1000000:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// Parse parses the lines of code formatted by Format ( e.g. 12.3k, 1M, 500 )
+func Parse(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	m := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		m = 1000
+		s = s[:len(s)-1]
+	case strings.HasSuffix(s, "M"):
+		m = 1000000
+		s = s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(f * m), nil
+}
+
+func readGitignore(root string) ([]gitignore.Pattern, error) {
+	ps := []gitignore.Pattern{}
+	if err := ratio.Walk(root, func(path string, fi os.FileInfo) error {
+		if !fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(filepath.Clean(filepath.Join(path, ".gitignore")))
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		var domain []string
+		if rel != "." {
+			domain = strings.Split(filepath.ToSlash(rel), "/")
+		}
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			line := s.Text()
+			if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+				continue
+			}
+			ps = append(ps, gitignore.ParsePattern(line, domain))
+		}
+		return s.Err()
+	}); err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
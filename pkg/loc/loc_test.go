@@ -0,0 +1,115 @@
+package loc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMeasure(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":           "package main\n\nfunc main() {\n\t// comment\n\tprintln(\"hello\")\n}\n",
+		"main_test.go":      "package main\n",
+		"script.py":         "import os\n\nprint(os.getcwd())\n",
+		"vendor/lib/lib.go": "package lib\n\nfunc Lib() {}\n",
+		"tmp/tmp.go":        "package tmp\n",
+		"sub/.gitignore":    "*.gen.go\n",
+		"sub/a.gen.go":      "package sub\n",
+		"sub/b.go":          "package sub\n",
+		".gitignore":        "# ignore\n/tmp/\n",
+	}
+	for p, c := range files {
+		fp := filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(c), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		exclude []string
+		want    *LOC
+		wantErr bool
+	}{
+		{
+			[]string{},
+			&LOC{Code: 10, Languages: map[string]int{"Go": 8, "Python": 2}},
+			false,
+		},
+		{
+			[]string{"vendor/**", "**/*_test.go"},
+			&LOC{Code: 7, Languages: map[string]int{"Go": 5, "Python": 2}},
+			false,
+		},
+		{
+			[]string{"**"},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := Measure(root, tt.exclude)
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", got)
+			continue
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestFormatAndParse(t *testing.T) {
+	tests := []struct {
+		in   int
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{12345, "12.3k"},
+		{1500000, "1.5M"},
+	}
+	for _, tt := range tests {
+		got := Format(tt.in)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+
+	parses := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"500", 500, false},
+		{"12.3k", 12300, false},
+		{"100K", 100000, false},
+		{"1M", 1000000, false},
+		{"many", 0, true},
+	}
+	for _, tt := range parses {
+		got, err := Parse(tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", got)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
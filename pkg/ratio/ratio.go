@@ -12,10 +12,32 @@ import (
 )
 
 type Ratio struct {
-	Code      int      `json:"code"`
-	Test      int      `json:"test"`
-	CodeFiles []string `json:"-"`
-	TestFiles []string `json:"-"`
+	Code      int              `json:"code"`
+	Test      int              `json:"test"`
+	Languages []*LanguageRatio `json:"languages,omitempty"`
+	CodeFiles []string         `json:"-"`
+	TestFiles []string         `json:"-"`
+}
+
+// Language is the patterns of the code and the test of the language
+type Language struct {
+	Name string
+	Code []string
+	Test []string
+}
+
+type LanguageRatio struct {
+	Name string `json:"name"`
+	Code int    `json:"code"`
+	Test int    `json:"test"`
+}
+
+// Ratio returns the lines of the test per line of the code
+func (l *LanguageRatio) Ratio() float64 {
+	if l.Code == 0 {
+		return 0.0
+	}
+	return float64(l.Test) / float64(l.Code)
 }
 
 type DiffRatio struct {
@@ -48,83 +70,47 @@ func (r *Ratio) Compare(r2 *Ratio) *DiffRatio {
 	return d
 }
 
-func Measure(root string, code, test []string) (*Ratio, error) {
+// Measure counts the code and the test under root.
+// The patterns of code and test are matched against the path relative to root, and the patterns of exclude are matched against the absolute path.
+// Only the logical lines are counted when logical is true ( see CountLines ).
+func Measure(root string, code, test, exclude []string, logical bool) (*Ratio, error) {
 	ratio := New()
-	defined := gocloc.NewDefinedLanguages()
-	opts := gocloc.NewClocOptions()
 
-	if err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+	if err := Walk(root, func(path string, fi os.FileInfo) error {
 		if fi.IsDir() {
-			if ignore(path) {
-				return filepath.SkipDir
-			}
 			return nil
 		}
-		if ignore(path) {
+		// match patterns against the path relative to root
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		excluded, err := matchExclude(exclude, path)
+		if err != nil {
+			return err
+		}
+		if excluded {
 			return nil
 		}
 
-		isCode := false
-		isTest := false
-
-		// check path
-		if len(code) == 0 {
-			isCode = true
+		isCode, err := matchPatterns(code, rel, len(code) == 0)
+		if err != nil {
+			return err
 		}
-		for _, p := range code {
-			not := false
-			if strings.HasPrefix(p, "!") {
-				p = strings.TrimPrefix(p, "!")
-				not = true
-			}
-			match, err := doublestar.PathMatch(p, path)
-			if err != nil {
-				return err
-			}
-			if match {
-				if not {
-					isCode = false
-				} else {
-					isCode = true
-				}
-			}
-		}
-		// test
-		for _, p := range test {
-			not := false
-			if strings.HasPrefix(p, "!") {
-				p = strings.TrimPrefix(p, "!")
-				not = true
-			}
-			match, err := doublestar.PathMatch(p, path)
-			if err != nil {
-				return err
-			}
-			if match {
-				if not {
-					isTest = false
-				} else {
-					isTest = true
-				}
-			}
+		isTest, err := matchPatterns(test, rel, false)
+		if err != nil {
+			return err
 		}
 		if !isCode && !isTest {
 			return nil
 		}
-		ext, ok := getFileType(path)
+		_, c, ok := CountLines(path, logical)
 		if !ok {
-			_, _ = fmt.Fprintf(os.Stderr, "could not detect language: %s\n", path)
 			return nil
 		}
-		l, ok := gocloc.Exts[ext]
-		if !ok {
-			_, _ = fmt.Fprintf(os.Stderr, "unsupported language: %s\n", ext)
-			return nil
-		}
-		cf := gocloc.AnalyzeFile(path, defined.Langs[l], opts)
 		if isCode {
-			log.Printf("code: %s,%d", path, cf.Code)
-			ratio.Code += int(cf.Code)
+			log.Printf("code: %s,%d", path, c)
+			ratio.Code += c
 			rel, err := filepath.Rel(root, path)
 			if err != nil {
 				return err
@@ -132,8 +118,8 @@ func Measure(root string, code, test []string) (*Ratio, error) {
 			ratio.CodeFiles = append(ratio.CodeFiles, rel)
 		}
 		if isTest {
-			log.Printf("test: %s,%d", path, cf.Code)
-			ratio.Test += int(cf.Code)
+			log.Printf("test: %s,%d", path, c)
+			ratio.Test += c
 			rel, err := filepath.Rel(root, path)
 			if err != nil {
 				return err
@@ -150,6 +136,153 @@ func Measure(root string, code, test []string) (*Ratio, error) {
 	return ratio, nil
 }
 
+// MeasureLanguages counts the code and the test of each language under root in the same way as Measure
+func MeasureLanguages(root string, languages []*Language, exclude []string, logical bool) ([]*LanguageRatio, error) {
+	lrs := []*LanguageRatio{}
+	for _, l := range languages {
+		lrs = append(lrs, &LanguageRatio{Name: l.Name})
+	}
+	if len(languages) == 0 {
+		return lrs, nil
+	}
+	if err := Walk(root, func(path string, fi os.FileInfo) error {
+		if fi.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		excluded, err := matchExclude(exclude, path)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			return nil
+		}
+		counted := false
+		c := 0
+		for i, l := range languages {
+			isCode, err := matchPatterns(l.Code, rel, false)
+			if err != nil {
+				return err
+			}
+			isTest, err := matchPatterns(l.Test, rel, false)
+			if err != nil {
+				return err
+			}
+			if !isCode && !isTest {
+				continue
+			}
+			if !counted {
+				var ok bool
+				_, c, ok = CountLines(path, logical)
+				if !ok {
+					return nil
+				}
+				counted = true
+			}
+			if isCode {
+				lrs[i].Code += c
+			}
+			if isTest {
+				lrs[i].Test += c
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return lrs, nil
+}
+
+// matchPatterns matches the patterns in order. The pattern with the leading "!" negates the match.
+func matchPatterns(patterns []string, rel string, defaultMatch bool) (bool, error) {
+	matched := defaultMatch
+	for _, p := range patterns {
+		not := false
+		if strings.HasPrefix(p, "!") {
+			p = strings.TrimPrefix(p, "!")
+			not = true
+		}
+		match, err := doublestar.PathMatch(p, rel)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			matched = !not
+		}
+	}
+	return matched, nil
+}
+
+// matchExclude matches the patterns of exclude against the absolute path
+func matchExclude(exclude []string, path string) (bool, error) {
+	if len(exclude) == 0 {
+		return false, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range exclude {
+		match, err := doublestar.Match(p, filepath.ToSlash(abs))
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Walk walks the file tree rooted at root, skipping VCS directories and files, and calls fn for each file or directory
+func Walk(root string, fn func(path string, fi os.FileInfo) error) error {
+	return filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ignore(path) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, fi)
+	})
+}
+
+var (
+	definedLangs = gocloc.NewDefinedLanguages()
+	clocOpts     = gocloc.NewClocOptions()
+)
+
+// CountCode returns the language and the logical lines of the file
+func CountCode(path string) (string, int, bool) {
+	return CountLines(path, true)
+}
+
+// CountLines returns the language and the lines of the file.
+// When logical is true, the blank lines and the comments ( by the comment syntaxes of the language ) are not counted.
+func CountLines(path string, logical bool) (string, int, bool) {
+	ext, ok := getFileType(path)
+	if !ok {
+		_, _ = fmt.Fprintf(os.Stderr, "could not detect language: %s\n", path)
+		return "", 0, false
+	}
+	l, ok := gocloc.Exts[ext]
+	if !ok {
+		_, _ = fmt.Fprintf(os.Stderr, "unsupported language: %s\n", ext)
+		return "", 0, false
+	}
+	cf := gocloc.AnalyzeFile(path, definedLangs.Langs[l], clocOpts)
+	if !logical {
+		return l, int(cf.Code + cf.Comments + cf.Blanks), true
+	}
+	return l, int(cf.Code), true
+}
+
 var ignores = []string{
 	".bzr", ".cvs", ".hg", ".git", ".svn",
 	".github", ".gitignore", ".gitkeep",
@@ -3,6 +3,7 @@ package ratio
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -75,7 +76,7 @@ func TestMeasure(t *testing.T) {
 	}
 	for _, tt := range tests {
 		root := filepath.Join(testdataDir(t), "..")
-		got, err := Measure(root, tt.code, tt.test)
+		got, err := Measure(root, tt.code, tt.test, []string{}, true)
 		if err != nil {
 			if !tt.wantErr {
 				t.Error(err)
@@ -88,6 +89,109 @@ func TestMeasure(t *testing.T) {
 	}
 }
 
+func TestMeasureExclude(t *testing.T) {
+	root := filepath.Join(testdataDir(t), "..")
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := []string{"**/*.go", "!**/*_test.go"}
+	test := []string{"**/*_test.go"}
+	exclude := []string{filepath.ToSlash(abs) + "/pkg/ratio/**"}
+	got, err := Measure(root, code, test, exclude, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range append(got.CodeFiles, got.TestFiles...) {
+		if strings.HasPrefix(filepath.ToSlash(f), "pkg/ratio/") {
+			t.Errorf("%s should be excluded", f)
+		}
+	}
+	all, err := Measure(root, code, test, []string{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code >= all.Code || got.Test >= all.Test {
+		t.Errorf("got %d/%d\nwant less than %d/%d", got.Code, got.Test, all.Code, all.Test)
+	}
+}
+
+func TestMeasureLanguages(t *testing.T) {
+	root := filepath.Join(testdataDir(t), "..")
+	languages := []*Language{
+		{Name: "Go", Code: []string{"**/*.go", "!**/*_test.go"}, Test: []string{"**/*_test.go"}},
+		{Name: "TypeScript", Code: []string{"**/*.ts"}, Test: []string{"**/*.test.ts"}},
+	}
+	got, err := MeasureLanguages(root, languages, []string{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := Measure(root, languages[0].Code, languages[0].Test, []string{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*LanguageRatio{
+		{Name: "Go", Code: all.Code, Test: all.Test},
+		{Name: "TypeScript", Code: 0, Test: 0},
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if got[1].Ratio() != 0.0 {
+		t.Errorf("got %v\nwant %v", got[1].Ratio(), 0.0)
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		file        string
+		content     string
+		wantLogical int
+		wantAll     int
+	}{
+		{"main.go", "package main\n\n// comment\n/*\n block\n*/\nfunc main() {\n\t// comment\n}\n", 3, 9},
+		{"index.ts", "// comment\n\nconst a = 1;\n/* block */\nexport default a;\n", 2, 5},
+		{"main.py", "# comment\n\nimport os\n\nprint(os.name)  # trailing\n", 2, 5},
+		{"main.rb", "# comment\n\nputs 'a'\n\nputs 'b'  # trailing\n", 2, 5},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		p := filepath.Join(dir, tt.file)
+		if err := os.WriteFile(p, []byte(tt.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		_, got, ok := CountLines(p, true)
+		if !ok {
+			t.Errorf("%s: could not count", tt.file)
+			continue
+		}
+		if got != tt.wantLogical {
+			t.Errorf("%s: got %v\nwant %v", tt.file, got, tt.wantLogical)
+		}
+		_, got, _ = CountLines(p, false)
+		if got != tt.wantAll {
+			t.Errorf("%s: got %v\nwant %v", tt.file, got, tt.wantAll)
+		}
+	}
+}
+
+func TestMeasureCountLogicalLines(t *testing.T) {
+	root := filepath.Join(testdataDir(t), "..")
+	code := []string{"**/*.go", "!**/*_test.go"}
+	test := []string{"**/*_test.go"}
+	logical, err := Measure(root, code, test, []string{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := Measure(root, code, test, []string{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logical.Code >= all.Code || logical.Test >= all.Test {
+		t.Errorf("got %d/%d\nwant less than %d/%d", logical.Code, logical.Test, all.Code, all.Test)
+	}
+}
+
 func TestPathMatch(t *testing.T) {
 	root := filepath.Join(testdataDir(t), "..")
 	{
@@ -95,7 +199,7 @@ func TestPathMatch(t *testing.T) {
 			"**/*.go",
 			"!**/*_test.go",
 		}
-		got, err := Measure(root, code, []string{})
+		got, err := Measure(root, code, []string{}, []string{}, true)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -109,7 +213,7 @@ func TestPathMatch(t *testing.T) {
 			"!**/*_test.go",
 			"**/*.go",
 		}
-		got, err := Measure(root, code, []string{})
+		got, err := Measure(root, code, []string{}, []string{}, true)
 		if err != nil {
 			t.Fatal(err)
 		}
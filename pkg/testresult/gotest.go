@@ -0,0 +1,117 @@
+package testresult
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var _ Processor = (*GoTest)(nil)
+
+// GoTest parses the test2json stream of `go test -json`.
+type GoTest struct{}
+
+type GoTestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	// seconds
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+}
+
+func NewGoTest() *GoTest {
+	return &GoTest{}
+}
+
+func (g *GoTest) Name() string {
+	return "go test -json"
+}
+
+func (g *GoTest) ParseReport(path string) (*TestResult, string, error) {
+	events, err := g.parseFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	t := New()
+	t.Format = g.Name()
+	var pkgs, tops float64
+	pkgFound := false
+	for _, e := range events {
+		switch e.Action {
+		case "pass", "fail", "skip":
+		default:
+			continue
+		}
+		d := e.Elapsed * float64(time.Second)
+		if e.Test == "" {
+			// the elapsed time of the package
+			if e.Action != "skip" {
+				pkgs += d
+				pkgFound = true
+			}
+			continue
+		}
+		t.Tests += 1
+		switch e.Action {
+		case "fail":
+			t.Failures += 1
+		case "skip":
+			t.Skipped += 1
+		}
+		if !strings.Contains(e.Test, "/") {
+			tops += d
+		}
+		t.Cases = append(t.Cases, &TestCase{
+			Name:      e.Test,
+			Classname: e.Package,
+			Time:      d,
+		})
+	}
+	// the package events are missing when the stream is interrupted
+	t.Time = pkgs
+	if !pkgFound {
+		t.Time = tops
+	}
+	return t, path, nil
+}
+
+func (g *GoTest) parseFile(path string) ([]*GoTestEvent, error) {
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		return nil, fmt.Errorf("%s is not go test -json format: %w", filepath.Clean(path), errUnsupportedFormat)
+	}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	events := []*GoTestEvent{}
+	rd := bufio.NewReader(bytes.NewReader(b))
+	for {
+		l, err := rd.ReadBytes('\n')
+		if len(bytes.TrimSpace(l)) > 0 {
+			e := &GoTestEvent{}
+			// the lines that are not test2json events ( e.g. build errors ) are skipped
+			if jerr := json.Unmarshal(l, e); jerr == nil && e.Action != "" {
+				events = append(events, e)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%s is not go test -json format: %w", filepath.Clean(path), errUnsupportedFormat)
+	}
+	return events, nil
+}
@@ -0,0 +1,51 @@
+package testresult
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGoTest(t *testing.T) {
+	p := filepath.Join(testdataDir(t), "gotest", "test.json")
+	got, _, err := NewGoTest().ParseReport(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 5; got.Tests != want {
+		t.Errorf("got %v\nwant %v", got.Tests, want)
+	}
+	if want := 1; got.Failures != want {
+		t.Errorf("got %v\nwant %v", got.Failures, want)
+	}
+	if want := 1; got.Skipped != want {
+		t.Errorf("got %v\nwant %v", got.Skipped, want)
+	}
+	if want := 3900 * time.Millisecond; got.Duration() != want {
+		t.Errorf("got %v\nwant %v", got.Duration(), want)
+	}
+	if want := "github.com/example/app/strutil.TestTrim"; got.Cases.Slowest(1)[0].FullName() != want {
+		t.Errorf("got %v\nwant %v", got.Cases.Slowest(1)[0].FullName(), want)
+	}
+	for _, c := range got.Cases.Slowest(len(got.Cases)) {
+		// the time of the parent test includes the time of the subtests
+		if c.FullName() == "github.com/example/app/calc.TestAdd" {
+			t.Errorf("got %v", c.FullName())
+		}
+	}
+	if want := len(got.Cases) - 1; len(got.Cases.Slowest(len(got.Cases))) != want {
+		t.Errorf("got %v\nwant %v", len(got.Cases.Slowest(len(got.Cases))), want)
+	}
+
+	// detected by ParseReport after Surefire
+	got2, _, err := ParseReport(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got2.Format != NewGoTest().Name() {
+		t.Errorf("got %v\nwant %v", got2.Format, NewGoTest().Name())
+	}
+	if _, _, err := NewGoTest().ParseReport(filepath.Join(testdataDir(t), "surefire", "TEST-com.example.CalcTest.xml")); err == nil {
+		t.Error("want error")
+	}
+}
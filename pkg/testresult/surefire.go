@@ -0,0 +1,182 @@
+package testresult
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ Processor = (*Surefire)(nil)
+
+var SurefireDefaultPath = []string{"target", "surefire-reports"}
+
+var thousandsRe = regexp.MustCompile(`^-?\d{1,3}(,\d{3})+(\.\d+)?$`)
+
+// Surefire parses JUnit XML reports generated by Maven Surefire ( and TestNG, Gradle ).
+type Surefire struct{}
+
+type SurefireTestsuites struct {
+	XMLName    xml.Name            `xml:"testsuites"`
+	Testsuites []SurefireTestsuite `xml:"testsuite"`
+}
+
+type SurefireTestsuite struct {
+	XMLName   xml.Name           `xml:"testsuite"`
+	Name      string             `xml:"name,attr"`
+	Tests     int                `xml:"tests,attr"`
+	Failures  int                `xml:"failures,attr"`
+	Errors    int                `xml:"errors,attr"`
+	Skipped   int                `xml:"skipped,attr"`
+	Time      string             `xml:"time,attr"`
+	Testcases []SurefireTestcase `xml:"testcase"`
+}
+
+type SurefireTestcase struct {
+	Name      string `xml:"name,attr"`
+	Classname string `xml:"classname,attr"`
+	Time      string `xml:"time,attr"`
+}
+
+func NewSurefire() *Surefire {
+	return &Surefire{}
+}
+
+func (s *Surefire) Name() string {
+	return "Surefire"
+}
+
+func (s *Surefire) ParseReport(path string) (*TestResult, string, error) {
+	rps, rp, err := s.detectReportPaths(path)
+	if err != nil {
+		return nil, "", err
+	}
+	t := New()
+	t.Format = s.Name()
+	for _, p := range rps {
+		suites, err := s.parseFile(p)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, ts := range suites {
+			cases := TestCases{}
+			var sum float64
+			for _, tc := range ts.Testcases {
+				d, err := parseSeconds(tc.Time)
+				if err != nil {
+					return nil, "", err
+				}
+				sum += d
+				cases = append(cases, &TestCase{
+					Name:      tc.Name,
+					Classname: tc.Classname,
+					Time:      d,
+				})
+			}
+			d, err := parseSeconds(ts.Time)
+			if err != nil {
+				return nil, "", err
+			}
+			if ts.Time == "" {
+				d = sum
+			}
+			tests := ts.Tests
+			if tests == 0 {
+				tests = len(ts.Testcases)
+			}
+			t.Tests += tests
+			t.Failures += ts.Failures
+			t.Errors += ts.Errors
+			t.Skipped += ts.Skipped
+			t.Time += d
+			t.Cases = append(t.Cases, cases...)
+		}
+	}
+	return t, rp, nil
+}
+
+func (s *Surefire) parseFile(p string) ([]SurefireTestsuite, error) {
+	b, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		return nil, err
+	}
+	// the root element is <testsuites> or <testsuite>
+	root := ""
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for root == "" {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%s is not Surefire (JUnit XML) format: %w", filepath.Clean(p), errUnsupportedFormat)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			root = se.Name.Local
+		}
+	}
+	switch root {
+	case "testsuites":
+		tss := SurefireTestsuites{}
+		if err := xml.Unmarshal(b, &tss); err != nil {
+			return nil, fmt.Errorf("can not parse %s: %w", filepath.Clean(p), err)
+		}
+		return tss.Testsuites, nil
+	case "testsuite":
+		ts := SurefireTestsuite{}
+		if err := xml.Unmarshal(b, &ts); err != nil {
+			return nil, fmt.Errorf("can not parse %s: %w", filepath.Clean(p), err)
+		}
+		return []SurefireTestsuite{ts}, nil
+	default:
+		return nil, fmt.Errorf("%s is not Surefire (JUnit XML) format: <%s>: %w", filepath.Clean(p), root, errUnsupportedFormat)
+	}
+}
+
+func (s *Surefire) detectReportPaths(path string) ([]string, string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if !p.IsDir() {
+		return []string{path}, path, nil
+	}
+	// path/to/target/surefire-reports
+	dir := filepath.Join(path, SurefireDefaultPath[0], SurefireDefaultPath[1])
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		// path/to/surefire-reports
+		dir = path
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "TEST-*.xml"))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(matches) == 0 {
+		return nil, "", fmt.Errorf("Surefire reports not found: %s: %w", dir, errUnsupportedFormat)
+	}
+	sort.Strings(matches)
+	return matches, dir, nil
+}
+
+// parseSeconds parses time attribute ( seconds ) and returns nanoseconds.
+// "," is accepted only as the thousands separator ( e.g. "1,234.5" ), and the decimal comma ( e.g. "1,5" ) is rejected.
+func parseSeconds(in string) (float64, error) {
+	if in == "" {
+		return 0, nil
+	}
+	s := in
+	if strings.Contains(s, ",") {
+		if !thousandsRe.MatchString(s) {
+			return 0, fmt.Errorf("invalid time: %s", in)
+		}
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	sec, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time: %s", in)
+	}
+	return sec * float64(time.Second), nil
+}
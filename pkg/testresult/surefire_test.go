@@ -0,0 +1,137 @@
+package testresult
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSurefire(t *testing.T) {
+	tests := []struct {
+		path      string
+		wantTests int
+		wantTime  time.Duration
+	}{
+		{filepath.Join(testdataDir(t), "surefire"), 5, 1003750 * time.Millisecond},
+		{filepath.Join(testdataDir(t), "surefire", "TEST-com.example.CalcTest.xml"), 3, 1250 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, _, err := NewSurefire().ParseReport(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Tests != tt.wantTests {
+			t.Errorf("got %v\nwant %v", got.Tests, tt.wantTests)
+		}
+		if got.Duration() != tt.wantTime {
+			t.Errorf("got %v\nwant %v", got.Duration(), tt.wantTime)
+		}
+		if len(got.Cases) != tt.wantTests {
+			t.Errorf("got %v\nwant %v", len(got.Cases), tt.wantTests)
+		}
+	}
+}
+
+func TestSlowest(t *testing.T) {
+	got, _, err := NewSurefire().ParseReport(filepath.Join(testdataDir(t), "surefire"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{2, []string{"com.example.StringUtilsTest.testReverse", "com.example.StringUtilsTest.testTrim"}},
+		{10, []string{"com.example.StringUtilsTest.testReverse", "com.example.StringUtilsTest.testTrim", "com.example.CalcTest.testAdd", "com.example.CalcTest.testSub", "com.example.CalcTest.testMul"}},
+		{0, []string{}},
+	}
+	for _, tt := range tests {
+		names := []string{}
+		for _, c := range got.Cases.Slowest(tt.n) {
+			names = append(names, c.FullName())
+		}
+		if diff := cmp.Diff(names, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestSurefireNotFound(t *testing.T) {
+	if _, _, err := NewSurefire().ParseReport(testdataDir(t)); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestParseSeconds(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1.5", 1500 * time.Millisecond, false},
+		{"1,234.5", 1234500 * time.Millisecond, false},
+		{"1,234,567", 1234567 * time.Second, false},
+		{"1,5", 0, true},
+		{"12,34", 0, true},
+		{"1,2345", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSeconds(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: got %v\nwantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if time.Duration(got) != tt.want {
+			t.Errorf("%q: got %v\nwant %v", tt.in, time.Duration(got), tt.want)
+		}
+	}
+}
+
+func TestParseReportError(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"TEST-malformed.xml", `<testsuite name="a" tests="1"><testcase name="b"></testsuite>`, "failed to parse the test report as Surefire"},
+		{"TEST-decimal-comma.xml", `<testsuite name="a" tests="1" time="1,5"><testcase name="b" time="1,5"/></testsuite>`, "invalid time: 1,5"},
+		{"other.xml", `<coverage></coverage>`, "test report not found"},
+		{"not-found.xml", "", "test report not found"},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(dir, tt.name)
+		if tt.content != "" {
+			if err := os.WriteFile(p, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, _, err := ParseReport(p)
+		if err == nil {
+			t.Errorf("%s: want error", tt.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v\nwant %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func testdataDir(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := filepath.Abs(filepath.Join(wd, "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
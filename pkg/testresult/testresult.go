@@ -0,0 +1,102 @@
+package testresult
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errUnsupportedFormat is the error of the parser for the report of the other format
+var errUnsupportedFormat = errors.New("unsupported format")
+
+type TestResult struct {
+	Format   string    `json:"format"`
+	Tests    int       `json:"tests"`
+	Failures int       `json:"failures"`
+	Errors   int       `json:"errors"`
+	Skipped  int       `json:"skipped"`
+	Time     float64   `json:"time"`
+	Cases    TestCases `json:"-"`
+}
+
+type TestCase struct {
+	Name      string  `json:"name"`
+	Classname string  `json:"classname"`
+	Time      float64 `json:"time"`
+}
+
+type TestCases []*TestCase
+
+type Processor interface {
+	Name() string
+	ParseReport(path string) (*TestResult, string, error)
+}
+
+func New() *TestResult {
+	return &TestResult{
+		Cases: TestCases{},
+	}
+}
+
+func (t *TestResult) Duration() time.Duration {
+	return time.Duration(t.Time)
+}
+
+func (c *TestCase) FullName() string {
+	if c.Classname == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s.%s", c.Classname, c.Name)
+}
+
+func (c *TestCase) Duration() time.Duration {
+	return time.Duration(c.Time)
+}
+
+// Slowest returns the n slowest test cases in descending order of the time.
+// The parent tests of Go subtests ( e.g. TestAdd of TestAdd/positive ) are excluded, because their time includes the time of the subtests.
+func (cs TestCases) Slowest(n int) TestCases {
+	parents := map[string]struct{}{}
+	for _, c := range cs {
+		name := c.Name
+		for i := strings.LastIndex(name, "/"); i > 0; i = strings.LastIndex(name, "/") {
+			name = name[:i]
+			parents[c.Classname+"\x00"+name] = struct{}{}
+		}
+	}
+	sorted := TestCases{}
+	for _, c := range cs {
+		if _, ok := parents[c.Classname+"\x00"+c.Name]; ok {
+			continue
+		}
+		sorted = append(sorted, c)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time > sorted[j].Time
+	})
+	if n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// ParseReport parses the test report using the parsers in order ( Surefire, go test -json ).
+// It returns the error of the parser when the report is the format of the parser but can not be parsed ( e.g. malformed XML ).
+func ParseReport(path string) (*TestResult, string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", fmt.Errorf("test report not found: %w", err)
+	}
+	for _, p := range []Processor{NewSurefire(), NewGoTest()} {
+		t, rp, err := p.ParseReport(path)
+		if err == nil {
+			return t, rp, nil
+		}
+		if !errors.Is(err, errUnsupportedFormat) {
+			return nil, "", fmt.Errorf("failed to parse the test report as %s: %w", p.Name(), err)
+		}
+	}
+	return nil, "", fmt.Errorf("test report not found: %s", path)
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/pkg/coverage"
+)
+
+// ChangedFilesOnly returns the copy of the report whose coverage has only the files changed in the pull request.
+// The total and the covered of the coverage are still of the whole repository.
+func (r *Report) ChangedFilesOnly(files []*gh.PullRequestFile) *Report {
+	c := *r
+	if r.Coverage == nil {
+		return &c
+	}
+	cov := *r.Coverage
+	cov.Files = coverage.FileCoverages{}
+	found := map[*coverage.FileCoverage]struct{}{}
+	for _, f := range files {
+		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		if _, ok := found[fc]; ok {
+			continue
+		}
+		found[fc] = struct{}{}
+		cov.Files = append(cov.Files, fc)
+	}
+	c.Coverage = &cov
+	return &c
+}
+
+// ChangedFilesOnly returns the copy of the diff report whose coverage has only the files changed in the pull request.
+// The coverages and the diff of the coverage are still of the whole repository.
+func (d *DiffReport) ChangedFilesOnly(files []*gh.PullRequestFile) *DiffReport {
+	c := *d
+	if d.Coverage == nil {
+		return &c
+	}
+	cov := *d.Coverage
+	cov.Files = coverage.DiffFileCoverages{}
+	found := map[*coverage.DiffFileCoverage]struct{}{}
+	for _, f := range files {
+		dfc, err := d.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		if _, ok := found[dfc]; ok {
+			continue
+		}
+		found[dfc] = struct{}{}
+		cov.Files = append(cov.Files, dfc)
+	}
+	c.Coverage = &cov
+	return &c
+}
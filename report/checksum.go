@@ -0,0 +1,67 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// MeasureSourceChecksum computes the checksum of the contents of the files in the coverage report.
+// It is used with CoverageChecksum to detect that the code changed but the coverage report did not ( stale coverage ).
+func (r *Report) MeasureSourceChecksum(gitRoot, wd string) error {
+	if r.Coverage == nil {
+		return errors.New("coverage is not measured")
+	}
+	resolved, unresolved, err := r.resolveFiles(gitRoot, wd)
+	if err != nil {
+		return err
+	}
+	if len(resolved) == 0 {
+		return errors.New("no files in the coverage report could be resolved")
+	}
+	files := append([]string{}, unresolved...)
+	for f := range resolved {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	h := sha256.New()
+	for _, f := range files {
+		// unresolved files are hashed by their names only
+		cs := "-"
+		if p, ok := resolved[f]; ok {
+			cs, err = fileChecksum(p)
+			if err != nil {
+				return err
+			}
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%s\n", f, cs)
+	}
+	r.SourceChecksum = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// IsStaleCoverage reports whether the coverage report is the same as that of the previous report ( prev ) although the source files changed
+func (r *Report) IsStaleCoverage(prev *Report) bool {
+	if prev == nil || r.CoverageChecksum == "" || r.SourceChecksum == "" || prev.CoverageChecksum == "" || prev.SourceChecksum == "" {
+		return false
+	}
+	return r.CoverageChecksum == prev.CoverageChecksum && r.SourceChecksum != prev.SourceChecksum
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
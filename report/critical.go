@@ -0,0 +1,68 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/k1LoW/octocov/pkg/coverage"
+)
+
+// CriticalCoverage is the coverage of the files matched by the critical paths
+type CriticalCoverage struct {
+	Paths   []string `json:"paths"`
+	Total   int      `json:"total"`
+	Covered int      `json:"covered"`
+	Files   int      `json:"files"`
+}
+
+func (cc *CriticalCoverage) Percent() float64 {
+	if cc == nil || cc.Total == 0 {
+		return 0.0
+	}
+	return float64(cc.Covered) / float64(cc.Total) * 100
+}
+
+func measureCriticalCoverage(cov *coverage.Coverage, paths []string) (*CriticalCoverage, error) {
+	cc := &CriticalCoverage{
+		Paths: paths,
+	}
+	for _, f := range cov.Files {
+		match, err := matchCriticalPath(paths, f.File)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		cc.Files += 1
+		cc.Total += f.Total
+		cc.Covered += f.Covered
+	}
+	if cc.Files == 0 {
+		return nil, fmt.Errorf("no files in the coverage report match the critical paths: %s", strings.Join(paths, ", "))
+	}
+	return cc, nil
+}
+
+// matchCriticalPath reports whether the file matches any of the patterns.
+// Because file paths in coverage reports are not always relative to the repository ( e.g. Go package paths ),
+// the patterns are also matched against the trailing parts of the file path.
+func matchCriticalPath(patterns []string, file string) (bool, error) {
+	file = strings.TrimPrefix(filepath.ToSlash(file), "/")
+	elems := strings.Split(file, "/")
+	for _, p := range patterns {
+		p = strings.TrimPrefix(filepath.ToSlash(p), "./")
+		for i := range elems {
+			match, err := doublestar.Match(p, strings.Join(elems[i:], "/"))
+			if err != nil {
+				return false, err
+			}
+			if match {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
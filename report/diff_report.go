@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/loc"
 	"github.com/k1LoW/octocov/pkg/ratio"
 	"github.com/olekukonko/tablewriter"
 )
@@ -23,7 +25,9 @@ type DiffReport struct {
 	CommitB           string                 `json:"commit_b"`
 	Coverage          *coverage.DiffCoverage `json:"coverage,omitempty"`
 	CodeToTestRatio   *ratio.DiffRatio       `json:"code_to_test_ratio,omitempty"`
+	LinesOfCode       *loc.DiffLOC           `json:"lines_of_code,omitempty"`
 	TestExecutionTime *DiffTestExecutionTime `json:"test_execution_time,omitempty"`
+	RemovedFiles      *DiffRemovedFiles      `json:"removed_files,omitempty"`
 	TimestampA        time.Time              `json:"timestamp_a"`
 	TimestampB        time.Time              `json:"timestamp_b"`
 	ReportA           *Report                `json:"-"`
@@ -57,6 +61,51 @@ func (d *DiffReport) Out(w io.Writer) {
 	table.Render()
 }
 
+// OutFiles writes the files whose coverage changed between the reports
+func (d *DiffReport) OutFiles(w io.Writer) {
+	if d.Coverage == nil {
+		return
+	}
+	files := coverage.DiffFileCoverages{}
+	for _, f := range d.Coverage.Files {
+		if f.Diff != 0 || f.FileCoverageA == nil || f.FileCoverageB == nil {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].File < files[j].File
+	})
+	table := tablewriter.NewWriter(w)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("-")
+	table.SetHeaderLine(true)
+	table.SetBorder(false)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
+	table.SetHeader([]string{"Files", makeHeadTitle(d.RefA, d.CommitA, d.ReportA.rp), makeHeadTitle(d.RefB, d.CommitB, d.ReportB.rp), "+/-"})
+	for _, f := range files {
+		a := "-"
+		b := "-"
+		if f.FileCoverageA != nil {
+			a = fmt.Sprintf("%.1f%%", f.A)
+		}
+		if f.FileCoverageB != nil {
+			b = fmt.Sprintf("%.1f%%", f.B)
+		}
+		ds := fmt.Sprintf("%.1f%%", f.Diff)
+		if f.Diff > 0 {
+			ds = fmt.Sprintf("+%.1f%%", f.Diff)
+		}
+		table.Append([]string{f.File, a, b, ds})
+	}
+	table.Render()
+}
+
 var leftSepRe = regexp.MustCompile(`(?m)^\|`)
 
 func (d *DiffReport) Table() string {
@@ -219,23 +268,43 @@ func (d *DiffReport) renderTable(table *tablewriter.Table, g, r, b tablewriter.C
 			}
 		}
 	}
+	if d.LinesOfCode != nil {
+		la := "-"
+		lb := "-"
+		if d.LinesOfCode.LOCA != nil {
+			la = d.LinesOfCode.LOCA.String()
+		}
+		if d.LinesOfCode.LOCB != nil {
+			lb = d.LinesOfCode.LOCB.String()
+		}
+		dd := d.LinesOfCode.Diff
+		ds := fmt.Sprintf("%d", dd)
+		if dd > 0 {
+			ds = fmt.Sprintf("+%d", dd)
+		}
+		t := "Lines of Code"
+		if !detail {
+			t = "**Lines of Code**"
+		}
+		table.Rich([]string{t, la, lb, ds}, []tablewriter.Colors{b, tablewriter.Colors{}, tablewriter.Colors{}, tablewriter.Colors{}})
+	}
 	if d.TestExecutionTime != nil {
 		ta := "-"
 		tb := "-"
+		f := d.timeFormat()
 		if d.TestExecutionTime.A != nil {
-			ta = time.Duration(*d.TestExecutionTime.A).String()
+			ta = f.Format(time.Duration(*d.TestExecutionTime.A))
 		}
 		if d.TestExecutionTime.B != nil {
-			tb = time.Duration(*d.TestExecutionTime.B).String()
+			tb = f.Format(time.Duration(*d.TestExecutionTime.B))
 		}
 		dd := d.TestExecutionTime.Diff
-		ds := time.Duration(dd).String()
+		ds := f.Format(time.Duration(dd))
 		cc := tablewriter.Colors{}
 		if dd > 0 {
-			ds = fmt.Sprintf("+%s", time.Duration(dd).String())
+			ds = fmt.Sprintf("+%s", f.Format(time.Duration(dd)))
 			cc = r
 		} else if dd < 0 {
-			ds = time.Duration(dd).String()
 			cc = g
 		}
 		t := "Test Execution Time"
@@ -246,7 +315,19 @@ func (d *DiffReport) renderTable(table *tablewriter.Table, g, r, b tablewriter.C
 	}
 }
 
-func (d *DiffReport) FileCoveagesTable(files []*gh.PullRequestFile) string {
+// timeFormat returns the format of test execution time set to the compared reports
+func (d *DiffReport) timeFormat() *DurationFormat {
+	if d.ReportB != nil && d.ReportB.timeFormat != nil {
+		return d.ReportB.timeFormat
+	}
+	if d.ReportA != nil {
+		return d.ReportA.timeFormat
+	}
+	return nil
+}
+
+// FileCoveagesTable renders the coverages of the files in the pull request. The table is collapsed when there are more than hideMin files.
+func (d *DiffReport) FileCoveagesTable(files []*gh.PullRequestFile, hideMin int) string {
 	if d.Coverage == nil {
 		return ""
 	}
@@ -288,7 +369,7 @@ func (d *DiffReport) FileCoveagesTable(files []*gh.PullRequestFile) string {
 		return buf.String()
 	}
 
-	if len(rows) > filesHideMin {
+	if len(rows) > hideMin {
 		buf.WriteString("<details>\n\n")
 	}
 
@@ -304,9 +385,72 @@ func (d *DiffReport) FileCoveagesTable(files []*gh.PullRequestFile) string {
 	}
 	table.Render()
 
-	if len(rows) > filesHideMin {
+	if len(rows) > hideMin {
 		buf.WriteString("\n</details>\n")
 	}
 
 	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
 }
+
+// FunctionCoveragesTable renders the regressed functions ( coverage.DiffCoverage.RegressedFunctions ) of the files in the pull request.
+// The table is collapsed when there are more than hideMin functions.
+func (d *DiffReport) FunctionCoveragesTable(files []*gh.PullRequestFile, hideMin int) string {
+	if d.Coverage == nil {
+		return ""
+	}
+	if len(files) == 0 {
+		return ""
+	}
+	rows := [][]string{}
+	for _, dfn := range d.Coverage.RegressedFunctions() {
+		for _, f := range files {
+			dfc, err := d.Coverage.Files.FuzzyFindByFile(f.Filename)
+			if err != nil || dfc.File != dfn.File {
+				continue
+			}
+			cover := fmt.Sprintf("%.1f%%", dfn.B)
+			diff := fmt.Sprintf("%.1f%%", dfn.Diff)
+			switch {
+			case dfn.FunctionCoverageB == nil:
+				cover = "-"
+				diff = "removed"
+			case dfn.FunctionCoverageA == nil:
+				diff = "new"
+			}
+			rows = append(rows, []string{fmt.Sprintf("[%s](%s)", f.Filename, f.BlobURL), fmt.Sprintf("`%s`", dfn.Name), cover, diff})
+			break
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("### Regressed functions\n\n")
+
+	if len(rows) > filesSkipMax {
+		buf.WriteString(fmt.Sprintf("Skip function coverages because there are too many functions (%d)\n", len(rows)))
+		return buf.String()
+	}
+
+	if len(rows) > hideMin {
+		buf.WriteString("<details>\n\n")
+	}
+
+	table := tablewriter.NewWriter(buf)
+	h := []string{"Files", "Functions", "Coverage", "+/-"}
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	for _, v := range rows {
+		table.Append(v)
+	}
+	table.Render()
+
+	if len(rows) > hideMin {
+		buf.WriteString("\n</details>\n")
+	}
+
+	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 2)
+}
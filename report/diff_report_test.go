@@ -5,6 +5,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/pkg/coverage"
 )
 
 func TestDiff(t *testing.T) {
@@ -58,3 +61,88 @@ func TestDiffTable(t *testing.T) {
 		t.Errorf("got\n%v\nwant\n%v", got, want)
 	}
 }
+
+func TestFunctionCoveragesTable(t *testing.T) {
+	a := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/calc.go", Total: 12, Covered: 10, Functions: coverage.FunctionCoverages{
+					{Name: "Add", Total: 4, Covered: 4},
+					{Name: "Sub", Total: 4, Covered: 2},
+					{Name: "Removed", Total: 4, Covered: 4},
+				}},
+				{File: "github.com/owner/repo/other.go", Total: 4, Covered: 4, Functions: coverage.FunctionCoverages{
+					{Name: "Other", Total: 4, Covered: 4},
+				}},
+			},
+		},
+	}
+	b := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/calc.go", Total: 12, Covered: 5, Functions: coverage.FunctionCoverages{
+					{Name: "Add", Total: 4, Covered: 2},
+					{Name: "Sub", Total: 4, Covered: 3},
+					{Name: "Added", Total: 4, Covered: 0},
+				}},
+				{File: "github.com/owner/repo/other.go", Total: 4, Covered: 0, Functions: coverage.FunctionCoverages{
+					{Name: "Other", Total: 4, Covered: 0},
+				}},
+			},
+		},
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "calc.go", BlobURL: "https://github.com/owner/repo/blob/xxx/calc.go"},
+	}
+	got := a.Compare(b).FunctionCoveragesTable(files, FilesHideMin)
+	want := `### Regressed functions
+
+|                           Files                           | Functions | Coverage |   +/-   |
+|-----------------------------------------------------------|-----------|---------:|--------:|
+| [calc.go](https://github.com/owner/repo/blob/xxx/calc.go) | ` + "`Add`" + `     | 50.0%    | -50.0%  |
+| [calc.go](https://github.com/owner/repo/blob/xxx/calc.go) | ` + "`Added`" + `   | 0.0%     | new     |
+| [calc.go](https://github.com/owner/repo/blob/xxx/calc.go) | ` + "`Removed`" + ` | -        | removed |
+`
+	if got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+	if got := a.Compare(b).FunctionCoveragesTable(nil, FilesHideMin); got != "" {
+		t.Errorf("got %v\nwant %v", got, "")
+	}
+	// collapsed when there are more than hideMin functions
+	if got := a.Compare(b).FunctionCoveragesTable(files, 2); !strings.HasPrefix(got, "### Regressed functions\n\n<details>\n\n") {
+		t.Errorf("got\n%v\nwant collapsed", got)
+	}
+}
+
+func TestDiffOutFiles(t *testing.T) {
+	a := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "a.go", Total: 10, Covered: 5},
+				{File: "b.go", Total: 10, Covered: 5},
+				{File: "c.go", Total: 10, Covered: 5},
+			},
+		},
+	}
+	b := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "a.go", Total: 10, Covered: 8},
+				{File: "b.go", Total: 10, Covered: 5},
+				{File: "d.go", Total: 10, Covered: 0},
+			},
+		},
+	}
+	buf := new(bytes.Buffer)
+	a.Compare(b).OutFiles(buf)
+	got := buf.String()
+	for _, f := range []string{"a.go", "c.go", "d.go"} {
+		if !strings.Contains(got, f) {
+			t.Errorf("got %v\nwant to contain %v", got, f)
+		}
+	}
+	if strings.Contains(got, "b.go") {
+		t.Errorf("got %v\nwant not to contain %v", got, "b.go")
+	}
+}
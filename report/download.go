@@ -0,0 +1,68 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/gh"
+)
+
+const (
+	maxDownloadSize = 100 << 20
+	downloadTimeout = 60 * time.Second
+)
+
+func isURL(p string) bool {
+	return strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://")
+}
+
+// downloadCoverageReport downloads the coverage report of the URL to the temporary directory
+func downloadCoverageReport(ctx context.Context, rawURL, dir string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := gh.HTTPClientForDownload(u, downloadTimeout).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download coverage report (%s): %s", rawURL, res.Status)
+	}
+	// keep the file name because some formats are detected by it
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "coverage"
+	}
+	p := filepath.Join(dir, name)
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	n, err := io.Copy(f, io.LimitReader(res.Body, maxDownloadSize+1))
+	if err != nil {
+		return "", err
+	}
+	if n > maxDownloadSize {
+		return "", fmt.Errorf("failed to download coverage report (%s): exceeds the limit of %d bytes", rawURL, maxDownloadSize)
+	}
+	return p, nil
+}
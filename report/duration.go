@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	DurationUnitAuto         = "auto"
+	DurationUnitSecond       = "s"
+	DurationUnitMillisecond  = "ms"
+	defaultDurationPrecision = 1
+)
+
+// DurationFormat is the format of test execution time
+type DurationFormat struct {
+	// s, ms or auto ( rounded Go duration string such as 1m2.3s ).
+	Unit string
+	// number of decimal places of the unit ( of seconds when Unit is auto )
+	Precision int
+}
+
+// NewDurationFormat returns the format of test execution time. The default precision is 1 ( 0 for ms )
+func NewDurationFormat(unit string, precision *int) (*DurationFormat, error) {
+	f := &DurationFormat{Unit: unit, Precision: defaultDurationPrecision}
+	switch unit {
+	case DurationUnitAuto, DurationUnitSecond:
+	case DurationUnitMillisecond:
+		f.Precision = 0
+	default:
+		return nil, fmt.Errorf("invalid unit: %s", unit)
+	}
+	if precision != nil {
+		if *precision < 0 {
+			return nil, fmt.Errorf("invalid precision: %d", *precision)
+		}
+		f.Precision = *precision
+	}
+	return f, nil
+}
+
+// Format formats the duration. A nil DurationFormat formats it by time.Duration.String()
+func (f *DurationFormat) Format(d time.Duration) string {
+	if f == nil {
+		return d.String()
+	}
+	switch f.Unit {
+	case DurationUnitSecond:
+		return fmt.Sprintf("%.*fs", f.Precision, d.Seconds())
+	case DurationUnitMillisecond:
+		return fmt.Sprintf("%.*fms", f.Precision, float64(d)/float64(time.Millisecond))
+	default:
+		return d.Round(time.Duration(float64(time.Second) / math.Pow10(f.Precision))).String()
+	}
+}
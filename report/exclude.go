@@ -0,0 +1,99 @@
+package report
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/k1LoW/octocov/pkg/coverage"
+)
+
+// ExcludeCoverageFiles drops the files matched by the patterns ( glob patterns relative to the root ) from the coverage and recalculates the totals.
+// The patterns are matched against the whole path relative to the root only.
+func (r *Report) ExcludeCoverageFiles(root string, patterns []string) error {
+	if r.Coverage == nil || len(patterns) == 0 {
+		return nil
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	mod := goModulePath(root)
+	files := coverage.FileCoverages{}
+	total, covered := 0, 0
+	excluded := false
+	for _, f := range r.Coverage.Files {
+		match, err := matchRootRelativePath(patterns, rootRelativePath(root, mod, f.File))
+		if err != nil {
+			return fmt.Errorf("coverage.exclude: %w", err)
+		}
+		if match {
+			excluded = true
+			continue
+		}
+		files = append(files, f)
+		total += f.Total
+		covered += f.Covered
+	}
+	if !excluded {
+		return nil
+	}
+	if len(files) == 0 {
+		return errors.New("all files in the coverage report are excluded by coverage.exclude")
+	}
+	r.Coverage.Files = files
+	r.Coverage.Total = total
+	r.Coverage.Covered = covered
+	return nil
+}
+
+// rootRelativePath returns the path of the file in the coverage report relative to the root.
+// The absolute paths under the root and the import paths of the Go module of the root ( mod ) are made relative.
+func rootRelativePath(root, mod, file string) string {
+	if filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(root, file); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+		return filepath.ToSlash(file)
+	}
+	file = strings.TrimPrefix(filepath.ToSlash(file), "./")
+	if mod != "" && strings.HasPrefix(file, mod+"/") {
+		return strings.TrimPrefix(file, mod+"/")
+	}
+	return file
+}
+
+func matchRootRelativePath(patterns []string, file string) (bool, error) {
+	for _, p := range patterns {
+		p = strings.TrimPrefix(filepath.ToSlash(p), "./")
+		match, err := doublestar.Match(p, file)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// goModulePath returns the module path of go.mod of the root, or an empty string if it is not found
+func goModulePath(root string) string {
+	f, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
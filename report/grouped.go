@@ -0,0 +1,186 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/olekukonko/tablewriter"
+)
+
+// fileGroup is the files of the pull request grouped under the same directory
+type fileGroup struct {
+	name     string
+	covered  int
+	total    int
+	coveredA int
+	totalA   int
+	rows     [][]string
+}
+
+func (g *fileGroup) cover() float64 {
+	if g.total == 0 {
+		return 0.0
+	}
+	return float64(g.covered) / float64(g.total) * 100
+}
+
+func (g *fileGroup) diff() float64 {
+	a := 0.0
+	if g.totalA > 0 {
+		a = float64(g.coveredA) / float64(g.totalA) * 100
+	}
+	return g.cover() - a
+}
+
+// GroupedFileCoveagesTable renders FileCoveagesTable with the files grouped by the directory up to depth.
+// Each group has an aggregate row and its files are nested in a <details> block. The table of the groups is collapsed when there are more than hideMin files.
+func (r *Report) GroupedFileCoveagesTable(files []*gh.PullRequestFile, depth, hideMin int) string {
+	if r.Coverage == nil {
+		return ""
+	}
+	var t, c int
+	groups := map[string]*fileGroup{}
+	n := 0
+	for _, f := range files {
+		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		n++
+		c += fc.Covered
+		t += fc.Total
+		cover := float64(fc.Covered) / float64(fc.Total) * 100
+		if fc.Total == 0 {
+			cover = 0.0
+		}
+		g := lookupFileGroup(groups, f.Filename, depth)
+		g.covered += fc.Covered
+		g.total += fc.Total
+		g.rows = append(g.rows, []string{fmt.Sprintf("[%s](%s)", f.Filename, f.BlobURL), fmt.Sprintf("%.1f%%", cover)})
+	}
+	if n == 0 {
+		return ""
+	}
+	return renderGroupedTable(groups, n, c, t, hideMin, false)
+}
+
+// GroupedFileCoveagesTable renders FileCoveagesTable with the files grouped by the directory up to depth.
+func (d *DiffReport) GroupedFileCoveagesTable(files []*gh.PullRequestFile, depth, hideMin int) string {
+	if d.Coverage == nil {
+		return ""
+	}
+	var t, c int
+	groups := map[string]*fileGroup{}
+	n := 0
+	for _, f := range files {
+		fc, err := d.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		n++
+		diff := fmt.Sprintf("%.1f%%", fc.Diff)
+		if fc.Diff > 0 {
+			diff = fmt.Sprintf("+%.1f%%", fc.Diff)
+		}
+		g := lookupFileGroup(groups, f.Filename, depth)
+		if fc.FileCoverageB != nil {
+			c += fc.FileCoverageB.Covered
+			t += fc.FileCoverageB.Total
+			g.covered += fc.FileCoverageB.Covered
+			g.total += fc.FileCoverageB.Total
+		}
+		if fc.FileCoverageA != nil {
+			g.coveredA += fc.FileCoverageA.Covered
+			g.totalA += fc.FileCoverageA.Total
+		}
+		g.rows = append(g.rows, []string{fmt.Sprintf("[%s](%s)", f.Filename, f.BlobURL), fmt.Sprintf("%.1f%%", fc.B), diff})
+	}
+	if n == 0 {
+		return ""
+	}
+	return renderGroupedTable(groups, n, c, t, hideMin, true)
+}
+
+func lookupFileGroup(groups map[string]*fileGroup, file string, depth int) *fileGroup {
+	name := coverage.PackageName(file, depth)
+	g, ok := groups[name]
+	if !ok {
+		g = &fileGroup{name: name}
+		groups[name] = g
+	}
+	return g
+}
+
+func renderGroupedTable(groups map[string]*fileGroup, n, c, t, hideMin int, diff bool) string {
+	coverAll := float64(c) / float64(t) * 100
+	if t == 0 {
+		coverAll = 0.0
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("### Code coverage of files in pull request scope (%.1f%%)\n\n", coverAll))
+
+	if n > filesSkipMax {
+		buf.WriteString(fmt.Sprintf("Skip file coverages because there are too many files (%d)\n", n))
+		return buf.String()
+	}
+
+	sorted := []*fileGroup{}
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+
+	h := []string{"Packages", "Coverage"}
+	if diff {
+		h = append(h, "+/-")
+	}
+	rows := [][]string{}
+	for _, g := range sorted {
+		row := []string{g.name, fmt.Sprintf("%.1f%%", g.cover())}
+		if diff {
+			d := fmt.Sprintf("%.1f%%", g.diff())
+			if g.diff() > 0 {
+				d = fmt.Sprintf("+%.1f%%", g.diff())
+			}
+			row = append(row, d)
+		}
+		rows = append(rows, row)
+	}
+	if n > hideMin {
+		buf.WriteString("<details>\n\n")
+	}
+	buf.WriteString(renderMarkdownTable(h, rows))
+
+	h[0] = "Files"
+	for _, g := range sorted {
+		buf.WriteString(fmt.Sprintf("\n<details><summary>%s (%.1f%%)</summary>\n\n", g.name, g.cover()))
+		buf.WriteString(renderMarkdownTable(h, g.rows))
+		buf.WriteString("\n</details>\n")
+	}
+	if n > hideMin {
+		buf.WriteString("\n</details>\n")
+	}
+	return buf.String()
+}
+
+// renderMarkdownTable renders the table with the columns except the first one aligned to the right
+func renderMarkdownTable(h []string, rows [][]string) string {
+	buf := new(bytes.Buffer)
+	table := tablewriter.NewWriter(buf)
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	for _, v := range rows {
+		table.Append(v)
+	}
+	table.Render()
+	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/k1LoW/octocov/pkg/coverage"
+)
+
+// coverageReportPaths splits the comma-separated paths ( files or URLs ) and expands the glob patterns ( `**` is supported )
+func coverageReportPaths(path string) ([]string, error) {
+	if strings.HasPrefix(path, coverage.ExecPrefix) || strings.HasPrefix(path, coverage.PerFilePrefix) {
+		return []string{path}, nil
+	}
+	paths := []string{}
+	for _, p := range strings.Split(path, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil || isURL(p) || !strings.ContainsAny(p, "*?[{") {
+			paths = append(paths, p)
+			continue
+		}
+		base, pattern := doublestar.SplitPattern(filepath.ToSlash(p))
+		matches, err := doublestar.Glob(os.DirFS(filepath.FromSlash(base)), pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("coverage report not found: %s", p)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			paths = append(paths, filepath.Join(filepath.FromSlash(base), filepath.FromSlash(m)))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("coverage report not found: %s", path)
+	}
+	return paths, nil
+}
+
+// measureMergedCoverage measures the coverages of the paths and merges them into one coverage
+func (r *Report) measureMergedCoverage(paths []string, ps ...coverage.Processor) error {
+	cov := coverage.New()
+	checksums := []string{}
+	modes := map[string]struct{}{}
+	var latest time.Time
+	for _, p := range paths {
+		pr := &Report{}
+		if err := pr.MeasureCoverage(p, ps...); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if pr.Coverage == nil {
+			return fmt.Errorf("%s: coverage report not found", p)
+		}
+		if pr.Coverage.Format == coverage.NewGocover().Name() {
+			if m, err := coverage.GocoverMode(pr.rp); err == nil {
+				modes[m] = struct{}{}
+			}
+		}
+		if err := cov.Merge(pr.Coverage); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		checksums = append(checksums, pr.CoverageChecksum)
+		// the latest report is used as the report path ( e.g. to detect the test execution time )
+		if fi, err := os.Stat(pr.rp); err == nil && (r.rp == "" || fi.ModTime().After(latest)) {
+			latest = fi.ModTime()
+			r.rp = pr.rp
+		}
+	}
+	if r.rp == "" {
+		r.rp = paths[len(paths)-1]
+	}
+	if len(modes) > 1 {
+		// the counts of set mode are 0 or 1, so they can not be summed with the counts of the other modes
+		ms := []string{}
+		for m := range modes {
+			ms = append(ms, m)
+		}
+		sort.Strings(ms)
+		mode := "count"
+		if _, ok := modes["set"]; ok {
+			cov.ToSetMode()
+			mode = "set"
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: mixing covermodes (%s) in %s: the counts are merged as %s mode\n", strings.Join(ms, ", "), strings.Join(paths, ","), mode)
+	}
+	r.Coverage = cov
+	h := sha256.Sum256([]byte(strings.Join(checksums, ",")))
+	r.CoverageChecksum = hex.EncodeToString(h[:])
+	return nil
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MetricCoverage          = "coverage"
+	MetricCriticalCoverage  = "critical"
+	MetricCodeToTestRatio   = "ratio"
+	MetricLinesOfCode       = "loc"
+	MetricTestExecutionTime = "time"
+	MetricTestCount         = "tests"
+)
+
+var metrics = []string{
+	MetricCoverage,
+	MetricCriticalCoverage,
+	MetricCodeToTestRatio,
+	MetricLinesOfCode,
+	MetricTestExecutionTime,
+	MetricTestCount,
+}
+
+// ValidateMetrics checks that the names are metric names
+func ValidateMetrics(names []string) error {
+	for _, n := range names {
+		if !contains(metrics, n) {
+			return fmt.Errorf("invalid metric: %s (must be one of %s)", n, strings.Join(metrics, ", "))
+		}
+	}
+	return nil
+}
+
+// SelectMetrics returns a copy of the report that has only the metrics of the names. All metrics are kept when names is empty
+func (r *Report) SelectMetrics(names []string) (*Report, error) {
+	if err := ValidateMetrics(names); err != nil {
+		return nil, err
+	}
+	selected := *r
+	if len(names) == 0 {
+		return &selected, nil
+	}
+	if !contains(names, MetricCoverage) {
+		selected.Coverage = nil
+	}
+	if !contains(names, MetricCriticalCoverage) {
+		selected.CriticalCoverage = nil
+	}
+	if !contains(names, MetricCodeToTestRatio) {
+		selected.CodeToTestRatio = nil
+	}
+	if !contains(names, MetricLinesOfCode) {
+		selected.LinesOfCode = nil
+	}
+	if !contains(names, MetricTestExecutionTime) {
+		selected.TestExecutionTime = nil
+	}
+	if !contains(names, MetricTestCount) {
+		selected.TestCount = nil
+	}
+	return &selected, nil
+}
+
+func contains(s []string, e string) bool {
+	for _, v := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
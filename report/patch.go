@@ -0,0 +1,156 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/k1LoW/octocov/gh"
+)
+
+// PatchCoverage is the coverage of the lines added or modified in the pull request
+type PatchCoverage struct {
+	Repository string               `json:"repository"`
+	Ref        string               `json:"ref"`
+	Commit     string               `json:"commit"`
+	Total      int                  `json:"total"`
+	Covered    int                  `json:"covered"`
+	Percent    float64              `json:"percent"`
+	Files      []*PatchFileCoverage `json:"files"`
+}
+
+type PatchFileCoverage struct {
+	File           string  `json:"file"`
+	Total          int     `json:"total"`
+	Covered        int     `json:"covered"`
+	Percent        float64 `json:"percent"`
+	UncoveredLines []int   `json:"uncovered_lines"`
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// PatchCoverage measures the coverage of the lines added or modified in the files of the pull request.
+// Lines that are not executable ( not in any block of the coverage report ) are not counted.
+func (r *Report) PatchCoverage(files []*gh.PullRequestFile) (*PatchCoverage, error) {
+	if r.Coverage == nil {
+		return nil, errors.New("coverage is not measured")
+	}
+	pc := &PatchCoverage{
+		Repository: r.Repository,
+		Ref:        r.Ref,
+		Commit:     r.Commit,
+		Files:      []*PatchFileCoverage{},
+	}
+	for _, f := range files {
+		if f.Status == "removed" || f.Patch == "" {
+			continue
+		}
+		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		lines, err := addedLines(f.Patch)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Filename, err)
+		}
+		pfc := &PatchFileCoverage{
+			File:           f.Filename,
+			UncoveredLines: []int{},
+		}
+		for _, l := range lines {
+			blocks := fc.FindBlocksByLine(l)
+			if len(blocks) == 0 {
+				continue
+			}
+			pfc.Total += 1
+			covered := false
+			for _, b := range blocks {
+				if b.Count != nil && *b.Count > 0 {
+					covered = true
+					break
+				}
+			}
+			if covered {
+				pfc.Covered += 1
+			} else {
+				pfc.UncoveredLines = append(pfc.UncoveredLines, l)
+			}
+		}
+		if pfc.Total == 0 {
+			continue
+		}
+		pfc.Percent = float64(pfc.Covered) / float64(pfc.Total) * 100
+		pc.Total += pfc.Total
+		pc.Covered += pfc.Covered
+		pc.Files = append(pc.Files, pfc)
+	}
+	if pc.Total > 0 {
+		pc.Percent = float64(pc.Covered) / float64(pc.Total) * 100
+	}
+	sort.Slice(pc.Files, func(i, j int) bool {
+		return pc.Files[i].File < pc.Files[j].File
+	})
+	return pc, nil
+}
+
+// LineRange is the range of the lines ( inclusive )
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// UncoveredLineRanges returns the consecutive uncovered lines as ranges
+func (pfc *PatchFileCoverage) UncoveredLineRanges() []*LineRange {
+	ranges := []*LineRange{}
+	for _, l := range pfc.UncoveredLines {
+		if len(ranges) > 0 && ranges[len(ranges)-1].End+1 == l {
+			ranges[len(ranges)-1].End = l
+			continue
+		}
+		ranges = append(ranges, &LineRange{Start: l, End: l})
+	}
+	return ranges
+}
+
+func (pc *PatchCoverage) Bytes() []byte {
+	b, _ := json.Marshal(pc)
+	return b
+}
+
+// addedLines returns the line numbers ( in the new file ) of the lines added in the unified diff
+func addedLines(patch string) ([]int, error) {
+	lines := []int{}
+	n := 0
+	inHunk := false
+	for _, l := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(l, "@@") {
+			m := hunkHeaderRe.FindStringSubmatch(l)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header: %s", l)
+			}
+			start, err := strconv.Atoi(m[1])
+			if err != nil {
+				return nil, err
+			}
+			n = start
+			inHunk = true
+			continue
+		}
+		if !inHunk {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(l, "+"):
+			lines = append(lines, n)
+			n++
+		case strings.HasPrefix(l, "-"), strings.HasPrefix(l, `\`):
+		default:
+			n++
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,216 @@
+package report
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// CoveragePolicy is the coverage thresholds of files managed in a CODEOWNERS-like policy file.
+//
+//	# pattern      threshold  owners
+//	internal/**    80%        @org/platform
+//	**/*_gen.go    0%
+//
+// Each line has a glob pattern, the accepted coverage of each file matched by the pattern and optional owners.
+// As in CODEOWNERS, the last matching rule wins ( e.g. the files of `internal/**/*_gen.go` are accepted with 0% ).
+type CoveragePolicy struct {
+	Rules []*CoveragePolicyRule
+}
+
+type CoveragePolicyRule struct {
+	Pattern    string
+	Acceptable float64
+	Owners     []string
+	Line       int
+}
+
+// PolicyViolation is a file whose coverage is below the accepted coverage of the policy
+type PolicyViolation struct {
+	File     string
+	Coverage float64
+	Rule     *CoveragePolicyRule
+}
+
+func (v *PolicyViolation) String() string {
+	s := fmt.Sprintf("%s (%.1f%%, accepted %.1f%%)", v.File, v.Coverage, v.Rule.Acceptable)
+	if len(v.Rule.Owners) > 0 {
+		s = fmt.Sprintf("%s %s", s, strings.Join(v.Rule.Owners, " "))
+	}
+	return s
+}
+
+// ReadCoveragePolicy reads and validates the policy file
+func ReadCoveragePolicy(path string) (*CoveragePolicy, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := ParseCoveragePolicy(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParseCoveragePolicy parses and validates the policy
+func ParseCoveragePolicy(r io.Reader) (*CoveragePolicy, error) {
+	p := &CoveragePolicy{}
+	s := bufio.NewScanner(r)
+	n := 0
+	for s.Scan() {
+		n++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: threshold is not set: %s", n, line)
+		}
+		pattern := strings.TrimPrefix(filepath.ToSlash(fields[0]), "./")
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("line %d: invalid pattern: %s", n, fields[0])
+		}
+		a, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+		if err != nil || a < 0 || a > 100 {
+			return nil, fmt.Errorf("line %d: invalid threshold: %s", n, fields[1])
+		}
+		owners := fields[2:]
+		for _, o := range owners {
+			if !strings.Contains(o, "@") {
+				return nil, fmt.Errorf("line %d: invalid owner: %s", n, o)
+			}
+		}
+		rule := &CoveragePolicyRule{
+			Pattern:    pattern,
+			Acceptable: a,
+			Owners:     owners,
+			Line:       n,
+		}
+		p.Rules = append(p.Rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.Rules) == 0 {
+		return nil, errors.New("no rules")
+	}
+	return p, nil
+}
+
+// Match returns the rules matched by the file in the order of the lines
+func (p *CoveragePolicy) Match(file string) ([]*CoveragePolicyRule, error) {
+	matched := []*CoveragePolicyRule{}
+	for _, rule := range p.Rules {
+		match, err := matchCriticalPath([]string{rule.Pattern}, file)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			matched = append(matched, rule)
+		}
+	}
+	return matched, nil
+}
+
+// PolicyViolations returns the files whose coverage is below the accepted coverage of the policy.
+// The last matching rule wins, and the overlapping rules with different thresholds are returned as the warnings.
+func (r *Report) PolicyViolations(p *CoveragePolicy) ([]*PolicyViolation, []string, error) {
+	if r.Coverage == nil {
+		return nil, nil, errors.New("coverage is not measured")
+	}
+	violations := []*PolicyViolation{}
+	type overlap struct {
+		overridden *CoveragePolicyRule
+		winner     *CoveragePolicyRule
+	}
+	overlaps := map[overlap]int{}
+	for _, f := range r.Coverage.Files {
+		rules, err := p.Match(f.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		rule := rules[len(rules)-1]
+		for _, rr := range rules[:len(rules)-1] {
+			if rr.Acceptable != rule.Acceptable {
+				overlaps[overlap{rr, rule}]++
+			}
+		}
+		cover := 0.0
+		if f.Total > 0 {
+			cover = float64(f.Covered) / float64(f.Total) * 100
+		}
+		if cover < rule.Acceptable {
+			violations = append(violations, &PolicyViolation{
+				File:     f.File,
+				Coverage: cover,
+				Rule:     rule,
+			})
+		}
+	}
+	keys := []overlap{}
+	for k := range overlaps {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].winner.Line != keys[j].winner.Line {
+			return keys[i].winner.Line < keys[j].winner.Line
+		}
+		return keys[i].overridden.Line < keys[j].overridden.Line
+	})
+	warnings := []string{}
+	for _, k := range keys {
+		warnings = append(warnings, fmt.Sprintf("%s %.1f%% (line %d) overrides %s %.1f%% (line %d) for %d files", k.winner.Pattern, k.winner.Acceptable, k.winner.Line, k.overridden.Pattern, k.overridden.Acceptable, k.overridden.Line, overlaps[k]))
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].File < violations[j].File
+	})
+	return violations, warnings, nil
+}
+
+// PerFileViolations returns the files whose coverage is below the accepted coverage, except the files matched by the exclude patterns.
+// Files without any statements are skipped.
+func (r *Report) PerFileViolations(acceptable float64, exclude []string) ([]*PolicyViolation, error) {
+	if r.Coverage == nil {
+		return nil, errors.New("coverage is not measured")
+	}
+	rule := &CoveragePolicyRule{Pattern: "**", Acceptable: acceptable}
+	violations := []*PolicyViolation{}
+	for _, f := range r.Coverage.Files {
+		if f.Total == 0 {
+			continue
+		}
+		excluded, err := matchCriticalPath(exclude, f.File)
+		if err != nil {
+			return nil, err
+		}
+		if excluded {
+			continue
+		}
+		cover := float64(f.Covered) / float64(f.Total) * 100
+		if cover < acceptable {
+			violations = append(violations, &PolicyViolation{
+				File:     f.File,
+				Coverage: cover,
+				Rule:     rule,
+			})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].File < violations[j].File
+	})
+	return violations, nil
+}
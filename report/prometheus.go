@@ -0,0 +1,38 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type prometheusMetric struct {
+	name  string
+	help  string
+	value float64
+}
+
+var prometheusLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// PrometheusOut writes the measured metrics of the report as gauges in Prometheus exposition format ( e.g. for the textfile collector of node_exporter )
+func (r *Report) PrometheusOut(w io.Writer) error {
+	pms := []prometheusMetric{}
+	if r.IsMeasuredCoverage() {
+		pms = append(pms, prometheusMetric{"octocov_coverage_percent", "Code coverage [%].", r.CoveragePercent()})
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		pms = append(pms, prometheusMetric{"octocov_code_to_test_ratio", "Code to test ratio.", r.CodeToTestRatioRatio()})
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		pms = append(pms, prometheusMetric{"octocov_test_execution_time_seconds", "Test execution time [sec].", time.Duration(*r.TestExecutionTime).Seconds()})
+	}
+	label := prometheusLabelValueReplacer.Replace(r.Repository)
+	for _, m := range pms {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s{repository=\"%s\"} %s\n", m.name, m.help, m.name, m.name, label, strconv.FormatFloat(m.value, 'f', -1, 64)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/ratio"
+)
+
+func TestPrometheusOut(t *testing.T) {
+	d := float64(90*time.Second + 500*time.Millisecond)
+	tests := []struct {
+		r    *Report
+		want string
+	}{
+		{
+			&Report{
+				Repository:        "owner/repo",
+				Coverage:          &coverage.Coverage{Total: 200, Covered: 151},
+				CodeToTestRatio:   &ratio.Ratio{Code: 100, Test: 50},
+				TestExecutionTime: &d,
+			},
+			`# HELP octocov_coverage_percent Code coverage [%].
+# TYPE octocov_coverage_percent gauge
+octocov_coverage_percent{repository="owner/repo"} 75.5
+# HELP octocov_code_to_test_ratio Code to test ratio.
+# TYPE octocov_code_to_test_ratio gauge
+octocov_code_to_test_ratio{repository="owner/repo"} 0.5
+# HELP octocov_test_execution_time_seconds Test execution time [sec].
+# TYPE octocov_test_execution_time_seconds gauge
+octocov_test_execution_time_seconds{repository="owner/repo"} 90.5
+`,
+		},
+		{
+			&Report{
+				Repository: `owner/"repo"`,
+				Coverage:   &coverage.Coverage{Total: 4, Covered: 1},
+			},
+			`# HELP octocov_coverage_percent Code coverage [%].
+# TYPE octocov_coverage_percent gauge
+octocov_coverage_percent{repository="owner/\"repo\""} 25
+`,
+		},
+		{
+			&Report{Repository: "owner/repo"},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		buf := new(bytes.Buffer)
+		if err := tt.r.PrometheusOut(buf); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
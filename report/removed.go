@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/pkg/coverage"
+)
+
+// DiffRemovedFiles is the coverage of the files removed in the pull request
+type DiffRemovedFiles struct {
+	Files   []string `json:"files"`
+	Total   int      `json:"total"`
+	Covered int      `json:"covered"`
+	// coverage change attributable to removing the files
+	Diff float64 `json:"diff"`
+	// whether the coverage of the previous report excludes the removed files
+	Excluded bool `json:"excluded"`
+}
+
+// HandlePullRequestFiles follows the files renamed in the pull request and accounts for the files removed in the pull request.
+// If exclude is true, the coverage of the previous report is recalculated without the removed files,
+// so that the coverage delta is not boosted by deleting uncovered code.
+func (d *DiffReport) HandlePullRequestFiles(files []*gh.PullRequestFile, exclude bool) {
+	if d.Coverage == nil {
+		return
+	}
+	for _, f := range files {
+		if f.Status == "renamed" && f.PreviousFilename != "" {
+			d.Coverage.FollowRename(f.PreviousFilename, f.Filename)
+		}
+	}
+	removed := coverage.DiffFileCoverages{}
+	for _, f := range files {
+		if f.Status != "removed" {
+			continue
+		}
+		for _, dfc := range d.Coverage.Files {
+			if dfc.FileCoverageA == nil || dfc.FileCoverageB != nil {
+				continue
+			}
+			if coverage.MatchFile(dfc.File, f.Filename) {
+				removed = append(removed, dfc)
+				break
+			}
+		}
+	}
+	if len(removed) == 0 || d.Coverage.CoverageA == nil {
+		return
+	}
+	rf := &DiffRemovedFiles{
+		Files:    []string{},
+		Excluded: exclude,
+	}
+	for _, dfc := range removed {
+		rf.Files = append(rf.Files, dfc.File)
+		rf.Total += dfc.FileCoverageA.Total
+		rf.Covered += dfc.FileCoverageA.Covered
+	}
+	sort.Strings(rf.Files)
+	a := 0.0
+	if t := d.Coverage.CoverageA.Total - rf.Total; t > 0 {
+		a = float64(d.Coverage.CoverageA.Covered-rf.Covered) / float64(t) * 100
+	}
+	rf.Diff = a - d.Coverage.A
+	if exclude {
+		d.Coverage.A = a
+		d.Coverage.Diff = d.Coverage.B - a
+	}
+	d.RemovedFiles = rf
+}
+
+// RemovedFilesNote returns the note of the coverage change attributable to the files removed in the pull request
+func (d *DiffReport) RemovedFilesNote() string {
+	rf := d.RemovedFiles
+	if rf == nil {
+		return ""
+	}
+	diff := fmt.Sprintf("%.1f%%", rf.Diff)
+	if rf.Diff > 0 {
+		diff = fmt.Sprintf("+%.1f%%", rf.Diff)
+	}
+	files := "files"
+	if len(rf.Files) == 1 {
+		files = "file"
+	}
+	note := fmt.Sprintf("> **Note:** Removing %d %s ( %d lines, %d covered ) changes the coverage by %s.", len(rf.Files), files, rf.Total, rf.Covered, diff)
+	if rf.Excluded {
+		note = fmt.Sprintf("%s The coverage of the previous report excludes the removed files.", note)
+	}
+	return fmt.Sprintf("%s\n", note)
+}
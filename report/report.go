@@ -3,8 +3,10 @@ package report
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,26 +16,47 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/loc"
 	"github.com/k1LoW/octocov/pkg/ratio"
+	"github.com/k1LoW/octocov/pkg/testresult"
 	"github.com/olekukonko/tablewriter"
 )
 
-const filesHideMin = 30
+// FilesHideMin is the default number of the files above which the table of the files is collapsed
+const FilesHideMin = 30
 const filesSkipMax = 100
 
 type Report struct {
 	Repository        string             `json:"repository"`
 	Ref               string             `json:"ref"`
 	Commit            string             `json:"commit"`
+	CommitAuthor      string             `json:"commit_author,omitempty"`
+	PullRequestAuthor string             `json:"pull_request_author,omitempty"`
 	Coverage          *coverage.Coverage `json:"coverage,omitempty"`
+	CriticalCoverage  *CriticalCoverage  `json:"critical_coverage,omitempty"`
 	CodeToTestRatio   *ratio.Ratio       `json:"code_to_test_ratio,omitempty"`
+	LinesOfCode       *loc.LOC           `json:"lines_of_code,omitempty"`
 	TestExecutionTime *float64           `json:"test_execution_time,omitempty"`
-	Timestamp         time.Time          `json:"timestamp"`
+	TestCount         *int               `json:"test_count,omitempty"`
+	SummaryOnly       bool               `json:"summary_only,omitempty"`
+	// checksum of the coverage report file
+	CoverageChecksum string `json:"coverage_checksum,omitempty"`
+	// checksum of the contents of the files in the coverage report
+	SourceChecksum string    `json:"source_checksum,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
 	// coverage report path
 	rp string
+	// test cases of test report
+	testCases testresult.TestCases
+	// format of test execution time
+	timeFormat *DurationFormat
+	// number of the slowest test cases written by Out
+	showSlowest int
 }
 
+// New returns a report of the repository, the ref and the commit detected from the environment variables of GitHub Actions or the git repository of the working directory
 func New() (*Report, error) {
 	repo := os.Getenv("GITHUB_REPOSITORY")
 	ref := os.Getenv("GITHUB_REF")
@@ -41,7 +64,9 @@ func New() (*Report, error) {
 		b, err := os.ReadFile(".git/HEAD")
 		if err == nil {
 			splitted := strings.Split(strings.TrimSuffix(string(b), "\n"), " ")
-			ref = splitted[1]
+			if len(splitted) > 1 {
+				ref = splitted[1]
+			}
 		}
 	}
 	commit := os.Getenv("GITHUB_SHA")
@@ -52,13 +77,41 @@ func New() (*Report, error) {
 			commit = strings.TrimSuffix(string(b), "\n")
 		}
 	}
+	return NewWithParams(repo, ref, commit), nil
+}
 
+// NewWithParams returns a report of the repository, the ref and the commit without reading the environment variables or the working directory.
+// The measurement of the returned report does not depend on any shared state, so reports can be measured concurrently.
+func NewWithParams(repo, ref, commit string) *Report {
 	return &Report{
 		Repository: repo,
 		Ref:        ref,
 		Commit:     commit,
 		Timestamp:  time.Now().UTC(),
-	}, nil
+	}
+}
+
+// SetTimestampFromCommit sets the timestamp of the report to the committer time of the commit of the report
+func (r *Report) SetTimestampFromCommit(gitRoot string) error {
+	t, err := internal.GetCommitTime(gitRoot, r.Commit)
+	if err != nil {
+		return err
+	}
+	r.Timestamp = t
+	return nil
+}
+
+// SetAuthors sets the author of the commit and the author of the pull request ( when running on a pull request event ) of the report
+func (r *Report) SetAuthors(gitRoot string) error {
+	if e, err := gh.DecodeGitHubEvent(); err == nil && e.Number > 0 {
+		r.PullRequestAuthor = e.Author
+	}
+	a, err := internal.GetCommitAuthor(gitRoot, r.Commit)
+	if err != nil {
+		return err
+	}
+	r.CommitAuthor = a
+	return nil
 }
 
 func (r *Report) String() string {
@@ -84,14 +137,25 @@ func (r *Report) Table() string {
 		h = append(h, "Coverage")
 		m = append(m, fmt.Sprintf("%.1f%%", r.CoveragePercent()))
 	}
+	if r.CriticalCoverage != nil {
+		h = append(h, "Critical Coverage")
+		m = append(m, fmt.Sprintf("%.1f%%", r.CriticalCoverage.Percent()))
+	}
 	if r.CodeToTestRatio != nil {
 		h = append(h, "Code to Test Ratio")
 		m = append(m, fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio()))
 	}
+	if r.LinesOfCode != nil {
+		h = append(h, "Lines of Code")
+		m = append(m, r.LinesOfCode.String())
+	}
 	if r.TestExecutionTime != nil {
 		h = append(h, "Test Execution Time")
-		d := time.Duration(*r.TestExecutionTime)
-		m = append(m, d.String())
+		m = append(m, r.TestExecutionTimeString())
+	}
+	if r.TestCount != nil {
+		h = append(h, "Tests")
+		m = append(m, fmt.Sprintf("%d", *r.TestCount))
 	}
 	buf := new(bytes.Buffer)
 	table := tablewriter.NewWriter(buf)
@@ -121,19 +185,64 @@ func (r *Report) Out(w io.Writer) error {
 		table.Rich([]string{"Coverage", fmt.Sprintf("%.1f%%", r.CoveragePercent())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
 	}
 
+	if r.CriticalCoverage != nil {
+		table.Rich([]string{"Critical Coverage", fmt.Sprintf("%.1f%%", r.CriticalCoverage.Percent())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+
 	if r.CodeToTestRatio != nil {
 		table.Rich([]string{"Code to Test Ratio", fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+		for _, l := range r.CodeToTestRatioByLanguage() {
+			table.Rich([]string{fmt.Sprintf("Code to Test Ratio (%s)", l.Name), fmt.Sprintf("1:%.1f", l.Ratio())}, []tablewriter.Colors{tablewriter.Colors{}, tablewriter.Colors{}})
+		}
+	}
+
+	if r.LinesOfCode != nil {
+		table.Rich([]string{"Lines of Code", r.LinesOfCode.String()}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
 	}
 
 	if r.TestExecutionTime != nil {
-		table.Rich([]string{"Test Execution Time", time.Duration(*r.TestExecutionTime).String()}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+		table.Rich([]string{"Test Execution Time", r.TestExecutionTimeString()}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+
+	if r.TestCount != nil {
+		table.Rich([]string{"Tests", fmt.Sprintf("%d", *r.TestCount)}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+
+	table.Render()
+
+	if cases := r.SlowestTests(r.showSlowest); len(cases) > 0 {
+		if _, err := fmt.Fprintln(w, ""); err != nil {
+			return err
+		}
+		r.slowestTestsOut(w, cases)
 	}
+	return nil
+}
 
+// PackageCoveragesOut writes the coverage of each package ( the files grouped by the directory up to depth )
+func (r *Report) PackageCoveragesOut(w io.Writer, depth int) error {
+	if r.Coverage == nil {
+		return nil
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Packages", "Files", "Coverage"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("-")
+	table.SetHeaderLine(true)
+	table.SetBorder(false)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
+	for _, pc := range r.Coverage.PerPackage(depth) {
+		table.Append([]string{pc.Package, fmt.Sprintf("%d", pc.Files), fmt.Sprintf("%.1f%%", pc.CoveragePercent())})
+	}
 	table.Render()
 	return nil
 }
 
-func (r *Report) FileCoveagesTable(files []*gh.PullRequestFile) string {
+// FileCoveagesTable renders the coverages of the files in the pull request. The table is collapsed when there are more than hideMin files.
+func (r *Report) FileCoveagesTable(files []*gh.PullRequestFile, hideMin int) string {
 	if r.Coverage == nil {
 		return ""
 	}
@@ -174,7 +283,7 @@ func (r *Report) FileCoveagesTable(files []*gh.PullRequestFile) string {
 		return buf.String()
 	}
 
-	if len(rows) > filesHideMin {
+	if len(rows) > hideMin {
 		buf.WriteString("<details>\n\n")
 	}
 
@@ -190,13 +299,124 @@ func (r *Report) FileCoveagesTable(files []*gh.PullRequestFile) string {
 	}
 	table.Render()
 
-	if len(rows) > filesHideMin {
+	if len(rows) > hideMin {
 		buf.WriteString("\n</details>\n")
 	}
 
 	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
 }
 
+// UnresolvedFiles returns the files in the coverage report that do not exist on disk, and the number of files in the coverage report
+func (r *Report) UnresolvedFiles(gitRoot, wd string) ([]string, int, error) {
+	if r.Coverage == nil || len(r.Coverage.Files) == 0 {
+		return []string{}, 0, nil
+	}
+	_, unresolved, err := r.resolveFiles(gitRoot, wd)
+	if err != nil {
+		return nil, 0, err
+	}
+	return unresolved, len(r.Coverage.Files), nil
+}
+
+// resolveFiles returns the paths on disk of the files in the coverage report, and the files that do not exist on disk
+func (r *Report) resolveFiles(gitRoot, wd string) (map[string]string, []string, error) {
+	resolved := map[string]string{}
+	unresolved := []string{}
+	if r.Coverage == nil || len(r.Coverage.Files) == 0 {
+		return resolved, unresolved, nil
+	}
+	files, err := internal.ListFiles(gitRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfiles := []string{}
+	for _, f := range r.Coverage.Files {
+		cfiles = append(cfiles, f.File)
+	}
+	prefix := internal.DetectPrefix(gitRoot, wd, files, cfiles)
+	exists := func(p string) bool {
+		fi, err := os.Stat(p)
+		return err == nil && !fi.IsDir()
+	}
+	for _, f := range r.Coverage.Files {
+		p := filepath.Clean(f.File)
+		candidates := []string{p, filepath.Join(wd, p), filepath.Join(gitRoot, p)}
+		if prefix != "" && strings.HasPrefix(p, prefix) {
+			candidates = append(candidates, filepath.Join(wd, strings.TrimPrefix(strings.TrimPrefix(p, prefix), "/")))
+		}
+		ok := false
+		for _, c := range candidates {
+			if exists(c) {
+				resolved[f.File] = c
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			unresolved = append(unresolved, f.File)
+		}
+	}
+	return resolved, unresolved, nil
+}
+
+type UncoveredNewFile struct {
+	Filename string
+	BlobURL  string
+	Coverage float64
+}
+
+// UncoveredNewFiles returns the files newly added in the pull request whose coverage is 0% or below the threshold
+func (r *Report) UncoveredNewFiles(files []*gh.PullRequestFile, threshold float64) []*UncoveredNewFile {
+	uncovered := []*UncoveredNewFile{}
+	if r.Coverage == nil {
+		return uncovered
+	}
+	for _, f := range files {
+		if !f.IsAdded() {
+			continue
+		}
+		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
+		if err != nil {
+			continue
+		}
+		cover := 0.0
+		if fc.Total > 0 {
+			cover = float64(fc.Covered) / float64(fc.Total) * 100
+		}
+		if cover > 0 && cover >= threshold {
+			continue
+		}
+		uncovered = append(uncovered, &UncoveredNewFile{
+			Filename: f.Filename,
+			BlobURL:  f.BlobURL,
+			Coverage: cover,
+		})
+	}
+	return uncovered
+}
+
+func (r *Report) UncoveredNewFilesTable(files []*gh.PullRequestFile, threshold float64) string {
+	uncovered := r.UncoveredNewFiles(files, threshold)
+	if len(uncovered) == 0 {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("### New files without sufficient coverage (%d)\n\n", len(uncovered)))
+	table := tablewriter.NewWriter(buf)
+	h := []string{"Files", "Coverage"}
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	for _, f := range uncovered {
+		table.Append([]string{fmt.Sprintf("[%s](%s)", f.Filename, f.BlobURL), fmt.Sprintf("%.1f%%", f.Coverage)})
+	}
+	table.Render()
+
+	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
+}
+
 func (r *Report) CountMeasured() int {
 	c := 0
 	if r.IsMeasuredCoverage() {
@@ -208,6 +428,9 @@ func (r *Report) CountMeasured() int {
 	if r.IsMeasuredTestExecutionTime() {
 		c += 1
 	}
+	if r.IsMeasuredLinesOfCode() {
+		c += 1
+	}
 	return c
 }
 
@@ -215,6 +438,10 @@ func (r *Report) IsMeasuredCoverage() bool {
 	return r.Coverage != nil
 }
 
+func (r *Report) IsMeasuredCriticalCoverage() bool {
+	return r.CriticalCoverage != nil
+}
+
 func (r *Report) IsMeasuredCodeToTestRatio() bool {
 	return r.CodeToTestRatio != nil
 }
@@ -223,8 +450,61 @@ func (r *Report) IsMeasuredTestExecutionTime() bool {
 	return r.TestExecutionTime != nil
 }
 
-func (r *Report) MeasureCoverage(path string) error {
-	cov, rp, cerr := challengeParseReport(path)
+func (r *Report) IsMeasuredLinesOfCode() bool {
+	return r.LinesOfCode != nil
+}
+
+// MeasureCoverage measures the coverage of the coverage report.
+// The path can be the comma-separated paths or the glob pattern of the coverage reports ( e.g. the reports of test shards ), and they are merged.
+// The coverage report is read from stdin when the path is "-".
+// The parsers ps are tried before the registered parsers ( e.g. the per-file parser configured by the config ).
+func (r *Report) MeasureCoverage(path string, ps ...coverage.Processor) error {
+	if path == StdinPath {
+		dir, err := os.MkdirTemp("", "octocov")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.RemoveAll(dir)
+		}()
+		p, err := readCoverageReportFromStdin(stdin, dir)
+		if err != nil {
+			return err
+		}
+		if err := r.MeasureCoverage(p, ps...); err != nil {
+			return err
+		}
+		// the temporary file is removed, so the report has no coverage report file
+		r.rp = ""
+		return nil
+	}
+	paths, err := coverageReportPaths(path)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 1 {
+		return r.measureMergedCoverage(paths, ps...)
+	}
+	path = paths[0]
+	if isURL(path) {
+		dir, err := os.MkdirTemp("", "octocov")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.RemoveAll(dir)
+		}()
+		p, err := downloadCoverageReport(context.Background(), path, dir)
+		if err != nil {
+			return err
+		}
+		if err := r.MeasureCoverage(p, ps...); err != nil {
+			return err
+		}
+		r.rp = path
+		return nil
+	}
+	cov, rp, cerr := challengeParseReport(path, ps...)
 	if cerr != nil {
 		f, err := os.Stat(path)
 		if err != nil || f.IsDir() {
@@ -242,15 +522,55 @@ func (r *Report) MeasureCoverage(path string) error {
 	}
 	r.Coverage = cov
 	r.rp = rp
+	if fi, err := os.Stat(rp); err == nil && !fi.IsDir() {
+		cs, err := fileChecksum(rp)
+		if err != nil {
+			return err
+		}
+		r.CoverageChecksum = cs
+	}
+	return nil
+}
+
+// MeasureCriticalCoverage measures the coverage of only the files matched by the paths ( glob patterns )
+func (r *Report) MeasureCriticalCoverage(paths []string) error {
+	if r.Coverage == nil {
+		return errors.New("coverage is not measured")
+	}
+	cc, err := measureCriticalCoverage(r.Coverage, paths)
+	if err != nil {
+		return err
+	}
+	r.CriticalCoverage = cc
+	return nil
+}
+
+// MeasureCodeToTestRatio measures the code to test ratio of the files under root except the files matched by exclude ( the patterns of the absolute paths ).
+// The code to test ratio of each language is also measured when languages are set.
+// Only the logical lines are counted when logical is true.
+func (r *Report) MeasureCodeToTestRatio(root string, code, test, exclude []string, languages []*ratio.Language, logical bool) error {
+	rt, err := ratio.Measure(root, code, test, exclude, logical)
+	if err != nil {
+		return err
+	}
+	if len(languages) > 0 {
+		lrs, err := ratio.MeasureLanguages(root, languages, exclude, logical)
+		if err != nil {
+			return err
+		}
+		rt.Languages = lrs
+	}
+	r.CodeToTestRatio = rt
 	return nil
 }
 
-func (r *Report) MeasureCodeToTestRatio(code, test []string) error {
-	ratio, err := ratio.Measure(".", code, test)
+// MeasureLinesOfCode measures the lines of code of the files under root
+func (r *Report) MeasureLinesOfCode(root string, exclude []string) error {
+	l, err := loc.Measure(root, exclude)
 	if err != nil {
 		return err
 	}
-	r.CodeToTestRatio = ratio
+	r.LinesOfCode = l
 	return nil
 }
 
@@ -258,6 +578,10 @@ func (r *Report) MeasureTestExecutionTime(ctx context.Context, stepNames []strin
 	if r.Repository == "" {
 		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
 	}
+	if len(stepNames) == 0 && (r.rp == "" || isURL(r.rp)) {
+		// the step of the tests is detected by the modification time of the coverage report file
+		return errors.New("can not detect the step of the tests without the coverage report file ( e.g. the coverage report is read from stdin or a URL ): set testExecutionTime.steps: or testExecutionTime.path:")
+	}
 	splitted := strings.Split(r.Repository, "/")
 	owner := splitted[0]
 	repo := splitted[1]
@@ -296,6 +620,48 @@ func (r *Report) MeasureTestExecutionTime(ctx context.Context, stepNames []strin
 	return nil
 }
 
+// MeasureTestExecutionTimeByStepPatterns measures test execution time using the steps whose names match the patterns.
+// It returns the names of the summed steps.
+func (r *Report) MeasureTestExecutionTimeByStepPatterns(ctx context.Context, include, exclude []string) ([]string, error) {
+	if r.Repository == "" {
+		return nil, fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	owner, repo, err := gh.SplitRepository(r.Repository)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return nil, err
+	}
+	steps, err := g.GetStepsByPatterns(ctx, owner, repo, include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, s := range steps {
+		names = append(names, s.Name)
+	}
+	d := mergeExecutionTimes(steps)
+	t := float64(d)
+	r.TestExecutionTime = &t
+	return names, nil
+}
+
+// MeasureTestResults measures test execution time and test count using test reports ( e.g. Surefire XML reports )
+func (r *Report) MeasureTestResults(path string) error {
+	t, _, err := testresult.ParseReport(path)
+	if err != nil {
+		return err
+	}
+	d := t.Time
+	c := t.Tests
+	r.TestExecutionTime = &d
+	r.TestCount = &c
+	r.testCases = t.Cases
+	return nil
+}
+
 func (r *Report) CoveragePercent() float64 {
 	if r.Coverage == nil || r.Coverage.Total == 0 {
 		return 0.0
@@ -303,6 +669,23 @@ func (r *Report) CoveragePercent() float64 {
 	return float64(r.Coverage.Covered) / float64(r.Coverage.Total) * 100
 }
 
+// CoverageTrend returns ▲ or ▼ when the coverage ( rounded to 0.1% ) is changed from the previous report, otherwise ""
+func (r *Report) CoverageTrend(rPrev *Report) string {
+	if rPrev == nil || !r.IsMeasuredCoverage() || !rPrev.IsMeasuredCoverage() {
+		return ""
+	}
+	cur := math.Round(r.CoveragePercent() * 10)
+	prev := math.Round(rPrev.CoveragePercent() * 10)
+	switch {
+	case cur > prev:
+		return "▲"
+	case cur < prev:
+		return "▼"
+	default:
+		return ""
+	}
+}
+
 func (r *Report) CodeToTestRatioRatio() float64 {
 	if r.CodeToTestRatio.Code == 0 {
 		return 0.0
@@ -310,6 +693,79 @@ func (r *Report) CodeToTestRatioRatio() float64 {
 	return float64(r.CodeToTestRatio.Test) / float64(r.CodeToTestRatio.Code)
 }
 
+// CodeToTestRatioByLanguage returns the code to test ratio of each language ( codeToTestRatio.languages )
+func (r *Report) CodeToTestRatioByLanguage() []*ratio.LanguageRatio {
+	if r.CodeToTestRatio == nil || r.CodeToTestRatio.Languages == nil {
+		return []*ratio.LanguageRatio{}
+	}
+	return r.CodeToTestRatio.Languages
+}
+
+// SlowestTests returns the n slowest test cases of the test reports ( testExecutionTime.path )
+func (r *Report) SlowestTests(n int) testresult.TestCases {
+	if r.TestExecutionTime == nil || n <= 0 {
+		return testresult.TestCases{}
+	}
+	return r.testCases.Slowest(n)
+}
+
+// SetShowSlowest sets the number of the slowest test cases written by Out ( testExecutionTime.showSlowest )
+func (r *Report) SetShowSlowest(n int) {
+	r.showSlowest = n
+}
+
+func (r *Report) slowestTestsOut(w io.Writer, cases testresult.TestCases) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Slowest Tests", "Time"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("-")
+	table.SetHeaderLine(true)
+	table.SetBorder(false)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT})
+	for _, tc := range cases {
+		table.Append([]string{tc.FullName(), r.timeFormat.Format(tc.Duration())})
+	}
+	table.Render()
+}
+
+// SlowestTestsTable renders the n slowest test cases for the comment
+func (r *Report) SlowestTestsTable(n int) string {
+	cases := r.SlowestTests(n)
+	if len(cases) == 0 {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("### Slowest tests (%d)\n\n", len(cases)))
+	table := tablewriter.NewWriter(buf)
+	h := []string{"Tests", "Time"}
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	for _, tc := range cases {
+		table.Append([]string{fmt.Sprintf("`%s`", tc.FullName()), r.timeFormat.Format(tc.Duration())})
+	}
+	table.Render()
+
+	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
+}
+
+// SetTestExecutionTimeFormat sets the format of test execution time in tables and comparisons
+func (r *Report) SetTestExecutionTimeFormat(f *DurationFormat) {
+	r.timeFormat = f
+}
+
+func (r *Report) TestExecutionTimeString() string {
+	if r.TestExecutionTime == nil {
+		return ""
+	}
+	return r.timeFormat.Format(time.Duration(*r.TestExecutionTime))
+}
+
 func (r *Report) Validate() error {
 	if r.Repository == "" {
 		return fmt.Errorf("coverage report '%s' (env %s) is not set", "repository", "GITHUB_REPOSITORY")
@@ -340,6 +796,9 @@ func (r *Report) Compare(r2 *Report) *DiffReport {
 	if r.CodeToTestRatio != nil {
 		d.CodeToTestRatio = r.CodeToTestRatio.Compare(r2.CodeToTestRatio)
 	}
+	if r.LinesOfCode != nil {
+		d.LinesOfCode = r.LinesOfCode.Compare(r2.LinesOfCode)
+	}
 	if r.TestExecutionTime != nil {
 		dt := &DiffTestExecutionTime{
 			A:                  r.TestExecutionTime,
@@ -401,26 +860,6 @@ func mergeExecutionTimes(steps []gh.Step) time.Duration {
 	return d
 }
 
-func challengeParseReport(path string) (*coverage.Coverage, string, error) {
-	// gocover
-	if cov, rp, err := coverage.NewGocover().ParseReport(path); err == nil {
-		return cov, rp, nil
-	}
-	// lcov
-	if cov, rp, err := coverage.NewLcov().ParseReport(path); err == nil {
-		return cov, rp, nil
-	}
-	// simplecov
-	if cov, rp, err := coverage.NewSimplecov().ParseReport(path); err == nil {
-		return cov, rp, nil
-	}
-	// clover
-	if cov, rp, err := coverage.NewClover().ParseReport(path); err == nil {
-		return cov, rp, nil
-	}
-	// cobertura
-	if cov, rp, err := coverage.NewCobertura().ParseReport(path); err == nil {
-		return cov, rp, nil
-	}
-	return nil, "", fmt.Errorf("coverage report not found: %s", path)
+func challengeParseReport(path string, ps ...coverage.Processor) (*coverage.Coverage, string, error) {
+	return coverage.ParseReport(path, ps...)
 }
@@ -1,12 +1,22 @@
 package report
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/pkg/coverage"
+	"github.com/k1LoW/octocov/pkg/ratio"
 )
 
 func TestTable(t *testing.T) {
@@ -46,6 +56,64 @@ func TestTable(t *testing.T) {
 	}
 }
 
+func TestPackageCoveragesOut(t *testing.T) {
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				&coverage.FileCoverage{File: "pkg/a/a.go", Total: 10, Covered: 10},
+				&coverage.FileCoverage{File: "pkg/b/b.go", Total: 10, Covered: 5},
+			},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := r.PackageCoveragesOut(buf, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"pkg/a", "100.0%", "pkg/b", "50.0%"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("got %v\nwant %v", buf.String(), want)
+		}
+	}
+}
+
+func TestSlowestTests(t *testing.T) {
+	r := &Report{}
+	if err := r.MeasureTestResults(filepath.Join(testdataDir(t), "..", "pkg", "testresult", "testdata", "surefire")); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(r.SlowestTests(2)); got != 2 {
+		t.Errorf("got %v\nwant %v", got, 2)
+	}
+	buf := new(bytes.Buffer)
+	if err := r.Out(buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Slowest Tests") {
+		t.Errorf("got %v", buf.String())
+	}
+	r.SetShowSlowest(2)
+	buf.Reset()
+	if err := r.Out(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"com.example.StringUtilsTest.testReverse", "16m40s", "com.example.StringUtilsTest.testTrim", "2.5s"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("got %v\nwant %v", buf.String(), want)
+		}
+	}
+	if got := r.SlowestTestsTable(2); !strings.Contains(got, "### Slowest tests (2)") || strings.Contains(got, "testAdd") {
+		t.Errorf("got %v", got)
+	}
+
+	selected, err := r.SelectMetrics([]string{MetricCoverage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := selected.SlowestTestsTable(2); got != "" {
+		t.Errorf("got %v\nwant %v", got, "")
+	}
+}
+
 func TestFileCoveagesTable(t *testing.T) {
 	tests := []struct {
 		files []*gh.PullRequestFile
@@ -68,10 +136,19 @@ func TestFileCoveagesTable(t *testing.T) {
 		t.Fatal(err)
 	}
 	for _, tt := range tests {
-		if got := r.FileCoveagesTable(tt.files); got != tt.want {
+		if got := r.FileCoveagesTable(tt.files, FilesHideMin); got != tt.want {
 			t.Errorf("got\n%v\nwant\n%v", got, tt.want)
 		}
 	}
+
+	// collapsed when there are more than hideMin files
+	files := []*gh.PullRequestFile{{Filename: "config/yaml.go", BlobURL: "https://github.com/owner/repo/blob/xxx/config/yaml.go"}}
+	got := r.FileCoveagesTable(files, 0)
+	for _, want := range []string{"### Code coverage of files in pull request scope (41.7%)\n\n<details>\n\n", "\n</details>\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got\n%v\nwant\n%v", got, want)
+		}
+	}
 }
 
 func TestMergeExecutionTimes(t *testing.T) {
@@ -187,6 +264,410 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestSetAuthors(t *testing.T) {
+	origName := os.Getenv("GITHUB_EVENT_NAME")
+	origPath := os.Getenv("GITHUB_EVENT_PATH")
+	t.Cleanup(func() {
+		_ = os.Setenv("GITHUB_EVENT_NAME", origName)
+		_ = os.Setenv("GITHUB_EVENT_PATH", origPath)
+	})
+	p := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(p, []byte(`{"pull_request":{"number":3,"state":"open","user":{"login":"alice"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	_ = os.Setenv("GITHUB_EVENT_PATH", p)
+
+	r := &Report{}
+	// not a git repository
+	if err := r.SetAuthors(t.TempDir()); err == nil {
+		t.Error("want error")
+	}
+	if got, want := r.PullRequestAuthor, "alice"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got := r.CommitAuthor; got != "" {
+		t.Errorf("got %v\nwant %v", got, "")
+	}
+}
+
+func TestMeasureCoverageFromURL(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(filepath.Dir(testdataDir(t)), "pkg", "coverage", "testdata", "lcov", "lcov.info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/artifacts/lcov.info" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(ts.Close)
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{ts.URL + "/artifacts/lcov.info", "LCOV", false},
+		{ts.URL + "/artifacts/notfound.info", "", true},
+	}
+	for _, tt := range tests {
+		r := &Report{}
+		if err := r.MeasureCoverage(tt.path); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got := r.Coverage.Format; got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if got := r.rp; got != tt.path {
+			t.Errorf("got %v\nwant %v", got, tt.path)
+		}
+	}
+}
+
+func TestMeasureCoverageFromStdin(t *testing.T) {
+	lcov, err := os.ReadFile(filepath.Join(filepath.Dir(testdataDir(t)), "pkg", "coverage", "testdata", "lcov", "lcov.info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gocover, err := os.ReadFile(filepath.Join(filepath.Dir(testdataDir(t)), "pkg", "coverage", "testdata", "gocover", "coverage.out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := stdin
+	t.Cleanup(func() {
+		stdin = orig
+	})
+
+	tests := []struct {
+		in      []byte
+		want    string
+		wantErr bool
+	}{
+		{lcov, "LCOV", false},
+		{gocover, "Go coverage", false},
+		{[]byte{}, "", true},
+	}
+	for _, tt := range tests {
+		stdin = bytes.NewReader(tt.in)
+		r := &Report{}
+		if err := r.MeasureCoverage(StdinPath); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got := r.Coverage.Format; got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if got := r.rp; got != "" {
+			t.Errorf("got %v\nwant %v", got, "")
+		}
+	}
+}
+
+func TestMeasureTestExecutionTimeWithoutCoverageReportFile(t *testing.T) {
+	tests := []struct {
+		rp string
+	}{
+		{""},
+		{"https://example.com/coverage.out"},
+	}
+	for _, tt := range tests {
+		r := &Report{Repository: "owner/repo", rp: tt.rp}
+		err := r.MeasureTestExecutionTime(context.Background(), nil)
+		if err == nil {
+			t.Errorf("got %v\nwant error", err)
+			continue
+		}
+		if !strings.Contains(err.Error(), "testExecutionTime.steps:") {
+			t.Errorf("got %v\nwant the message to set testExecutionTime.steps:", err)
+		}
+	}
+}
+
+func TestMeasureMergedCoverage(t *testing.T) {
+	dir := t.TempDir()
+	shards := map[string]string{
+		"shard-1": "mode: count\ngithub.com/owner/repo/a.go:1.1,3.2 2 1\ngithub.com/owner/repo/a.go:4.1,5.2 1 0\n",
+		"shard-2": "mode: count\ngithub.com/owner/repo/a.go:1.1,3.2 2 0\ngithub.com/owner/repo/a.go:4.1,5.2 1 3\ngithub.com/owner/repo/b.go:1.1,1.2 1 0\n",
+	}
+	for d, p := range shards {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, d, "coverage.out"), []byte(p), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		path        string
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{filepath.Join(dir, "shard-1", "coverage.out"), 3, 2, false},
+		{filepath.Join(dir, "shard-1", "coverage.out") + "," + filepath.Join(dir, "shard-2", "coverage.out"), 4, 3, false},
+		{filepath.Join(dir, "shard-*", "coverage.out"), 4, 3, false},
+		{filepath.Join(dir, "**", "coverage.out"), 4, 3, false},
+		{filepath.Join(dir, "notfound-*", "coverage.out"), 0, 0, true},
+		{filepath.Join(dir, "shard-1", "coverage.out") + "," + filepath.Join(filepath.Dir(testdataDir(t)), "pkg", "coverage", "testdata", "lcov", "lcov.info"), 0, 0, true},
+	}
+	for _, tt := range tests {
+		r := &Report{}
+		err := r.MeasureCoverage(tt.path)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if r.Coverage.Total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", r.Coverage.Total, tt.wantTotal)
+		}
+		if r.Coverage.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", r.Coverage.Covered, tt.wantCovered)
+		}
+		if r.CoverageChecksum == "" {
+			t.Error("coverage checksum is not set")
+		}
+	}
+}
+
+func TestCoverageReportPaths(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"coverage.out", []string{"coverage.out"}},
+		{"https://example.com/coverage.out", []string{"https://example.com/coverage.out"}},
+		{"coverage.out, https://example.com/coverage.out", []string{"coverage.out", "https://example.com/coverage.out"}},
+		{"https://example.com/a.out,https://example.com/b.out", []string{"https://example.com/a.out", "https://example.com/b.out"}},
+		{coverage.ExecPrefix + "cat a.out,b.out", []string{coverage.ExecPrefix + "cat a.out,b.out"}},
+	}
+	for _, tt := range tests {
+		got, err := coverageReportPaths(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s: %s", tt.path, diff)
+		}
+	}
+}
+
+func TestMeasureMergedCoverageWithDifferentCovermodes(t *testing.T) {
+	dir := t.TempDir()
+	shards := map[string]string{
+		"set.out":   "mode: set\ngithub.com/owner/repo/a.go:1.1,3.2 2 1\ngithub.com/owner/repo/a.go:4.1,5.2 1 0\n",
+		"count.out": "mode: count\ngithub.com/owner/repo/a.go:1.1,3.2 2 5\ngithub.com/owner/repo/a.go:4.1,5.2 1 3\n",
+	}
+	for n, p := range shards {
+		if err := os.WriteFile(filepath.Join(dir, n), []byte(p), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &Report{}
+	if err := r.MeasureCoverage(filepath.Join(dir, "set.out") + "," + filepath.Join(dir, "count.out")); err != nil {
+		t.Fatal(err)
+	}
+	if r.Coverage.Covered != 3 {
+		t.Errorf("got %v\nwant %v", r.Coverage.Covered, 3)
+	}
+	fc, err := r.Coverage.Files.FindByFile("github.com/owner/repo/a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range fc.Blocks {
+		if *b.Count != 1 {
+			t.Errorf("got %v\nwant %v", *b.Count, 1)
+		}
+	}
+}
+
+func TestMeasureCoverageConcurrently(t *testing.T) {
+	ctd := filepath.Join(filepath.Dir(testdataDir(t)), "pkg", "coverage", "testdata")
+	paths := []string{
+		filepath.Join(ctd, "gocover", "coverage.out"),
+		filepath.Join(ctd, "lcov", "lcov.info"),
+		filepath.Join(ctd, "simplecov", ".resultset.json"),
+		filepath.Join(ctd, "clover", "coverage.xml"),
+		filepath.Join(ctd, "cobertura", "coverage.xml"),
+		filepath.Join(testdataDir(t), "reports", "k1LoW", "tbls", "report.json"),
+	}
+	want := map[string]*Report{}
+	for _, p := range paths {
+		r := NewWithParams("owner/repo", "refs/heads/main", "abcdef")
+		if err := r.MeasureCoverage(p); err != nil {
+			t.Fatal(err)
+		}
+		want[p] = r
+	}
+
+	// the rounds are few because the deep compare of each result is slow
+	const n = 2
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		for _, p := range paths {
+			wg.Add(1)
+			go func(p string) {
+				defer wg.Done()
+				r := NewWithParams("owner/repo", "refs/heads/main", "abcdef")
+				if err := r.MeasureCoverage(p); err != nil {
+					t.Error(err)
+					return
+				}
+				opts := []cmp.Option{
+					cmpopts.IgnoreUnexported(coverage.FileCoverage{}),
+					cmpopts.SortSlices(func(i, j *coverage.FileCoverage) bool {
+						return i.File < j.File
+					}),
+				}
+				if diff := cmp.Diff(r.Coverage, want[p].Coverage, opts...); diff != "" {
+					t.Errorf("%s: %s", p, diff)
+				}
+				if r.rp != want[p].rp {
+					t.Errorf("got %v\nwant %v", r.rp, want[p].rp)
+				}
+			}(p)
+		}
+	}
+	wg.Wait()
+}
+
+func TestUncoveredNewFiles(t *testing.T) {
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/new.go", Total: 10, Covered: 0},
+				{File: "github.com/owner/repo/new_partial.go", Total: 10, Covered: 3},
+				{File: "github.com/owner/repo/new_covered.go", Total: 10, Covered: 10},
+				{File: "github.com/owner/repo/modified.go", Total: 10, Covered: 0},
+			},
+		},
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "new.go", Status: "added"},
+		{Filename: "new_partial.go", Status: "added"},
+		{Filename: "new_covered.go", Status: "added"},
+		{Filename: "modified.go", Status: "modified"},
+		{Filename: "not_measured.md", Status: "added"},
+	}
+	tests := []struct {
+		threshold float64
+		want      []string
+	}{
+		{0, []string{"new.go"}},
+		{50, []string{"new.go", "new_partial.go"}},
+		{100, []string{"new.go", "new_partial.go"}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, f := range r.UncoveredNewFiles(files, tt.threshold) {
+			got = append(got, f.Filename)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestUnresolvedFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"a.go", filepath.Join("pkg", "b.go")} {
+		p := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("package a"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/a.go"},
+				{File: "github.com/owner/repo/pkg/b.go"},
+				{File: "github.com/owner/repo/pkg/missing.go"},
+			},
+		},
+	}
+	got, total, err := r.UnresolvedFiles(root, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 3; total != want {
+		t.Errorf("got %v\nwant %v", total, want)
+	}
+	if len(got) != 1 || got[0] != "github.com/owner/repo/pkg/missing.go" {
+		t.Errorf("got %v\nwant %v", got, []string{"github.com/owner/repo/pkg/missing.go"})
+	}
+}
+
+func TestSourceChecksum(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "a.go")
+	cp := filepath.Join(root, "coverage.out")
+	if err := os.WriteFile(src, []byte("package a\n\nfunc A() {\n}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cp, []byte("mode: set\ngithub.com/owner/repo/a.go:3.10,4.2 0 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	measure := func() *Report {
+		r := &Report{}
+		if err := r.MeasureCoverage(cp); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.MeasureSourceChecksum(root, root); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	prev := measure()
+	if prev.CoverageChecksum == "" || prev.SourceChecksum == "" {
+		t.Errorf("got %v and %v\nwant %v", prev.CoverageChecksum, prev.SourceChecksum, "checksums")
+	}
+	if got := measure(); got.IsStaleCoverage(prev) {
+		t.Errorf("got %v\nwant %v", true, false)
+	}
+
+	if err := os.WriteFile(src, []byte("package a\n\nfunc A() {\n}\n\nfunc B() {\n}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := measure(); !got.IsStaleCoverage(prev) {
+		t.Errorf("got %v\nwant %v", false, true)
+	}
+
+	if err := os.WriteFile(cp, []byte("mode: set\ngithub.com/owner/repo/a.go:3.10,4.2 0 1\ngithub.com/owner/repo/a.go:6.10,7.2 0 0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := measure(); got.IsStaleCoverage(prev) {
+		t.Errorf("got %v\nwant %v", true, false)
+	}
+	if got := measure(); got.IsStaleCoverage(&Report{}) {
+		t.Errorf("got %v\nwant %v", true, false)
+	}
+}
+
 func testdataDir(t *testing.T) string {
 	t.Helper()
 	wd, err := os.Getwd()
@@ -199,3 +680,653 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestMeasureCriticalCoverage(t *testing.T) {
+	cov := &coverage.Coverage{
+		Files: coverage.FileCoverages{
+			{File: "github.com/owner/repo/internal/auth/token.go", Total: 10, Covered: 9},
+			{File: "github.com/owner/repo/internal/auth/session.go", Total: 10, Covered: 10},
+			{File: "github.com/owner/repo/cmd/root.go", Total: 80, Covered: 10},
+		},
+	}
+	tests := []struct {
+		paths   []string
+		want    *CriticalCoverage
+		wantErr bool
+	}{
+		{[]string{"internal/auth/**"}, &CriticalCoverage{Paths: []string{"internal/auth/**"}, Total: 20, Covered: 19, Files: 2}, false},
+		{[]string{"./internal/auth/token.go", "cmd/*.go"}, &CriticalCoverage{Paths: []string{"./internal/auth/token.go", "cmd/*.go"}, Total: 90, Covered: 19, Files: 2}, false},
+		{[]string{"pkg/**"}, nil, true},
+	}
+	for _, tt := range tests {
+		r := &Report{Coverage: cov}
+		if err := r.MeasureCriticalCoverage(tt.paths); err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", r.CriticalCoverage)
+			continue
+		}
+		if diff := cmp.Diff(r.CriticalCoverage, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestExcludeCoverageFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module github.com/owner/repo\n\ngo 1.16\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		patterns    []string
+		wantFiles   int
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{nil, 5, 140, 34, false},
+		{[]string{"**/*.pb.go"}, 4, 120, 34, false},
+		{[]string{"vendor/**", "gen/**"}, 4, 120, 24, false},
+		{[]string{"**/vendor/**"}, 3, 110, 19, false},
+		{[]string{"./internal/**"}, 4, 130, 25, false},
+		{[]string{"cmd/*.go"}, 4, 60, 24, false},
+		{[]string{"root.go"}, 5, 140, 34, false},
+		{[]string{"**"}, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		r := &Report{Coverage: &coverage.Coverage{
+			Total:   140,
+			Covered: 34,
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/cmd/root.go", Total: 80, Covered: 10},
+				{File: "github.com/owner/repo/api/api.pb.go", Total: 20, Covered: 0},
+				{File: filepath.Join(root, "vendor", "lib", "lib.go"), Total: 20, Covered: 10},
+				{File: "internal/auth/token.go", Total: 10, Covered: 9},
+				{File: "github.com/owner/repo/pkg/vendor/v.go", Total: 10, Covered: 5},
+			},
+		}}
+		err := r.ExcludeCoverageFiles(root, tt.patterns)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := len(r.Coverage.Files); got != tt.wantFiles {
+			t.Errorf("got %v\nwant %v", got, tt.wantFiles)
+		}
+		if r.Coverage.Total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", r.Coverage.Total, tt.wantTotal)
+		}
+		if r.Coverage.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", r.Coverage.Covered, tt.wantCovered)
+		}
+	}
+}
+
+func TestDurationFormat(t *testing.T) {
+	zero := 0
+	three := 3
+	d := 62345 * time.Millisecond
+	tests := []struct {
+		unit      string
+		precision *int
+		want      string
+		wantErr   bool
+	}{
+		{"s", nil, "62.3s", false},
+		{"s", &zero, "62s", false},
+		{"ms", nil, "62345ms", false},
+		{"ms", &three, "62345.000ms", false},
+		{"auto", nil, "1m2.3s", false},
+		{"auto", &zero, "1m2s", false},
+		{"min", nil, "", true},
+	}
+	for _, tt := range tests {
+		f, err := NewDurationFormat(tt.unit, tt.precision)
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", f)
+			continue
+		}
+		if got := f.Format(d); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+
+	var f *DurationFormat
+	if got := f.Format(d); got != "1m2.345s" {
+		t.Errorf("got %v\nwant %v", got, "1m2.345s")
+	}
+}
+
+func TestSelectMetrics(t *testing.T) {
+	tet := float64(time.Minute)
+	r := &Report{
+		Coverage:          &coverage.Coverage{Total: 100, Covered: 50},
+		CodeToTestRatio:   &ratio.Ratio{Code: 100, Test: 50},
+		TestExecutionTime: &tet,
+	}
+	tests := []struct {
+		metrics []string
+		want    string
+		wantErr bool
+	}{
+		{
+			[]string{},
+			`| Coverage | Code to Test Ratio | Test Execution Time |
+|---------:|-------------------:|--------------------:|
+| 50.0%    | 1:0.5              | 1m0s                |
+`,
+			false,
+		},
+		{
+			[]string{"coverage", "ratio"},
+			`| Coverage | Code to Test Ratio |
+|---------:|-------------------:|
+| 50.0%    | 1:0.5              |
+`,
+			false,
+		},
+		{
+			[]string{"time"},
+			`| Test Execution Time |
+|--------------------:|
+| 1m0s                |
+`,
+			false,
+		},
+		{[]string{"coverage", "duration"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := r.SelectMetrics(tt.metrics)
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", got)
+			continue
+		}
+		if got.Table() != tt.want {
+			t.Errorf("got\n%v\nwant\n%v", got.Table(), tt.want)
+		}
+	}
+	if r.TestExecutionTime == nil {
+		t.Error("the original report should not be changed")
+	}
+}
+
+func TestParseCoveragePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"# comment\ninternal/** 80% @org/platform\n\n**/*_gen.go 0\n", 2, false},
+		{"./cmd/** 50% @alice me@example.com\n", 1, false},
+		{"internal/** 80%\ninternal/** 80% @org/platform\n", 2, false},
+		{"internal/** 80%\ninternal/** 70%\n", 2, false},
+		{"internal/**\n", 0, true},
+		{"internal/** 101%\n", 0, true},
+		{"internal/** 80% platform\n", 0, true},
+		{"internal/[ 80%\n", 0, true},
+		{"# comment only\n", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCoveragePolicy(strings.NewReader(tt.in))
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%q: %v", tt.in, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%q: want err", tt.in)
+			continue
+		}
+		if len(got.Rules) != tt.want {
+			t.Errorf("got %v\nwant %v", len(got.Rules), tt.want)
+		}
+	}
+}
+
+func TestPolicyViolations(t *testing.T) {
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/internal/a.go", Total: 10, Covered: 9},
+				{File: "github.com/owner/repo/internal/b.go", Total: 10, Covered: 5},
+				{File: "github.com/owner/repo/internal/b_gen.go", Total: 10, Covered: 0},
+				{File: "github.com/owner/repo/cmd/root.go", Total: 10, Covered: 1},
+				{File: "github.com/owner/repo/cmd/root_gen.go", Total: 10, Covered: 0},
+			},
+		},
+	}
+	tests := []struct {
+		policy       string
+		want         []string
+		wantWarnings []string
+	}{
+		{"internal/** 80% @org/platform\n", []string{"github.com/owner/repo/internal/b.go (50.0%, accepted 80.0%) @org/platform", "github.com/owner/repo/internal/b_gen.go (0.0%, accepted 80.0%) @org/platform"}, []string{}},
+		{"cmd/*.go 10%\n", []string{"github.com/owner/repo/cmd/root_gen.go (0.0%, accepted 10.0%)"}, []string{}},
+		{"cmd/*.go 10%\n**/*_gen.go 10%\n", []string{"github.com/owner/repo/cmd/root_gen.go (0.0%, accepted 10.0%)", "github.com/owner/repo/internal/b_gen.go (0.0%, accepted 10.0%)"}, []string{}},
+		{"docs/** 100%\n", []string{}, []string{}},
+		// the example of CoveragePolicy: the last matching rule wins
+		{
+			"internal/** 80% @org/platform\n**/*_gen.go 0%\n",
+			[]string{"github.com/owner/repo/internal/b.go (50.0%, accepted 80.0%) @org/platform"},
+			[]string{"**/*_gen.go 0.0% (line 2) overrides internal/** 80.0% (line 1) for 1 files"},
+		},
+		{
+			"**/*_gen.go 0%\ninternal/** 80% @org/platform\n",
+			[]string{"github.com/owner/repo/internal/b.go (50.0%, accepted 80.0%) @org/platform", "github.com/owner/repo/internal/b_gen.go (0.0%, accepted 80.0%) @org/platform"},
+			[]string{"internal/** 80.0% (line 2) overrides **/*_gen.go 0.0% (line 1) for 1 files"},
+		},
+		{
+			"internal/** 80%\ninternal/** 40%\n",
+			[]string{"github.com/owner/repo/internal/b_gen.go (0.0%, accepted 40.0%)"},
+			[]string{"internal/** 40.0% (line 2) overrides internal/** 80.0% (line 1) for 3 files"},
+		},
+	}
+	for _, tt := range tests {
+		p, err := ParseCoveragePolicy(strings.NewReader(tt.policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		violations, warnings, err := r.PolicyViolations(p)
+		if err != nil {
+			t.Errorf("%q: %v", tt.policy, err)
+			continue
+		}
+		got := []string{}
+		for _, v := range violations {
+			got = append(got, v.String())
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+		if diff := cmp.Diff(warnings, tt.wantWarnings, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestCoverageTrend(t *testing.T) {
+	tests := []struct {
+		covered     int
+		prevCovered int
+		prev        bool
+		want        string
+	}{
+		{823, 810, true, "▲"},
+		{810, 823, true, "▼"},
+		{823, 823, true, ""},
+		{8231, 8230, true, ""},
+		{823, 810, false, ""},
+	}
+	for _, tt := range tests {
+		total := 1000
+		if tt.covered > total {
+			total = 10000
+		}
+		r := &Report{Coverage: &coverage.Coverage{Covered: tt.covered, Total: total}}
+		var prev *Report
+		if tt.prev {
+			prev = &Report{Coverage: &coverage.Coverage{Covered: tt.prevCovered, Total: total}}
+		}
+		if got := r.CoverageTrend(prev); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestPerFileViolations(t *testing.T) {
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/internal/a.go", Total: 10, Covered: 9},
+				{File: "github.com/owner/repo/legacy/b.go", Total: 10, Covered: 1},
+				{File: "github.com/owner/repo/cmd/root.go", Total: 10, Covered: 4},
+				{File: "github.com/owner/repo/doc.go", Total: 0, Covered: 0},
+			},
+		},
+	}
+	tests := []struct {
+		acceptable float64
+		exclude    []string
+		want       []string
+	}{
+		{50, nil, []string{"github.com/owner/repo/cmd/root.go (40.0%, accepted 50.0%)", "github.com/owner/repo/legacy/b.go (10.0%, accepted 50.0%)"}},
+		{50, []string{"legacy/**"}, []string{"github.com/owner/repo/cmd/root.go (40.0%, accepted 50.0%)"}},
+		{10, nil, []string{}},
+	}
+	for _, tt := range tests {
+		violations, err := r.PerFileViolations(tt.acceptable, tt.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, v := range violations {
+			got = append(got, v.String())
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestAddedLines(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{"@@ -1,3 +1,4 @@\n a\n-b\n+c\n+d\n e\n", []int{2, 3}, false},
+		{"@@ -10 +10,2 @@ func main() {\n x\n+y\n\\ No newline at end of file\n@@ -20,0 +21,1 @@\n+z\n", []int{11, 21}, false},
+		{"@@ -1,2 +1 @@\n-a\n b\n", []int{}, false},
+		{"@@ invalid @@\n+a\n", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := addedLines(tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%q: %v", tt.in, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%q: want err", tt.in)
+			continue
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestCodeToTestRatioByLanguage(t *testing.T) {
+	r := &Report{
+		CodeToTestRatio: &ratio.Ratio{
+			Code: 300,
+			Test: 240,
+			Languages: []*ratio.LanguageRatio{
+				{Name: "Go", Code: 100, Test: 200},
+				{Name: "JavaScript", Code: 200, Test: 40},
+			},
+		},
+	}
+	if got := len(r.CodeToTestRatioByLanguage()); got != 2 {
+		t.Errorf("got %v\nwant %v", got, 2)
+	}
+	buf := new(bytes.Buffer)
+	if err := r.Out(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Code to Test Ratio (Go)", "1:2.0", "Code to Test Ratio (JavaScript)", "1:0.2"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("got %v\nwant %v", buf.String(), want)
+		}
+	}
+	if got := len((&Report{}).CodeToTestRatioByLanguage()); got != 0 {
+		t.Errorf("got %v\nwant %v", got, 0)
+	}
+}
+
+func TestUncoveredLineRanges(t *testing.T) {
+	tests := []struct {
+		in   []int
+		want []*LineRange
+	}{
+		{[]int{}, []*LineRange{}},
+		{[]int{3}, []*LineRange{{Start: 3, End: 3}}},
+		{[]int{3, 4, 5, 8, 10, 11}, []*LineRange{{Start: 3, End: 5}, {Start: 8, End: 8}, {Start: 10, End: 11}}},
+	}
+	for _, tt := range tests {
+		pfc := &PatchFileCoverage{UncoveredLines: tt.in}
+		got := pfc.UncoveredLineRanges()
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Errorf("%s", diff)
+		}
+	}
+}
+
+func TestPatchCoverage(t *testing.T) {
+	line := func(n, count int) *coverage.BlockCoverage {
+		sl, el, c := n, n, count
+		return &coverage.BlockCoverage{Type: coverage.TypeLOC, StartLine: &sl, EndLine: &el, Count: &c}
+	}
+	a := coverage.NewFileCoverage("github.com/owner/repo/a.go")
+	a.Blocks = coverage.BlockCoverages{line(1, 1), line(2, 0), line(3, 1), line(4, 0)}
+	b := coverage.NewFileCoverage("github.com/owner/repo/b.go")
+	b.Blocks = coverage.BlockCoverages{line(1, 1)}
+	r := &Report{
+		Repository: "owner/repo",
+		Commit:     "abcdef",
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{a, b},
+		},
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "a.go", Status: "modified", Patch: "@@ -1,2 +1,5 @@\n a\n+b\n+c\n+d\n+e\n"},
+		{Filename: "b.go", Status: "modified", Patch: "@@ -1 +1,2 @@\n a\n+b\n"},
+		{Filename: "c.go", Status: "added", Patch: "@@ -0,0 +1 @@\n+a\n"},
+		{Filename: "README.md", Status: "modified", Patch: "@@ -1 +1 @@\n-a\n+b\n"},
+	}
+	got, err := r.PatchCoverage(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &PatchCoverage{
+		Repository: "owner/repo",
+		Commit:     "abcdef",
+		Total:      3,
+		Covered:    1,
+		Percent:    float64(1) / float64(3) * 100,
+		Files: []*PatchFileCoverage{
+			{File: "a.go", Total: 3, Covered: 1, Percent: float64(1) / float64(3) * 100, UncoveredLines: []int{2, 4}},
+		},
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Errorf("%s", diff)
+	}
+}
+
+func TestHandlePullRequestFiles(t *testing.T) {
+	a := &Report{
+		Coverage: &coverage.Coverage{
+			Total:   40,
+			Covered: 20,
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/kept.go", Total: 10, Covered: 10},
+				{File: "github.com/owner/repo/old.go", Total: 10, Covered: 10},
+				{File: "github.com/owner/repo/removed.go", Total: 20, Covered: 0},
+			},
+		},
+	}
+	b := &Report{
+		Coverage: &coverage.Coverage{
+			Total:   20,
+			Covered: 15,
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/kept.go", Total: 10, Covered: 10},
+				{File: "github.com/owner/repo/new.go", Total: 10, Covered: 5},
+			},
+		},
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "new.go", Status: "renamed", PreviousFilename: "old.go"},
+		{Filename: "removed.go", Status: "removed"},
+	}
+	tests := []struct {
+		exclude  bool
+		wantDiff float64
+		wantNote string
+	}{
+		{false, 25.0, "> **Note:** Removing 1 file ( 20 lines, 0 covered ) changes the coverage by +50.0%.\n"},
+		{true, -25.0, "> **Note:** Removing 1 file ( 20 lines, 0 covered ) changes the coverage by +50.0%. The coverage of the previous report excludes the removed files.\n"},
+	}
+	for _, tt := range tests {
+		d := a.Compare(b)
+		d.HandlePullRequestFiles(files, tt.exclude)
+		if d.Coverage.Diff != tt.wantDiff {
+			t.Errorf("got %v\nwant %v", d.Coverage.Diff, tt.wantDiff)
+		}
+		if got := d.RemovedFilesNote(); got != tt.wantNote {
+			t.Errorf("got %v\nwant %v", got, tt.wantNote)
+		}
+		dfc, err := d.Coverage.Files.FuzzyFindByFile("new.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dfc.Diff != -50.0 {
+			t.Errorf("got %v\nwant %v", dfc.Diff, -50.0)
+		}
+	}
+}
+
+func TestChangedFilesOnly(t *testing.T) {
+	a := &Report{
+		Coverage: &coverage.Coverage{
+			Total:   30,
+			Covered: 20,
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/changed.go", Total: 10, Covered: 5},
+				{File: "github.com/owner/repo/other.go", Total: 20, Covered: 15},
+			},
+		},
+	}
+	b := &Report{
+		Coverage: &coverage.Coverage{
+			Total:   30,
+			Covered: 25,
+			Files: coverage.FileCoverages{
+				{File: "github.com/owner/repo/changed.go", Total: 10, Covered: 10},
+				{File: "github.com/owner/repo/other.go", Total: 20, Covered: 15},
+			},
+		},
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "changed.go"},
+		{Filename: "repo/changed.go"},
+		{Filename: "README.md"},
+	}
+	got := b.ChangedFilesOnly(files)
+	if len(got.Coverage.Files) != 1 || got.Coverage.Files[0].File != "github.com/owner/repo/changed.go" {
+		t.Errorf("got %v\nwant %v", got.Coverage.Files, "github.com/owner/repo/changed.go")
+	}
+	if got.CoveragePercent() != b.CoveragePercent() {
+		t.Errorf("got %v\nwant %v", got.CoveragePercent(), b.CoveragePercent())
+	}
+	if len(b.Coverage.Files) != 2 {
+		t.Error("the original report should not be changed")
+	}
+
+	d := a.Compare(b)
+	gotd := d.ChangedFilesOnly(files)
+	if len(gotd.Coverage.Files) != 1 || gotd.Coverage.Files[0].File != "github.com/owner/repo/changed.go" {
+		t.Errorf("got %v\nwant %v", gotd.Coverage.Files, "github.com/owner/repo/changed.go")
+	}
+	if gotd.Coverage.Diff != d.Coverage.Diff {
+		t.Errorf("got %v\nwant %v", gotd.Coverage.Diff, d.Coverage.Diff)
+	}
+	if len(d.Coverage.Files) != 2 {
+		t.Error("the original diff report should not be changed")
+	}
+	if got := (&Report{}).ChangedFilesOnly(files); got.Coverage != nil {
+		t.Errorf("got %v\nwant %v", got.Coverage, nil)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	r := &Report{
+		Coverage:          &coverage.Coverage{Total: 1000, Covered: 741},
+		CodeToTestRatio:   &ratio.Ratio{Code: 100, Test: 80},
+		TestExecutionTime: f(float64(2*time.Minute + 3*time.Second + 400*time.Millisecond)),
+	}
+	prev := &Report{
+		Coverage:          &coverage.Coverage{Total: 1000, Covered: 729},
+		TestExecutionTime: f(float64(2*time.Minute + 10*time.Second)),
+	}
+	tests := []struct {
+		r    *Report
+		prev *Report
+		want string
+	}{
+		{r, nil, "coverage: 74.1% | ratio 1:0.8 | time 2m3s"},
+		{r, prev, "coverage: 74.1% (+1.2) | ratio 1:0.8 | time 2m3s (-7s)"},
+		{&Report{Coverage: &coverage.Coverage{Total: 10, Covered: 5}}, prev, "coverage: 50.0% (-22.9)"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Summary(tt.prev); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestGroupedFileCoveagesTable(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "reports", "k1LoW", "tbls", "report.json")
+	r := &Report{}
+	if err := r.MeasureCoverage(path); err != nil {
+		t.Fatal(err)
+	}
+	files := []*gh.PullRequestFile{}
+	for _, fc := range r.Coverage.Files {
+		files = append(files, &gh.PullRequestFile{Filename: fc.File, BlobURL: "https://github.com/owner/repo/blob/xxx/" + fc.File})
+	}
+	firstLine := func(s string) string {
+		return strings.SplitN(s, "\n", 2)[0]
+	}
+	for _, depth := range []int{1, 2} {
+		got := r.GroupedFileCoveagesTable(files, depth, FilesHideMin)
+		if want := r.FileCoveagesTable(files, FilesHideMin); firstLine(got) != firstLine(want) {
+			t.Errorf("got %v\nwant %v", firstLine(got), firstLine(want))
+		}
+		if c := strings.Count(got, "<details>"); c == 0 {
+			t.Errorf("got %v\nwant %v", c, "> 0")
+		}
+	}
+	if got := r.GroupedFileCoveagesTable([]*gh.PullRequestFile{}, 1, FilesHideMin); got != "" {
+		t.Errorf("got %v\nwant %v", got, "")
+	}
+
+	files = []*gh.PullRequestFile{&gh.PullRequestFile{Filename: "config/yaml.go", BlobURL: "https://github.com/owner/repo/blob/xxx/config/yaml.go"}}
+	want := `### Code coverage of files in pull request scope (41.7%)
+
+| Packages | Coverage |
+|----------|---------:|
+| config   | 41.7%    |
+
+<details><summary>config (41.7%)</summary>
+
+|                                  Files                                  | Coverage |
+|-------------------------------------------------------------------------|---------:|
+| [config/yaml.go](https://github.com/owner/repo/blob/xxx/config/yaml.go) | 41.7%    |
+
+</details>
+`
+	if got := r.GroupedFileCoveagesTable(files, 1, FilesHideMin); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+
+	// collapsed when there are more than hideMin files
+	got := r.GroupedFileCoveagesTable(files, 1, 0)
+	for _, want := range []string{"### Code coverage of files in pull request scope (41.7%)\n\n<details>\n\n| Packages |", "\n</details>\n\n</details>\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got\n%v\nwant\n%v", got, want)
+		}
+	}
+}
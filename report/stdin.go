@@ -0,0 +1,36 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// StdinPath is the path of the coverage report to read from stdin ( coverage.path: - )
+const StdinPath = "-"
+
+var stdin io.Reader = os.Stdin
+
+// readCoverageReportFromStdin buffers the coverage report of stdin to the temporary directory, so that the format is detected as the file
+func readCoverageReportFromStdin(in io.Reader, dir string) (string, error) {
+	p := filepath.Join(dir, "coverage")
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	n, err := io.Copy(f, io.LimitReader(in, maxDownloadSize+1))
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "", fmt.Errorf("coverage report not found: %s", "stdin")
+	}
+	if n > maxDownloadSize {
+		return "", fmt.Errorf("failed to read coverage report from stdin: exceeds the limit of %d bytes", maxDownloadSize)
+	}
+	return p, nil
+}
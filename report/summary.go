@@ -0,0 +1,57 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/pkg/loc"
+)
+
+// summaryTimeFormat is the format of test execution time in the summary when the format is not set
+var summaryTimeFormat = &DurationFormat{Unit: DurationUnitAuto, Precision: 0}
+
+// Summary returns the one-line summary of the measured metrics ( e.g. `coverage: 74.1% (+1.2) | ratio 1:0.8 | time 2m3s` ).
+// The deltas are included when the previous report ( prev ) has the metrics. The format is kept stable for parsing.
+func (r *Report) Summary(prev *Report) string {
+	parts := []string{}
+	if r.IsMeasuredCoverage() {
+		s := fmt.Sprintf("coverage: %.1f%%", r.CoveragePercent())
+		if prev != nil && prev.IsMeasuredCoverage() {
+			s = fmt.Sprintf("%s (%+.1f)", s, r.CoveragePercent()-prev.CoveragePercent())
+		}
+		parts = append(parts, s)
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		s := fmt.Sprintf("ratio 1:%.1f", r.CodeToTestRatioRatio())
+		if prev != nil && prev.IsMeasuredCodeToTestRatio() {
+			s = fmt.Sprintf("%s (%+.1f)", s, r.CodeToTestRatioRatio()-prev.CodeToTestRatioRatio())
+		}
+		parts = append(parts, s)
+	}
+	if r.IsMeasuredLinesOfCode() {
+		s := fmt.Sprintf("loc %s", loc.Format(r.LinesOfCode.Code))
+		if prev != nil && prev.IsMeasuredLinesOfCode() {
+			s = fmt.Sprintf("%s (%+d)", s, r.LinesOfCode.Code-prev.LinesOfCode.Code)
+		}
+		parts = append(parts, s)
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		f := r.timeFormat
+		if f == nil {
+			f = summaryTimeFormat
+		}
+		s := fmt.Sprintf("time %s", f.Format(time.Duration(*r.TestExecutionTime)))
+		if prev != nil && prev.IsMeasuredTestExecutionTime() {
+			d := time.Duration(*r.TestExecutionTime - *prev.TestExecutionTime)
+			sign := "+"
+			if d < 0 {
+				sign = "-"
+				d = -d
+			}
+			s = fmt.Sprintf("%s (%s%s)", s, sign, f.Format(d))
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " | ")
+}